@@ -0,0 +1,450 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reposerver serves a directory of packaged charts as a chart
+// repository, for 'helm repo serve'.
+package reposerver
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"helm.sh/helm/v4/internal/fileutil"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	"helm.sh/helm/v4/pkg/repo"
+)
+
+// maxUploadSize is the maximum size of a chart, with its provenance file,
+// uploaded to a Server.
+const maxUploadSize = 20 * 1024 * 1024
+
+// Server serves a directory of packaged charts as a chart repository.
+//
+// The index of the repository is regenerated when the charts in the directory
+// change. When uploads are enabled, charts can also be uploaded, listed and
+// deleted through an API compatible with the one of ChartMuseum:
+//
+//	POST   /api/charts                    upload a chart, or with a multipart
+//	                                      form, a chart and its provenance file
+//	GET    /api/charts                    list the charts
+//	GET    /api/charts/<name>             list the versions of a chart
+//	GET    /api/charts/<name>/<version>   describe a version of a chart
+//	DELETE /api/charts/<name>/<version>   delete a version of a chart
+type Server struct {
+	dir      string
+	url      string
+	upload   bool
+	username string
+	password string
+	sharded  bool
+
+	mu    sync.Mutex
+	state string
+	index *repo.IndexFile
+	files http.Handler
+	api   http.Handler
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithURL sets the URL of the repository in the URLs of the charts in its
+// index. By default, the URLs are relative to the repository.
+func WithURL(url string) Option {
+	return func(s *Server) {
+		s.url = url
+	}
+}
+
+// WithUploads enables the API to upload and delete charts. When a username is
+// given, the API requires basic authentication with the username and password.
+func WithUploads(username, password string) Option {
+	return func(s *Server) {
+		s.upload = true
+		s.username = username
+		s.password = password
+	}
+}
+
+// WithShardedIndex also generates a sharded index of the repository.
+func WithShardedIndex() Option {
+	return func(s *Server) {
+		s.sharded = true
+	}
+}
+
+// New returns a Server for a directory of packaged charts. The index of the
+// repository is generated right away.
+func New(dir string, options ...Option) (*Server, error) {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("%q is not a directory", dir)
+	}
+
+	s := &Server{
+		dir:   dir,
+		files: http.FileServer(http.Dir(dir)),
+	}
+	for _, option := range options {
+		option(s)
+	}
+
+	api := http.NewServeMux()
+	api.HandleFunc("POST /api/charts", s.uploadChart)
+	api.HandleFunc("GET /api/charts", s.listCharts)
+	api.HandleFunc("GET /api/charts/{name}", s.listChartVersions)
+	api.HandleFunc("GET /api/charts/{name}/{version}", s.describeChartVersion)
+	api.HandleFunc("DELETE /api/charts/{name}/{version}", s.deleteChartVersion)
+	s.api = api
+
+	if _, err := s.Reindex(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reindex regenerates the index of the repository if the charts in its
+// directory changed since it was last generated, and returns it.
+func (s *Server) Reindex() (*repo.IndexFile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reindex()
+}
+
+// reindex regenerates the index of the repository, as Reindex does. It
+// requires the lock of the server.
+func (s *Server) reindex() (*repo.IndexFile, error) {
+	state, err := s.chartsState()
+	if err != nil {
+		return nil, err
+	}
+	if s.index != nil && state == s.state {
+		if _, err := os.Stat(filepath.Join(s.dir, "index.yaml")); err == nil {
+			return s.index, nil
+		}
+	}
+
+	index, err := repo.IndexDirectory(s.dir, s.url)
+	if err != nil {
+		return nil, err
+	}
+	index.SortEntries()
+	if s.sharded {
+		if err := index.WriteShardedIndex(filepath.Join(s.dir, repo.ShardedIndexDir), 0644); err != nil {
+			return nil, err
+		}
+	}
+	if err := index.WriteFile(filepath.Join(s.dir, "index.yaml"), 0644); err != nil {
+		return nil, err
+	}
+	slog.Debug("regenerated the index of the repository", "dir", s.dir)
+	s.state = state
+	s.index = index
+	return index, nil
+}
+
+// chartsState describes the packaged charts in the directory of the
+// repository, so that their changes can be detected.
+func (s *Server) chartsState() (string, error) {
+	archives, err := filepath.Glob(filepath.Join(s.dir, "*.tgz"))
+	if err != nil {
+		return "", err
+	}
+	moreArchives, err := filepath.Glob(filepath.Join(s.dir, "**/*.tgz"))
+	if err != nil {
+		return "", err
+	}
+	archives = append(archives, moreArchives...)
+	sort.Strings(archives)
+
+	var state strings.Builder
+	for _, arch := range archives {
+		fi, err := os.Stat(arch)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return "", err
+		}
+		fmt.Fprintf(&state, "%s %d %d\n", arch, fi.Size(), fi.ModTime().UnixNano())
+	}
+	return state.String(), nil
+}
+
+// ServeHTTP serves the files of the repository and its API.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api" || strings.HasPrefix(r.URL.Path, "/api/") {
+		if !s.upload {
+			http.NotFound(w, r)
+			return
+		}
+		if !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="helm"`)
+			writeServerError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+			return
+		}
+		s.api.ServeHTTP(w, r)
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.servable(r.URL.Path) {
+		http.NotFound(w, r)
+		return
+	}
+	if r.URL.Path == "/index.yaml" || strings.HasPrefix(r.URL.Path, "/"+repo.ShardedIndexDir+"/") {
+		if _, err := s.Reindex(); err != nil {
+			slog.Error("failed to regenerate the index of the repository", slog.Any("error", err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+	s.files.ServeHTTP(w, r)
+}
+
+// servable returns whether a file of the repository is served: its index,
+// its sharded index, and the chart archives and provenance files. Directories
+// and hidden files are never served.
+func (s *Server) servable(urlPath string) bool {
+	name := strings.TrimPrefix(urlPath, "/")
+	for _, elem := range strings.Split(name, "/") {
+		if elem == "" || strings.HasPrefix(elem, ".") {
+			return false
+		}
+	}
+	switch ext := path.Ext(name); {
+	case name == "index.yaml":
+	case strings.HasPrefix(name, repo.ShardedIndexDir+"/"):
+		if !s.sharded || ext != ".yaml" {
+			return false
+		}
+	case ext != ".tgz" && ext != ".prov":
+		return false
+	}
+	fi, err := os.Stat(filepath.Join(s.dir, filepath.FromSlash(name)))
+	return err == nil && fi.Mode().IsRegular()
+}
+
+// authorized returns whether a request to the API is authorized.
+func (s *Server) authorized(r *http.Request) bool {
+	if s.username == "" {
+		return true
+	}
+	username, password, ok := r.BasicAuth()
+	return ok &&
+		subtle.ConstantTimeCompare([]byte(username), []byte(s.username)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(password), []byte(s.password)) == 1
+}
+
+// uploadChart saves an uploaded chart, and its provenance file if any. An
+// existing version of a chart is only replaced with the force parameter.
+func (s *Server) uploadChart(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	chartData, provData, err := readUpload(r)
+	if err != nil {
+		writeServerError(w, http.StatusBadRequest, err)
+		return
+	}
+	ch, err := loader.LoadArchive(bytes.NewReader(chartData))
+	if err != nil {
+		writeServerError(w, http.StatusBadRequest, fmt.Errorf("invalid chart: %w", err))
+		return
+	}
+	fname := ch.Metadata.Name + "-" + ch.Metadata.Version + ".tgz"
+	if filepath.Base(fname) != fname || !filepath.IsLocal(fname) {
+		writeServerError(w, http.StatusBadRequest, fmt.Errorf("invalid chart name %q", ch.Metadata.Name))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dest := filepath.Join(s.dir, fname)
+	if _, err := os.Stat(dest); err == nil && !r.URL.Query().Has("force") {
+		writeServerError(w, http.StatusConflict, errors.New("file already exists"))
+		return
+	}
+	if err := fileutil.AtomicWriteFile(dest, bytes.NewReader(chartData), 0644); err != nil {
+		writeServerError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if provData != nil {
+		if err := fileutil.AtomicWriteFile(dest+".prov", bytes.NewReader(provData), 0644); err != nil {
+			writeServerError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	if _, err := s.reindex(); err != nil {
+		writeServerError(w, http.StatusInternalServerError, err)
+		return
+	}
+	slog.Info("uploaded chart", "chart", ch.Metadata.Name, "version", ch.Metadata.Version)
+	writeServerJSON(w, http.StatusCreated, map[string]bool{"saved": true})
+}
+
+// readUpload reads an uploaded chart, either from the body of a request, or
+// with its provenance file from a multipart form.
+func readUpload(r *http.Request) (chartData, provData []byte, err error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		chartData, err = io.ReadAll(r.Body)
+		return chartData, nil, err
+	}
+
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		return nil, nil, err
+	}
+	readFile := func(field string) ([]byte, error) {
+		f, _, err := r.FormFile(field)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}
+	chartData, err = readFile("chart")
+	if err != nil {
+		return nil, nil, fmt.Errorf("missing chart: %w", err)
+	}
+	provData, err = readFile("prov")
+	if err != nil && !errors.Is(err, http.ErrMissingFile) {
+		return nil, nil, err
+	}
+	return chartData, provData, nil
+}
+
+// listCharts lists the versions of all the charts.
+func (s *Server) listCharts(w http.ResponseWriter, _ *http.Request) {
+	index, err := s.Reindex()
+	if err != nil {
+		writeServerError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeServerJSON(w, http.StatusOK, index.Entries)
+}
+
+// listChartVersions lists the versions of a chart.
+func (s *Server) listChartVersions(w http.ResponseWriter, r *http.Request) {
+	index, err := s.Reindex()
+	if err != nil {
+		writeServerError(w, http.StatusInternalServerError, err)
+		return
+	}
+	cvs, ok := index.Entries[r.PathValue("name")]
+	if !ok {
+		writeServerError(w, http.StatusNotFound, errors.New("chart not found"))
+		return
+	}
+	writeServerJSON(w, http.StatusOK, cvs)
+}
+
+// describeChartVersion describes a version of a chart.
+func (s *Server) describeChartVersion(w http.ResponseWriter, r *http.Request) {
+	index, err := s.Reindex()
+	if err != nil {
+		writeServerError(w, http.StatusInternalServerError, err)
+		return
+	}
+	cv, ok := findChartVersion(index, r.PathValue("name"), r.PathValue("version"))
+	if !ok {
+		writeServerError(w, http.StatusNotFound, errors.New("chart version not found"))
+		return
+	}
+	writeServerJSON(w, http.StatusOK, cv)
+}
+
+// deleteChartVersion deletes a version of a chart, and its provenance file.
+func (s *Server) deleteChartVersion(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index, err := s.reindex()
+	if err != nil {
+		writeServerError(w, http.StatusInternalServerError, err)
+		return
+	}
+	cv, ok := findChartVersion(index, r.PathValue("name"), r.PathValue("version"))
+	if !ok || len(cv.URLs) == 0 {
+		writeServerError(w, http.StatusNotFound, errors.New("chart not found"))
+		return
+	}
+	// The URLs of the charts are relative to the directory of the repository
+	// when they were generated without a server URL.
+	fname := filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(cv.URLs[0], s.url), "/"))
+	if !filepath.IsLocal(fname) {
+		writeServerError(w, http.StatusInternalServerError, fmt.Errorf("cannot delete %s", cv.URLs[0]))
+		return
+	}
+	archive := filepath.Join(s.dir, fname)
+	if err := os.Remove(archive); err != nil {
+		writeServerError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := os.Remove(archive + ".prov"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		writeServerError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if _, err := s.reindex(); err != nil {
+		writeServerError(w, http.StatusInternalServerError, err)
+		return
+	}
+	slog.Info("deleted chart", "chart", cv.Name, "version", cv.Version)
+	writeServerJSON(w, http.StatusOK, map[string]bool{"deleted": true})
+}
+
+// findChartVersion finds the exact version of a chart in an index.
+func findChartVersion(index *repo.IndexFile, name, version string) (*repo.ChartVersion, bool) {
+	for _, cv := range index.Entries[name] {
+		if cv.Version == version {
+			return cv, true
+		}
+	}
+	return nil, false
+}
+
+// writeServerJSON writes a JSON response of the API.
+func writeServerJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Debug("failed to write response", slog.Any("error", err))
+	}
+}
+
+// writeServerError writes an error response of the API, in the format of
+// ChartMuseum.
+func writeServerError(w http.ResponseWriter, status int, err error) {
+	writeServerJSON(w, status, map[string]string{"error": err.Error()})
+}
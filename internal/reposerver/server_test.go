@@ -0,0 +1,224 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reposerver
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/cli"
+	"helm.sh/helm/v4/pkg/getter"
+	"helm.sh/helm/v4/pkg/repo"
+)
+
+// packageTestChart packages a chart, copies the archive to dir unless it is
+// empty, and returns the archive.
+func packageTestChart(t *testing.T, name, version, dir string) []byte {
+	t.Helper()
+	ch := &chart.Chart{Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: name, Version: version}}
+	archive, err := chartutil.Save(ch, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != "" {
+		if err := os.WriteFile(filepath.Join(dir, filepath.Base(archive)), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return data
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	packageTestChart(t, "sprocket", "1.1.0", dir)
+
+	s, err := New(dir, WithShardedIndex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	chartURL, err := repo.FindChartInRepoURL(srv.URL, "sprocket", getter.All(&cli.EnvSettings{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chartURL != srv.URL+"/sprocket-1.1.0.tgz" {
+		t.Errorf("unexpected chart URL %s", chartURL)
+	}
+	if _, err := os.Stat(filepath.Join(dir, repo.ShardedIndexDir, "index.yaml")); err != nil {
+		t.Errorf("expected a sharded index: %s", err)
+	}
+
+	// The index is regenerated when the charts change.
+	packageTestChart(t, "sprocket", "1.2.0", dir)
+	chartURL, err = repo.FindChartInRepoURL(srv.URL, "sprocket", getter.All(&cli.EnvSettings{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chartURL != srv.URL+"/sprocket-1.2.0.tgz" {
+		t.Errorf("unexpected chart URL %s", chartURL)
+	}
+
+	// Uploads are disabled by default.
+	resp, err := http.Post(srv.URL+"/api/charts", "application/octet-stream", bytes.NewReader(packageTestChart(t, "frobnitz", "1.2.3", "")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+func TestServerFiles(t *testing.T) {
+	dir := t.TempDir()
+	packageTestChart(t, "sprocket", "1.1.0", dir)
+	for _, name := range []string{".secret", "notes.txt", "sub/.hidden.tgz", "dir.tgz/chart.yaml"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("private"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	for path, status := range map[string]int{
+		"/index.yaml":            http.StatusOK,
+		"/sprocket-1.1.0.tgz":    http.StatusOK,
+		"/":                      http.StatusNotFound,
+		"/.secret":               http.StatusNotFound,
+		"/notes.txt":             http.StatusNotFound,
+		"/sub/":                  http.StatusNotFound,
+		"/sub/.hidden.tgz":       http.StatusNotFound,
+		"/dir.tgz":               http.StatusNotFound,
+		"/index.d/index.yaml":    http.StatusNotFound,
+		"/missing-0.1.0.tgz":     http.StatusNotFound,
+		"/../sprocket-1.1.0.tgz": http.StatusNotFound,
+	} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("%s: expected status %d, got %d", path, status, resp.StatusCode)
+		}
+	}
+}
+
+func TestServerUploads(t *testing.T) {
+	dir := t.TempDir()
+	s, err := New(dir, WithUploads("helm", "secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	request := func(method, path, contentType string, body []byte, auth bool) int {
+		t.Helper()
+		req, err := http.NewRequest(method, srv.URL+path, bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if auth {
+			req.SetBasicAuth("helm", "secret")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	chartData := packageTestChart(t, "frobnitz", "1.2.3", "")
+	if status := request(http.MethodPost, "/api/charts", "application/octet-stream", chartData, false); status != http.StatusUnauthorized {
+		t.Errorf("expected status %d without credentials, got %d", http.StatusUnauthorized, status)
+	}
+	if status := request(http.MethodPost, "/api/charts", "application/octet-stream", chartData, true); status != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, status)
+	}
+	if status := request(http.MethodPost, "/api/charts", "application/octet-stream", chartData, true); status != http.StatusConflict {
+		t.Errorf("expected status %d uploading an existing chart, got %d", http.StatusConflict, status)
+	}
+	if status := request(http.MethodPost, "/api/charts", "application/octet-stream", []byte("not a chart"), true); status != http.StatusBadRequest {
+		t.Errorf("expected status %d uploading an invalid chart, got %d", http.StatusBadRequest, status)
+	}
+
+	// A chart and its provenance file are uploaded with a multipart form.
+	var form bytes.Buffer
+	mw := multipart.NewWriter(&form)
+	for field, data := range map[string][]byte{"chart": chartData, "prov": []byte("provenance")} {
+		fw, err := mw.CreateFormFile(field, field)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write(data)
+	}
+	mw.Close()
+	if status := request(http.MethodPost, "/api/charts?force", mw.FormDataContentType(), form.Bytes(), true); status != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, status)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "frobnitz-1.2.3.tgz.prov")); err != nil {
+		t.Errorf("expected the provenance file to be saved: %s", err)
+	}
+
+	index, err := s.Reindex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !index.Has("frobnitz", "1.2.3") {
+		t.Error("expected the uploaded chart in the index")
+	}
+	if status := request(http.MethodGet, "/api/charts/frobnitz/1.2.3", "", nil, true); status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, status)
+	}
+
+	if status := request(http.MethodDelete, "/api/charts/frobnitz/1.2.3", "", nil, true); status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, status)
+	}
+	for _, f := range []string{"frobnitz-1.2.3.tgz", "frobnitz-1.2.3.tgz.prov"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be deleted, got %v", f, err)
+		}
+	}
+	if status := request(http.MethodGet, "/api/charts/frobnitz", "", nil, true); status != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, status)
+	}
+}
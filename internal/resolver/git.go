@@ -0,0 +1,202 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	stdfs "io/fs"
+	"log/slog"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/Masterminds/vcs"
+
+	"helm.sh/helm/v4/internal/vcsutil"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	"helm.sh/helm/v4/pkg/plugin/cache"
+)
+
+// gitSchemePrefix prefixes the repository of dependencies that are fetched
+// from a Git repository, e.g. "git+https://".
+const gitSchemePrefix = "git+"
+
+// IsGitRepository reports whether the repository of a dependency is a Git
+// repository.
+func IsGitRepository(repo string) bool {
+	for _, scheme := range []string{"https", "http", "ssh", "file"} {
+		if strings.HasPrefix(repo, gitSchemePrefix+scheme+"://") {
+			return true
+		}
+	}
+	return false
+}
+
+// GitSource is the location of a chart in a Git repository.
+//
+// It is parsed from a dependency repository of the form
+//
+//	git+https://example.com/charts.git//path/to/chart?ref=v1.0.0
+//
+// where the path to the chart and the ref are optional.
+type GitSource struct {
+	// Remote is the URL of the Git repository.
+	Remote string
+	// Path is the path of the chart in the repository.
+	Path string
+	// Ref is a branch, tag or commit to check out. If it is empty, the tag
+	// matching the version constraint of the dependency is used.
+	Ref string
+}
+
+// ParseGitSource parses the repository of a dependency from a Git repository.
+func ParseGitSource(repo string) (*GitSource, error) {
+	if !IsGitRepository(repo) {
+		return nil, fmt.Errorf("%s is not a Git repository", repo)
+	}
+	u, err := url.Parse(strings.TrimPrefix(repo, gitSchemePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Git repository %s: %w", repo, err)
+	}
+	src := &GitSource{Ref: u.Query().Get("ref")}
+	u.RawQuery = ""
+	u.Fragment = ""
+	if repoPath, chartPath, ok := strings.Cut(u.Path, "//"); ok {
+		u.Path = repoPath
+		src.Path = path.Clean(chartPath)
+		if src.Path == ".." || strings.HasPrefix(src.Path, "../") {
+			return nil, fmt.Errorf("invalid Git repository %s: the chart path must be inside the repository", repo)
+		}
+	}
+	u.RawPath = ""
+	src.Remote = u.String()
+	return src, nil
+}
+
+// checkout clones or updates the Git repository into the cache and checks out
+// the given revision. It returns the checked out repository.
+func (s *GitSource) checkout(cachepath, rev string) (vcs.Repo, error) {
+	key, err := cache.Key(s.Remote)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := vcs.NewGitRepo(s.Remote, filepath.Join(cachepath, "git", key))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(repo.LocalPath()); errors.Is(err, stdfs.ErrNotExist) {
+		slog.Debug("cloning", "source", repo.Remote(), "destination", repo.LocalPath())
+		if err := repo.Get(); err != nil {
+			return nil, err
+		}
+	} else if err := repo.Update(); err != nil {
+		return nil, err
+	}
+	if rev != "" {
+		if err := repo.UpdateVersion(rev); err != nil {
+			return nil, err
+		}
+		// Pull the latest commits when a branch is checked out.
+		if err := repo.Update(); err != nil {
+			return nil, err
+		}
+	}
+	return repo, nil
+}
+
+// GitCheckout checks out the chart of a dependency from a Git repository and
+// returns the path to the chart.
+//
+// The commit the dependency is locked to is checked out, and it is an error
+// if the repository does not have it. Dependencies that are not locked check
+// out the ref of their repository.
+func GitCheckout(dep *chart.Dependency, cachepath string) (string, error) {
+	src, err := ParseGitSource(dep.Repository)
+	if err != nil {
+		return "", err
+	}
+	rev := dep.Commit
+	if rev == "" {
+		rev = src.Ref
+	}
+	repo, err := src.checkout(cachepath, rev)
+	if err != nil {
+		return "", fmt.Errorf("could not check out %s: %w", dep.Repository, err)
+	}
+	if dep.Commit != "" {
+		commit, err := repo.Version()
+		if err != nil {
+			return "", err
+		}
+		if commit != dep.Commit {
+			return "", fmt.Errorf("checked out commit %s of %s, but the lock file expects %s", commit, dep.Repository, dep.Commit)
+		}
+	}
+	return filepath.Join(repo.LocalPath(), filepath.FromSlash(src.Path)), nil
+}
+
+// resolveGit locks a dependency from a Git repository to a commit.
+//
+// The ref of the repository is checked out if it is set. Otherwise the highest
+// semver tag that satisfies the version constraint is used. It returns nil if
+// no chart satisfies the constraint.
+func (r *Resolver) resolveGit(d *chart.Dependency, constraint *semver.Constraints) (*chart.Dependency, error) {
+	src, err := ParseGitSource(d.Repository)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := src.checkout(r.cachepath, src.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("could not check out %s: %w", d.Repository, err)
+	}
+	if src.Ref == "" {
+		tag, err := vcsutil.SolveTag(repo, constraint)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve list of tags for repository %s: %w", d.Repository, err)
+		}
+		if tag == "" {
+			return nil, nil
+		}
+		if err := repo.UpdateVersion(tag); err != nil {
+			return nil, err
+		}
+	}
+	commit, err := repo.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := loader.LoadDir(filepath.Join(repo.LocalPath(), filepath.FromSlash(src.Path)))
+	if err != nil {
+		return nil, fmt.Errorf("could not load chart %s from %s: %w", d.Name, d.Repository, err)
+	}
+	v, err := semver.NewVersion(ch.Metadata.Version)
+	if err != nil || !constraint.Check(v) {
+		return nil, nil
+	}
+	return &chart.Dependency{
+		Name:       d.Name,
+		Repository: d.Repository,
+		Version:    ch.Metadata.Version,
+		Commit:     commit,
+	}, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"helm.sh/helm/v4/internal/test/ensure"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+func TestParseGitSource(t *testing.T) {
+	tests := []struct {
+		repo   string
+		expect GitSource
+		err    bool
+	}{
+		{
+			repo:   "git+https://example.com/charts.git",
+			expect: GitSource{Remote: "https://example.com/charts.git"},
+		},
+		{
+			repo:   "git+https://example.com/charts.git//charts/library?ref=v1.0.0",
+			expect: GitSource{Remote: "https://example.com/charts.git", Path: "charts/library", Ref: "v1.0.0"},
+		},
+		{
+			repo:   "git+ssh://git@example.com/org/charts.git//library",
+			expect: GitSource{Remote: "ssh://git@example.com/org/charts.git", Path: "library"},
+		},
+		{
+			repo:   "git+file:///srv/charts//library?ref=main",
+			expect: GitSource{Remote: "file:///srv/charts", Path: "library", Ref: "main"},
+		},
+		{
+			repo: "git+https://example.com/charts.git//../outside",
+			err:  true,
+		},
+		{
+			repo: "https://example.com/charts",
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			src, err := ParseGitSource(tt.repo)
+			if tt.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *src != tt.expect {
+				t.Errorf("expected %+v, got %+v", tt.expect, *src)
+			}
+		})
+	}
+}
+
+func TestResolveGit(t *testing.T) {
+	repoDir := ensure.GitRepo(t, "1.0.0", "1.1.0", "2.0.0")
+	repo := "git+file://" + filepath.ToSlash(repoDir) + "//charts/library"
+
+	tests := []struct {
+		name   string
+		dep    *chart.Dependency
+		expect *chart.Dependency
+		err    bool
+	}{
+		{
+			name:   "highest tag matching the constraint",
+			dep:    &chart.Dependency{Name: "library", Repository: repo, Version: "^1.0.0"},
+			expect: &chart.Dependency{Name: "library", Repository: repo, Version: "1.1.0", Commit: ensure.GitRevParse(t, repoDir, "v1.1.0")},
+		},
+		{
+			name:   "ref",
+			dep:    &chart.Dependency{Name: "library", Repository: repo + "?ref=v1.0.0", Version: "1.x"},
+			expect: &chart.Dependency{Name: "library", Repository: repo + "?ref=v1.0.0", Version: "1.0.0", Commit: ensure.GitRevParse(t, repoDir, "v1.0.0")},
+		},
+		{
+			name:   "branch",
+			dep:    &chart.Dependency{Name: "library", Repository: repo + "?ref=main", Version: ">=2.0.0"},
+			expect: &chart.Dependency{Name: "library", Repository: repo + "?ref=main", Version: "2.0.0", Commit: ensure.GitRevParse(t, repoDir, "main")},
+		},
+		{
+			name: "no tag matching the constraint",
+			dep:  &chart.Dependency{Name: "library", Repository: repo, Version: "^3.0.0"},
+			err:  true,
+		},
+		{
+			name: "ref not matching the constraint",
+			dep:  &chart.Dependency{Name: "library", Repository: repo + "?ref=v2.0.0", Version: "^1.0.0"},
+			err:  true,
+		},
+	}
+
+	r := New("testdata/chartpath", t.TempDir(), nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := r.Resolve([]*chart.Dependency{tt.dep}, map[string]string{})
+			if tt.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := l.Dependencies[0]; !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("expected %+v, got %+v", *tt.expect, *got)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+// ociRepository returns the repository of the chart of a dependency from an
+// OCI registry, without the oci:// scheme, and the digest the dependency is
+// pinned to in Chart.yaml, if any.
+//
+// The repository of a dependency is the path the chart is found below, e.g.
+// oci://registry.example.com/charts. A pinned dependency refers to the chart
+// itself, e.g. oci://registry.example.com/charts/mychart@sha256:...
+func ociRepository(dep *chart.Dependency) (string, string) {
+	repository := strings.TrimPrefix(dep.Repository, registry.OCIScheme+"://")
+	repository, digest, _ := strings.Cut(repository, "@")
+	repository = strings.TrimSuffix(repository, "/")
+	if digest == "" || path.Base(repository) != dep.Name {
+		repository = repository + "/" + dep.Name
+	}
+	return repository, digest
+}
+
+// OCIChartRef returns the oci:// reference a dependency from an OCI registry
+// is pulled from.
+//
+// Dependencies locked to a manifest are pulled by its digest, so that pushing
+// the tag of the version again does not change the chart that is pulled.
+func OCIChartRef(dep *chart.Dependency) string {
+	repository, digest := ociRepository(dep)
+	if dep.ManifestDigest != "" {
+		digest = dep.ManifestDigest
+	}
+	if digest != "" {
+		return fmt.Sprintf("%s://%s@%s", registry.OCIScheme, repository, digest)
+	}
+	return fmt.Sprintf("%s://%s:%s", registry.OCIScheme, repository, dep.Version)
+}
+
+// resolveOCI locks a dependency from an OCI registry to the digest of a
+// manifest.
+//
+// A dependency pinned to a digest in Chart.yaml is locked to it. Otherwise the
+// explicit version or the highest semver tag that satisfies the version
+// constraint is used. It returns nil if no chart satisfies the constraint.
+func (r *Resolver) resolveOCI(d *chart.Dependency, constraint *semver.Constraints) (*chart.Dependency, error) {
+	if r.registryClient == nil {
+		return nil, fmt.Errorf("could not resolve %s: a registry client is required to resolve dependencies from OCI registries", d.Repository)
+	}
+	repository, digest := ociRepository(d)
+	if digest != "" {
+		meta, err := r.registryClient.ChartMetadata(repository + "@" + digest)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve %s: %w", d.Repository, err)
+		}
+		v, err := semver.NewVersion(meta.Version)
+		if err != nil || !constraint.Check(v) {
+			return nil, nil
+		}
+		return &chart.Dependency{
+			Name:           d.Name,
+			Repository:     d.Repository,
+			Version:        meta.Version,
+			ManifestDigest: digest,
+		}, nil
+	}
+
+	// Check to see if an explicit version has been provided, otherwise search
+	// for tags
+	version := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(d.Version), "="))
+	if _, err := semver.NewVersion(version); err != nil {
+		tags, err := r.registryClient.Tags(repository)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve list of tags for repository %s: %w", d.Repository, err)
+		}
+		version = ""
+		// The tags are already sorted and hence the first one to satisfy the
+		// constraint is used
+		for _, tag := range tags {
+			v, err := semver.NewVersion(tag)
+			if err == nil && constraint.Check(v) {
+				version = v.Original()
+				break
+			}
+		}
+		if version == "" {
+			return nil, nil
+		}
+	}
+
+	desc, err := r.registryClient.Resolve(repository + ":" + version)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve %s:%s: %w", repository, version, err)
+	}
+	return &chart.Dependency{
+		Name:           d.Name,
+		Repository:     d.Repository,
+		Version:        version,
+		ManifestDigest: desc.Digest.String(),
+	}, nil
+}
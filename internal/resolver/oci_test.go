@@ -0,0 +1,155 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/repo/repotest"
+)
+
+func TestOCIChartRef(t *testing.T) {
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	tests := []struct {
+		name   string
+		dep    *chart.Dependency
+		expect string
+	}{
+		{
+			name:   "tag",
+			dep:    &chart.Dependency{Name: "mychart", Version: "1.2.3", Repository: "oci://example.com/charts/"},
+			expect: "oci://example.com/charts/mychart:1.2.3",
+		},
+		{
+			name:   "locked",
+			dep:    &chart.Dependency{Name: "mychart", Version: "1.2.3", Repository: "oci://example.com/charts", ManifestDigest: digest},
+			expect: "oci://example.com/charts/mychart@" + digest,
+		},
+		{
+			name:   "pinned in Chart.yaml",
+			dep:    &chart.Dependency{Name: "mychart", Version: "1.2.3", Repository: "oci://example.com/charts/mychart@" + digest},
+			expect: "oci://example.com/charts/mychart@" + digest,
+		},
+		{
+			name:   "pinned repository",
+			dep:    &chart.Dependency{Name: "mychart", Version: "1.2.3", Repository: "oci://example.com/charts@" + digest},
+			expect: "oci://example.com/charts/mychart@" + digest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OCIChartRef(tt.dep); got != tt.expect {
+				t.Errorf("expected %s, got %s", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestResolveOCI(t *testing.T) {
+	dir := t.TempDir()
+	data, err := os.ReadFile("../../pkg/cmd/testdata/testcharts/oci-dependent-chart-0.1.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "oci-dependent-chart-0.1.0.tgz"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	srv, err := repotest.NewOCIServer(t, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Run(t)
+
+	digests := map[string]string{}
+	for _, version := range []string{"0.1.0", "0.2.0"} {
+		ch := &chart.Chart{Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "mychart", Version: version}}
+		archive, err := chartutil.Save(ch, t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(archive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := srv.Client.Push(data, fmt.Sprintf("%s/charts/mychart:%s", srv.RegistryURL, version))
+		if err != nil {
+			t.Fatal(err)
+		}
+		digests[version] = result.Manifest.Digest
+	}
+	repository := "oci://" + srv.RegistryURL + "/charts"
+
+	tests := []struct {
+		name       string
+		repository string
+		version    string
+		expect     string
+	}{
+		{name: "exact version", repository: repository, version: "0.1.0", expect: "0.1.0"},
+		{name: "exact version with an operator", repository: repository, version: "=0.1.0", expect: "0.1.0"},
+		{name: "constraint", repository: repository, version: ">=0.1.0", expect: "0.2.0"},
+		{name: "unsatisfied constraint", repository: repository, version: ">=1.0.0"},
+		{name: "pinned digest", repository: repository + "/mychart@" + digests["0.1.0"], version: ">=0.0.0", expect: "0.1.0"},
+		{name: "pinned digest outside the constraint", repository: repository + "/mychart@" + digests["0.1.0"], version: ">=0.2.0"},
+	}
+	r := New("", "", srv.Client)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &chart.Dependency{Name: "mychart", Repository: tt.repository, Version: tt.version}
+			constraint, err := semver.NewConstraint(tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dep, err := r.resolveOCI(d, constraint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.expect == "" {
+				if dep != nil {
+					t.Fatalf("expected no chart to satisfy %s, got %s", tt.version, dep.Version)
+				}
+				return
+			}
+			if dep == nil {
+				t.Fatalf("expected %s to satisfy %s", tt.expect, tt.version)
+			}
+			if dep.Version != tt.expect || dep.ManifestDigest != digests[tt.expect] {
+				t.Errorf("expected %s@%s, got %s@%s", tt.expect, digests[tt.expect], dep.Version, dep.ManifestDigest)
+			}
+		})
+	}
+}
+
+func TestResolveOCIWithoutRegistryClient(t *testing.T) {
+	d := &chart.Dependency{Name: "mychart", Repository: "oci://example.com/charts", Version: "0.1.0"}
+	constraint, err := semver.NewConstraint(d.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = New("", "", nil).resolveOCI(d, constraint)
+	if err == nil || !strings.Contains(err.Error(), "a registry client is required") {
+		t.Errorf("expected an error about the missing registry client, got %v", err)
+	}
+}
@@ -30,7 +30,7 @@ import (
 
 	chart "helm.sh/helm/v4/pkg/chart/v2"
 	"helm.sh/helm/v4/pkg/chart/v2/loader"
-	"helm.sh/helm/v4/pkg/helmpath"
+	"helm.sh/helm/v4/pkg/getter"
 	"helm.sh/helm/v4/pkg/provenance"
 	"helm.sh/helm/v4/pkg/registry"
 	"helm.sh/helm/v4/pkg/repo"
@@ -41,6 +41,7 @@ type Resolver struct {
 	chartpath      string
 	cachepath      string
 	registryClient *registry.Client
+	repositories   map[string]*repo.ChartRepository
 }
 
 // New creates a new resolver for a given chart, helm home and registry client.
@@ -52,6 +53,30 @@ func New(chartpath, cachepath string, registryClient *registry.Client) *Resolver
 	}
 }
 
+// SetRepositories sets the configured chart repositories, which the versions
+// of the charts in sharded indexes are downloaded from. Without them, only the
+// cached versions are resolved.
+func (r *Resolver) SetRepositories(entries []*repo.Entry, getters getter.Providers) {
+	r.repositories = map[string]*repo.ChartRepository{}
+	for _, e := range entries {
+		cr, err := repo.NewChartRepository(e, getters)
+		if err != nil {
+			continue
+		}
+		r.repositories[e.Name] = cr
+	}
+}
+
+// loadIndex loads the cached index of a chart repository.
+func (r *Resolver) loadIndex(repoName string) (*repo.Index, error) {
+	cr, ok := r.repositories[repoName]
+	if !ok {
+		cr = &repo.ChartRepository{Config: &repo.Entry{Name: repoName}}
+	}
+	cr.CachePath = r.cachepath
+	return cr.LoadIndex()
+}
+
 // Resolve resolves dependencies and returns a lock file with the resolution.
 func (r *Resolver) Resolve(reqs []*chart.Dependency, repoNames map[string]string) (*chart.Lock, error) {
 
@@ -107,6 +132,32 @@ func (r *Resolver) Resolve(reqs []*chart.Dependency, repoNames map[string]string
 			continue
 		}
 
+		if IsGitRepository(d.Repository) {
+			dep, err := r.resolveGit(d, constraint)
+			if err != nil {
+				return nil, err
+			}
+			if dep == nil {
+				missing = append(missing, fmt.Sprintf("%q (repository %q, version %q)", d.Name, d.Repository, d.Version))
+				continue
+			}
+			locked[i] = dep
+			continue
+		}
+
+		if registry.IsOCI(d.Repository) {
+			dep, err := r.resolveOCI(d, constraint)
+			if err != nil {
+				return nil, err
+			}
+			if dep == nil {
+				missing = append(missing, fmt.Sprintf("%q (repository %q, version %q)", d.Name, d.Repository, d.Version))
+				continue
+			}
+			locked[i] = dep
+			continue
+		}
+
 		repoName := repoNames[d.Name]
 		// if the repository was not defined, but the dependency defines a repository url, bypass the cache
 		if repoName == "" && d.Repository != "" {
@@ -118,66 +169,28 @@ func (r *Resolver) Resolve(reqs []*chart.Dependency, repoNames map[string]string
 			continue
 		}
 
-		var vs repo.ChartVersions
-		var version string
-		var ok bool
-		found := true
-		if !registry.IsOCI(d.Repository) {
-			repoIndex, err := repo.LoadIndexFile(filepath.Join(r.cachepath, helmpath.CacheIndexFile(repoName)))
-			if err != nil {
-				return nil, fmt.Errorf("no cached repository for %s found. (try 'helm repo update'): %w", repoName, err)
-			}
+		repoIndex, err := r.loadIndex(repoName)
+		if err != nil {
+			return nil, fmt.Errorf("no cached repository for %s found. (try 'helm repo update'): %w", repoName, err)
+		}
 
-			vs, ok = repoIndex.Entries[d.Name]
-			if !ok {
-				return nil, fmt.Errorf("%s chart not found in repo %s", d.Name, d.Repository)
-			}
-			found = false
-		} else {
-			version = d.Version
-
-			// Check to see if an explicit version has been provided
-			_, err := semver.NewVersion(version)
-
-			// Use an explicit version, otherwise search for tags
-			if err == nil {
-				vs = []*repo.ChartVersion{{
-					Metadata: &chart.Metadata{
-						Version: version,
-					},
-				}}
-
-			} else {
-				// Retrieve list of tags for repository
-				ref := fmt.Sprintf("%s/%s", strings.TrimPrefix(d.Repository, fmt.Sprintf("%s://", registry.OCIScheme)), d.Name)
-				tags, err := r.registryClient.Tags(ref)
-				if err != nil {
-					return nil, fmt.Errorf("could not retrieve list of tags for repository %s: %w", d.Repository, err)
-				}
-
-				vs = make(repo.ChartVersions, len(tags))
-				for ti, t := range tags {
-					// Mock chart version objects
-					version := &repo.ChartVersion{
-						Metadata: &chart.Metadata{
-							Version: t,
-						},
-					}
-					vs[ti] = version
-				}
-			}
+		vs, err := repoIndex.Versions(d.Name)
+		if errors.Is(err, repo.ErrNoChartName) {
+			return nil, fmt.Errorf("%s chart not found in repo %s", d.Name, d.Repository)
 		}
+		if err != nil {
+			return nil, err
+		}
+		found := false
 
 		locked[i] = &chart.Dependency{
 			Name:       d.Name,
 			Repository: d.Repository,
-			Version:    version,
 		}
 		// The versions are already sorted and hence the first one to satisfy the constraint is used
 		for _, ver := range vs {
 			v, err := semver.NewVersion(ver.Version)
-			// OCI does not need URLs
-			if err != nil || (!registry.IsOCI(d.Repository) && len(ver.URLs) == 0) {
+			if err != nil || len(ver.URLs) == 0 {
 				// Not a legit entry.
 				continue
 			}
@@ -221,6 +234,17 @@ func HashReq(req, lock []*chart.Dependency) (string, error) {
 	return "sha256:" + s, err
 }
 
+// HashArchive generates the digest of a chart archive.
+//
+// It is recorded for every downloaded dependency in the lock file.
+func HashArchive(filename string) (string, error) {
+	s, err := provenance.DigestFile(filename)
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + s, nil
+}
+
 // HashV2Req generates a hash of requirements generated in Helm v2.
 //
 // This should be used only to compare against another hash generated by the
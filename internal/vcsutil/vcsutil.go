@@ -0,0 +1,60 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcsutil
+
+import (
+	"log/slog"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/Masterminds/vcs"
+)
+
+// SolveTag returns the highest semantic version tag of the repository that
+// satisfies the constraint, or an empty string if none does.
+func SolveTag(repo vcs.Repo, constraint *semver.Constraints) (string, error) {
+	// Get the tags
+	refs, err := repo.Tags()
+	if err != nil {
+		return "", err
+	}
+	slog.Debug("found refs", "refs", refs)
+
+	// Convert and filter the list to semver.Version instances
+	semvers := getSemVers(refs)
+
+	// Sort semver list
+	sort.Sort(sort.Reverse(semver.Collection(semvers)))
+	for _, v := range semvers {
+		if constraint.Check(v) {
+			// If the constraint passes get the original reference
+			return v.Original(), nil
+		}
+	}
+	return "", nil
+}
+
+// Filter a list of versions to only included semantic versions.
+func getSemVers(refs []string) []*semver.Version {
+	var sv []*semver.Version
+	for _, r := range refs {
+		if v, err := semver.NewVersion(r); err == nil {
+			sv = append(sv, v)
+		}
+	}
+	return sv
+}
@@ -89,6 +89,14 @@ type Configuration struct {
 	// CustomTemplateFuncs is defined by users to provide custom template funcs
 	CustomTemplateFuncs template.FuncMap
 
+	// LookupClientProvider serves the lookup template function when rendering
+	// without talking to the cluster (e.g. `helm template` or a client-side
+	// dry run). When nil, lookup returns an empty map in those cases.
+	LookupClientProvider engine.ClientProvider
+
+	// Renderers render the chart templates that are not Go templates.
+	Renderers engine.RendererProviders
+
 	// HookOutputFunc called with container name and returns and expects writer that will receive the log output.
 	HookOutputFunc func(namespace, pod, container string) io.Writer
 
@@ -205,12 +213,17 @@ func (cfg *Configuration) renderResources(ch *chart.Chart, values chartutil.Valu
 		e := engine.New(restConfig)
 		e.EnableDNS = enableDNS
 		e.CustomTemplateFuncs = cfg.CustomTemplateFuncs
+		e.Renderers = cfg.Renderers
 
 		files, err2 = e.Render(ch, values)
 	} else {
 		var e engine.Engine
+		if cfg.LookupClientProvider != nil {
+			e = engine.NewWithClientProvider(cfg.LookupClientProvider)
+		}
 		e.EnableDNS = enableDNS
 		e.CustomTemplateFuncs = cfg.CustomTemplateFuncs
+		e.Renderers = cfg.Renderers
 
 		files, err2 = e.Render(ch, values)
 	}
@@ -0,0 +1,216 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v4/internal/fileutil"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+// BundleCreate is the action for creating a bundle of a chart.
+//
+// It provides the implementation of 'helm bundle create'.
+type BundleCreate struct {
+	// Destination is the directory the bundle is written to.
+	Destination string
+}
+
+// NewBundleCreate creates a new BundleCreate object.
+func NewBundleCreate() *BundleCreate {
+	return &BundleCreate{}
+}
+
+// Run creates a bundle of a chart directory or archive, and returns the path
+// to the bundle and its metadata.
+//
+// The bundle holds the chart, with its provenance file if the chart is an
+// archive that has one, every dependency of the chart and of its
+// dependencies, and the container images referenced by the chart rendered
+// with its default values.
+func (b *BundleCreate) Run(chartpath string) (string, *registry.BundleMetadata, error) {
+	ch, root, err := loadBundleChart(chartpath)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := CheckDependencies(ch, ch.Metadata.Dependencies); err != nil {
+		return "", nil, err
+	}
+
+	charts := []registry.BundleChart{*root}
+	if err := appendBundleDependencies(&charts, ch); err != nil {
+		return "", nil, err
+	}
+	images, err := packagedImages(ch, map[string]interface{}{})
+	if err != nil {
+		return "", nil, err
+	}
+
+	var buf bytes.Buffer
+	metadata, err := registry.WriteBundle(&buf, charts, images, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return "", nil, err
+	}
+	dest := filepath.Join(b.Destination, fmt.Sprintf("%s-%s.bundle.tar", ch.Name(), ch.Metadata.Version))
+	if err := fileutil.AtomicWriteFile(dest, &buf, 0644); err != nil {
+		return "", nil, err
+	}
+	return dest, metadata, nil
+}
+
+// loadBundleChart loads a chart directory or archive, and returns it with
+// its archive. Chart directories are packaged reproducibly.
+func loadBundleChart(chartpath string) (*chart.Chart, *registry.BundleChart, error) {
+	fi, err := os.Stat(chartpath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fi.IsDir() {
+		ch, err := loader.LoadDir(chartpath)
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err := archiveChart(ch)
+		if err != nil {
+			return nil, nil, err
+		}
+		return ch, &registry.BundleChart{Data: data}, nil
+	}
+
+	data, err := os.ReadFile(chartpath)
+	if err != nil {
+		return nil, nil, err
+	}
+	ch, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	prov, err := os.ReadFile(chartpath + ".prov")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, err
+	}
+	return ch, &registry.BundleChart{Data: data, Prov: prov}, nil
+}
+
+// appendBundleDependencies appends the archives of the dependencies vendored
+// in the charts/ directory of a chart, and of their dependencies, to charts.
+// Dependency archives are bundled as they are, with their provenance files if
+// they have one. Unpacked dependencies are packaged reproducibly.
+func appendBundleDependencies(charts *[]registry.BundleChart, ch *chart.Chart) error {
+	raw := make(map[string][]byte, len(ch.Raw))
+	for _, f := range ch.Raw {
+		raw[f.Name] = f.Data
+	}
+
+	var dirs []string
+	unpacked := map[string][]*loader.BufferedFile{}
+	for _, f := range ch.Raw {
+		name, ok := strings.CutPrefix(f.Name, "charts/")
+		if !ok || strings.IndexAny(name, "_.") == 0 {
+			continue
+		}
+		if dir, file, ok := strings.Cut(name, "/"); ok {
+			if _, seen := unpacked[dir]; !seen {
+				dirs = append(dirs, dir)
+			}
+			unpacked[dir] = append(unpacked[dir], &loader.BufferedFile{Name: file, Data: f.Data})
+			continue
+		}
+		if filepath.Ext(name) != ".tgz" {
+			continue
+		}
+		dep, err := loader.LoadArchive(bytes.NewReader(f.Data))
+		if err != nil {
+			return fmt.Errorf("could not load dependency %s: %w", name, err)
+		}
+		*charts = append(*charts, registry.BundleChart{Data: f.Data, Prov: raw[f.Name+".prov"]})
+		if err := appendBundleDependencies(charts, dep); err != nil {
+			return err
+		}
+	}
+
+	for _, dir := range dirs {
+		dep, err := loader.LoadFiles(unpacked[dir])
+		if err != nil {
+			return fmt.Errorf("could not load dependency %s: %w", dir, err)
+		}
+		data, err := archiveChart(dep)
+		if err != nil {
+			return fmt.Errorf("could not package dependency %s: %w", dep.Name(), err)
+		}
+		*charts = append(*charts, registry.BundleChart{Data: data})
+		if err := appendBundleDependencies(charts, dep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveChart packages a chart reproducibly and returns the archive.
+func archiveChart(ch *chart.Chart) ([]byte, error) {
+	modTime, err := sourceDateEpoch()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "helm-bundle-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	name, err := chartutil.SaveReproducible(ch, dir, modTime)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(name)
+}
+
+// BundlePush is the action for uploading the charts of a bundle to a
+// registry.
+//
+// It provides the implementation of 'helm bundle push'.
+type BundlePush struct {
+	cfg *Configuration
+}
+
+// NewBundlePush creates a new BundlePush object with the given configuration.
+func NewBundlePush(cfg *Configuration) *BundlePush {
+	return &BundlePush{cfg: cfg}
+}
+
+// Run uploads the charts of a bundle below the given oci:// remote, and
+// returns their references.
+func (b *BundlePush) Run(bundlePath, remote string) ([]string, error) {
+	if !registry.IsOCI(remote) {
+		return nil, fmt.Errorf("%s is not an OCI registry, the remote must start with %s://", remote, registry.OCIScheme)
+	}
+	bundle, err := registry.LoadBundle(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	return b.cfg.RegistryClient.PushBundle(bundle, remote)
+}
@@ -0,0 +1,32 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+)
+
+func TestCapabilitiesDump(t *testing.T) {
+	client := NewCapabilitiesDump(actionConfigFixture(t))
+	caps, err := client.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, chartutil.DefaultCapabilities, caps)
+}
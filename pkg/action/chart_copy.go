@@ -0,0 +1,285 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	"helm.sh/helm/v4/pkg/cli"
+	"helm.sh/helm/v4/pkg/downloader"
+	"helm.sh/helm/v4/pkg/getter"
+	"helm.sh/helm/v4/pkg/registry"
+	"helm.sh/helm/v4/pkg/repo"
+)
+
+// ChartCopy is the action for copying charts into a registry.
+//
+// It provides the implementation of 'helm chart copy'.
+type ChartCopy struct {
+	// Version is a version or a version constraint. Every version of the
+	// source chart that matches it is copied. Without a version, the latest
+	// version is copied.
+	Version string
+
+	Settings *cli.EnvSettings
+	cfg      *Configuration
+}
+
+// CopiedChart is a chart copied by ChartCopy.
+type CopiedChart struct {
+	// Source is the reference or the URL the chart was copied from.
+	Source string
+	// Destination is the registry reference the chart was copied to.
+	Destination string
+	// Digest is the digest of the manifest of the chart at the destination.
+	Digest string
+}
+
+// NewChartCopy creates a new ChartCopy object with the given configuration.
+func NewChartCopy(cfg *Configuration) *ChartCopy {
+	return &ChartCopy{cfg: cfg}
+}
+
+// Run copies the chart src below the oci:// remote dst, to a repository named
+// after the chart, e.g. oci://registry.example.com/charts/mychart:1.0.0.
+//
+// The source is an oci:// reference, a 'repo/chart' reference of a chart
+// repository, or the URL of a chart archive. Charts in a registry are copied
+// with their manifests unchanged. Charts in a chart repository are pushed with
+// their provenance files, if they have one.
+func (c *ChartCopy) Run(src, dst string) ([]CopiedChart, error) {
+	if !registry.IsOCI(dst) {
+		return nil, fmt.Errorf("%s is not an OCI registry, the destination must start with %s://", dst, registry.OCIScheme)
+	}
+	dst = strings.TrimSuffix(strings.TrimPrefix(dst, registry.OCIScheme+"://"), "/")
+	if registry.IsOCI(src) {
+		return c.copyFromRegistry(src, dst)
+	}
+	return c.copyFromRepository(src, dst)
+}
+
+func (c *ChartCopy) copyFromRegistry(src, dst string) ([]CopiedChart, error) {
+	ref := strings.TrimPrefix(src, registry.OCIScheme+"://")
+	if strings.Contains(ref, "@") {
+		return nil, fmt.Errorf("cannot copy %s by digest, use a tag or a version", src)
+	}
+	repository, tag := ref, ""
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		repository, tag = ref[:i], ref[i+1:]
+	}
+
+	var versions []string
+	if tag != "" {
+		if c.Version != "" {
+			return nil, fmt.Errorf("cannot use a version with the tagged reference %s", src)
+		}
+		versions = []string{tag}
+	} else {
+		tags, err := c.cfg.RegistryClient.Tags(repository)
+		if err != nil {
+			return nil, err
+		}
+		versions, err = matchingVersions(tags, c.Version)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src, err)
+		}
+	}
+
+	name := path.Base(repository)
+	var copied []CopiedChart
+	for _, version := range versions {
+		from := fmt.Sprintf("%s:%s", repository, version)
+		to := fmt.Sprintf("%s:%s", path.Join(dst, name), version)
+		desc, err := c.cfg.RegistryClient.Copy(from, to)
+		if err != nil {
+			return copied, err
+		}
+		copied = append(copied, CopiedChart{
+			Source:      registry.OCIScheme + "://" + from,
+			Destination: registry.OCIScheme + "://" + to,
+			Digest:      desc.Digest.String(),
+		})
+	}
+	return copied, nil
+}
+
+func (c *ChartCopy) copyFromRepository(src, dst string) ([]CopiedChart, error) {
+	// A chart archive URL is a single version of a chart.
+	if u, err := url.Parse(src); err == nil && u.IsAbs() {
+		if c.Version != "" {
+			return nil, fmt.Errorf("cannot use a version with the chart URL %s", src)
+		}
+		chart, err := c.pushFromRepository(src, "", "", dst)
+		if err != nil {
+			return nil, err
+		}
+		return []CopiedChart{*chart}, nil
+	}
+
+	repoName, chartName, ok := strings.Cut(src, "/")
+	if !ok {
+		return nil, fmt.Errorf("non-absolute URLs should be in form of repo_name/path_to_chart, got: %s", src)
+	}
+	rf, err := repo.LoadFile(c.Settings.RepositoryConfig)
+	if err != nil {
+		return nil, err
+	}
+	if !rf.Has(repoName) {
+		return nil, fmt.Errorf("no repo named %q found", repoName)
+	}
+	r, err := repo.NewChartRepository(rf.Get(repoName), getter.All(c.Settings))
+	if err != nil {
+		return nil, err
+	}
+	r.CachePath = c.Settings.RepositoryCache
+	index, err := r.LoadIndex()
+	if err != nil {
+		return nil, fmt.Errorf("no cached repo found. (try 'helm repo update'): %w", err)
+	}
+	cvs, err := index.Versions(chartName)
+	if err != nil && !errors.Is(err, repo.ErrNoChartName) {
+		return nil, err
+	}
+	created := map[string]time.Time{}
+	var versions []string
+	for _, cv := range cvs {
+		created[cv.Version] = cv.Created
+		versions = append(versions, cv.Version)
+	}
+	versions, err = matchingVersions(versions, c.Version)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", src, err)
+	}
+
+	var copied []CopiedChart
+	for _, version := range versions {
+		var creationTime string
+		if t := created[version]; !t.IsZero() {
+			creationTime = t.UTC().Format(time.RFC3339)
+		}
+		chart, err := c.pushFromRepository(src, version, creationTime, dst)
+		if err != nil {
+			return copied, err
+		}
+		copied = append(copied, *chart)
+	}
+	return copied, nil
+}
+
+// pushFromRepository downloads a version of a chart from a chart repository
+// with its provenance file, and pushes them below dst.
+func (c *ChartCopy) pushFromRepository(ref, version, creationTime, dst string) (*CopiedChart, error) {
+	dir, err := os.MkdirTemp("", "helm-copy-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	dl := downloader.ChartDownloader{
+		Out:              io.Discard,
+		Verify:           downloader.VerifyLater,
+		Getters:          getter.All(c.Settings),
+		RegistryClient:   c.cfg.RegistryClient,
+		RepositoryConfig: c.Settings.RepositoryConfig,
+		RepositoryCache:  c.Settings.RepositoryCache,
+	}
+	filename, _, err := dl.DownloadTo(ref, version, dir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []registry.PushOption{registry.PushOptCreationTime(creationTime)}
+	provData, err := os.ReadFile(filename + ".prov")
+	if err == nil {
+		opts = append(opts, registry.PushOptProvData(provData))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	to := fmt.Sprintf("%s:%s", path.Join(dst, ch.Metadata.Name), ch.Metadata.Version)
+	result, err := c.cfg.RegistryClient.Push(data, to, opts...)
+	if err != nil {
+		return nil, err
+	}
+	source := ref
+	if version != "" {
+		source = fmt.Sprintf("%s:%s", ref, version)
+	}
+	return &CopiedChart{
+		Source:      source,
+		Destination: registry.OCIScheme + "://" + result.Ref,
+		Digest:      result.Manifest.Digest,
+	}, nil
+}
+
+// matchingVersions returns the versions that match a version constraint, newest
+// first. Without a constraint, only the latest stable version is returned.
+func matchingVersions(versions []string, constraint string) ([]string, error) {
+	all := constraint != ""
+	if !all {
+		constraint = "*"
+	}
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+	var matches semver.Collection
+	for _, v := range versions {
+		sv, err := semver.NewVersion(v)
+		if err != nil {
+			continue
+		}
+		if c.Check(sv) {
+			matches = append(matches, sv)
+		}
+	}
+	if len(matches) == 0 {
+		if !all {
+			return nil, errors.New("no versions found")
+		}
+		return nil, fmt.Errorf("no versions match %s", constraint)
+	}
+	sort.Sort(sort.Reverse(matches))
+	if !all {
+		matches = matches[:1]
+	}
+	result := make([]string, len(matches))
+	for i, v := range matches {
+		result[i] = v.Original()
+	}
+	return result, nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchingVersions(t *testing.T) {
+	versions := []string{"1.0.0", "2.0.0-rc.1", "1.2.0", "0.9.0", "latest"}
+	tests := []struct {
+		constraint string
+		expect     string
+		wantErr    bool
+	}{
+		{constraint: "", expect: "1.2.0"},
+		{constraint: "1.0.0", expect: "1.0.0"},
+		{constraint: ">=1.0.0", expect: "1.2.0,1.0.0"},
+		{constraint: ">=1.0.0-0", expect: "2.0.0-rc.1,1.2.0,1.0.0"},
+		{constraint: ">=3.0.0", wantErr: true},
+		{constraint: "not a version", wantErr: true},
+	}
+	for _, tt := range tests {
+		matches, err := matchingVersions(versions, tt.constraint)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: unexpected error: %v", tt.constraint, err)
+			continue
+		}
+		if got := strings.Join(matches, ","); got != tt.expect {
+			t.Errorf("%q: expected %q, got %q", tt.constraint, tt.expect, got)
+		}
+	}
+}
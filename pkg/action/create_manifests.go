@@ -0,0 +1,463 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"sigs.k8s.io/yaml"
+
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	releaseutil "helm.sh/helm/v4/pkg/release/util"
+)
+
+// CreateFromManifests is the action for creating a chart from existing
+// Kubernetes manifests.
+//
+// It provides the implementation of 'helm create --from-manifests'.
+type CreateFromManifests struct{}
+
+// NewCreateFromManifests creates a new CreateFromManifests object.
+func NewCreateFromManifests() *CreateFromManifests {
+	return &CreateFromManifests{}
+}
+
+// defaultTemplates are the templates of a new chart that are replaced by the
+// templates of the manifests. The helpers are kept for their labels.
+var defaultTemplates = []string{
+	chartutil.IngressFileName,
+	chartutil.HTTPRouteFileName,
+	chartutil.DeploymentName,
+	chartutil.ServiceName,
+	chartutil.ServiceAccountName,
+	chartutil.HorizontalPodAutoscalerName,
+	chartutil.NotesName,
+	chartutil.TestConnectionName,
+}
+
+// helperLabels are the labels set by the labels helper of a new chart.
+var helperLabels = []string{
+	"helm.sh/chart",
+	"app.kubernetes.io/name",
+	"app.kubernetes.io/instance",
+	"app.kubernetes.io/version",
+	"app.kubernetes.io/managed-by",
+}
+
+// Run creates the chart name in dir from the manifests in a file or a
+// directory, and returns the path of the chart.
+//
+// Every object becomes a template of its own. The names, namespaces, labels,
+// replicas, container images and resources of the objects are moved to the
+// values of the chart, and the labels helper labels every object.
+func (c *CreateFromManifests) Run(name, dir, manifests string) (string, error) {
+	objs, err := loadManifestObjects(manifests)
+	if err != nil {
+		return "", err
+	}
+	if len(objs) == 0 {
+		return "", fmt.Errorf("no Kubernetes objects found in %s", manifests)
+	}
+
+	t := &manifestTemplater{chartname: name, values: map[string]interface{}{}, files: map[string]bool{}}
+	templates := map[string][]byte{}
+	var filenames []string
+	for _, obj := range objs {
+		filename, content, err := t.templatize(obj)
+		if err != nil {
+			return "", err
+		}
+		templates[filename] = content
+		filenames = append(filenames, filename)
+	}
+
+	cdir, err := chartutil.Create(name, dir)
+	if err != nil {
+		return cdir, err
+	}
+	for _, f := range defaultTemplates {
+		if err := os.Remove(filepath.Join(cdir, f)); err != nil && !os.IsNotExist(err) {
+			return cdir, err
+		}
+	}
+	// The tests directory is left alone if it holds tests of its own.
+	_ = os.Remove(filepath.Join(cdir, chartutil.TemplatesTestsDir))
+
+	for _, filename := range filenames {
+		if err := os.WriteFile(filepath.Join(cdir, chartutil.TemplatesDir, filename), templates[filename], 0644); err != nil {
+			return cdir, err
+		}
+	}
+	values, err := t.valuesFile()
+	if err != nil {
+		return cdir, err
+	}
+	return cdir, os.WriteFile(filepath.Join(cdir, chartutil.ValuesfileName), values, 0644)
+}
+
+// loadManifestObjects returns the objects in a manifest file, or in the YAML
+// and JSON files of a directory and its subdirectories. The items of lists are
+// returned as objects of their own.
+func loadManifestObjects(path string) ([]map[string]interface{}, error) {
+	var files []string
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch filepath.Ext(p) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, p)
+		default:
+			if p == path {
+				files = append(files, p)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []map[string]interface{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		docs := releaseutil.SplitManifests(string(data))
+		keys := make([]string, 0, len(docs))
+		for k := range docs {
+			keys = append(keys, k)
+		}
+		sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+		for _, k := range keys {
+			var obj map[string]interface{}
+			if err := yaml.Unmarshal([]byte(docs[k]), &obj); err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			objs = appendManifestObject(objs, obj)
+		}
+	}
+	return objs, nil
+}
+
+// appendManifestObject appends an object, or the items of a list, without
+// the fields that are set by the cluster.
+func appendManifestObject(objs []map[string]interface{}, obj map[string]interface{}) []map[string]interface{} {
+	if obj == nil {
+		return objs
+	}
+	kind, _ := obj["kind"].(string)
+	if items, ok := obj["items"].([]interface{}); ok && strings.HasSuffix(kind, "List") {
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok {
+				objs = appendManifestObject(objs, m)
+			}
+		}
+		return objs
+	}
+
+	delete(obj, "status")
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"creationTimestamp", "generation", "managedFields", "ownerReferences", "resourceVersion", "selfLink", "uid"} {
+			delete(metadata, field)
+		}
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+	return append(objs, obj)
+}
+
+// manifestTemplater turns objects into templates, and collects the values
+// that the templates are rendered with.
+type manifestTemplater struct {
+	chartname string
+	// values holds the values of the objects by the keys of their kinds
+	// and names.
+	values map[string]interface{}
+	// files are the names of the templates.
+	files map[string]bool
+
+	// substitutions are the template actions of the placeholders in the
+	// object that is templatized.
+	substitutions []substitution
+}
+
+// substitution is a template action that replaces a placeholder in an
+// object. A block replaces the value of a field with lines of its own,
+// indented by indent.
+type substitution struct {
+	action string
+	block  func(indent int) []string
+}
+
+// placeholder returns the placeholder of the nth substitution.
+func placeholder(n int) string {
+	return fmt.Sprintf("__HELM_TEMPLATE_%d__", n)
+}
+
+// scalar returns a placeholder for a scalar value.
+func (t *manifestTemplater) scalar(action string) string {
+	t.substitutions = append(t.substitutions, substitution{action: action})
+	return placeholder(len(t.substitutions) - 1)
+}
+
+// block returns a placeholder for a value that is written by lines of
+// template actions.
+func (t *manifestTemplater) block(fn func(indent int) []string) string {
+	t.substitutions = append(t.substitutions, substitution{block: fn})
+	return placeholder(len(t.substitutions) - 1)
+}
+
+// templatize returns the name and the content of the template of an object,
+// and adds the values of the object to the values.
+func (t *manifestTemplater) templatize(obj map[string]interface{}) (string, []byte, error) {
+	kind, _ := obj["kind"].(string)
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	if kind == "" || name == "" {
+		return "", nil, fmt.Errorf("an object without a kind or a name cannot be templatized")
+	}
+	t.substitutions = nil
+
+	kindKey := valuesKey(kind)
+	kindValues, ok := t.values[kindKey].(map[string]interface{})
+	if !ok {
+		kindValues = map[string]interface{}{}
+		t.values[kindKey] = kindValues
+	}
+	key := uniqueKey(kindValues, valuesKey(name))
+	vals := map[string]interface{}{}
+	kindValues[key] = vals
+	path := ".Values." + kindKey + "." + key
+
+	vals["name"] = name
+	metadata["name"] = t.scalar(fmt.Sprintf("{{ %s.name }}", path))
+	if namespace, ok := metadata["namespace"].(string); ok && namespace != "" {
+		vals["namespace"] = namespace
+		metadata["namespace"] = t.scalar(fmt.Sprintf("{{ %s.namespace | default .Release.Namespace }}", path))
+	}
+
+	labels, _ := metadata["labels"].(map[string]interface{})
+	for _, l := range helperLabels {
+		delete(labels, l)
+	}
+	if len(labels) > 0 {
+		vals["labels"] = labels
+	}
+	metadata["labels"] = t.block(func(indent int) []string {
+		lines := []string{fmt.Sprintf("{{- include %q . | nindent %d }}", t.chartname+".labels", indent)}
+		if len(labels) > 0 {
+			lines = append(lines,
+				fmt.Sprintf("{{- with %s.labels }}", path),
+				fmt.Sprintf("{{- toYaml . | nindent %d }}", indent),
+				"{{- end }}")
+		}
+		return lines
+	})
+
+	if spec, ok := obj["spec"].(map[string]interface{}); ok {
+		if replicas, ok := spec["replicas"]; ok {
+			vals["replicas"] = replicas
+			spec["replicas"] = t.scalar(fmt.Sprintf("{{ %s.replicas }}", path))
+		}
+	}
+
+	findPodSpecs(obj, func(podSpec map[string]interface{}) {
+		for _, field := range []string{"initContainers", "containers"} {
+			containers, _ := podSpec[field].([]interface{})
+			for _, c := range containers {
+				c, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				fieldValues, ok := vals[field].(map[string]interface{})
+				if !ok {
+					fieldValues = map[string]interface{}{}
+					vals[field] = fieldValues
+				}
+				cname, _ := c["name"].(string)
+				ckey := uniqueKey(fieldValues, valuesKey(cname))
+				cvals := map[string]interface{}{}
+				fieldValues[ckey] = cvals
+				cpath := path + "." + field + "." + ckey
+
+				if image, ok := c["image"].(string); ok && image != "" {
+					repository, tag, digest := splitImage(image)
+					imageValues := map[string]interface{}{"repository": repository}
+					action := fmt.Sprintf("{{ %s.image.repository }}", cpath)
+					if tag != "" {
+						imageValues["tag"] = tag
+						action += fmt.Sprintf(":{{ %s.image.tag }}", cpath)
+					}
+					if digest != "" {
+						imageValues["digest"] = digest
+						action += fmt.Sprintf("@{{ %s.image.digest }}", cpath)
+					}
+					cvals["image"] = imageValues
+					c["image"] = t.scalar(`"` + action + `"`)
+				}
+				if resources, ok := c["resources"].(map[string]interface{}); ok && len(resources) > 0 {
+					cvals["resources"] = resources
+					c["resources"] = t.block(func(indent int) []string {
+						return []string{fmt.Sprintf("{{- toYaml %s.resources | nindent %d }}", cpath, indent)}
+					})
+				}
+			}
+		}
+	})
+
+	out, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", nil, err
+	}
+	// Template actions in the manifests are written as they are.
+	content := strings.ReplaceAll(string(out), "{{", `{{ "{{" }}`)
+
+	var buf bytes.Buffer
+	for _, line := range strings.SplitAfter(content, "\n") {
+		line = t.substitute(line)
+		buf.WriteString(line)
+	}
+	return t.filename(kind, name), buf.Bytes(), nil
+}
+
+// substitute replaces the placeholders in a line of a template.
+func (t *manifestTemplater) substitute(line string) string {
+	for n, s := range t.substitutions {
+		p := placeholder(n)
+		if !strings.Contains(line, p) {
+			continue
+		}
+		if s.block == nil {
+			return strings.Replace(line, p, s.action, 1)
+		}
+		key := strings.TrimRight(strings.TrimSuffix(strings.TrimRight(line, "\n"), p), " ")
+		indent := len(key) - len(strings.TrimLeft(key, " -")) + 2
+		var b strings.Builder
+		b.WriteString(key + "\n")
+		for _, l := range s.block(indent) {
+			b.WriteString(strings.Repeat(" ", indent) + l + "\n")
+		}
+		return b.String()
+	}
+	return line
+}
+
+// filename returns a unique name for the template of an object.
+func (t *manifestTemplater) filename(kind, name string) string {
+	base := strings.ToLower(kind) + "-" + strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '.' {
+			return r
+		}
+		return '-'
+	}, name)
+	filename := base + ".yaml"
+	for i := 2; t.files[filename]; i++ {
+		filename = fmt.Sprintf("%s-%d.yaml", base, i)
+	}
+	t.files[filename] = true
+	return filename
+}
+
+// valuesFile returns the content of the values file of the chart.
+func (t *manifestTemplater) valuesFile() ([]byte, error) {
+	out, err := yaml.Marshal(t.values)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Default values for %s.\n", t.chartname)
+	buf.WriteString("# The values were extracted from the manifests that the chart was created from.\n\n")
+	buf.WriteString("# These override the name of the chart and the full name of the release in labels.\n")
+	buf.WriteString("nameOverride: \"\"\n")
+	buf.WriteString("fullnameOverride: \"\"\n\n")
+	buf.Write(out)
+	return buf.Bytes(), nil
+}
+
+// valuesKey returns a key of the values for the name of a kind or an object,
+// in camel case, e.g. configMap for ConfigMap and myApp for my-app. Keys are
+// identifiers, so that templates can refer to them as fields.
+func valuesKey(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for i, word := range words {
+		runes := []rune(word)
+		if i == 0 {
+			// Lower the leading upper case letters, but the last of a
+			// run that starts a word, as in APIService.
+			upper := 0
+			for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+				upper++
+			}
+			if upper > 1 && upper < len(runes) {
+				upper--
+			}
+			for j := 0; j < upper || j == 0; j++ {
+				runes[j] = unicode.ToLower(runes[j])
+			}
+		} else {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		b.WriteString(string(runes))
+	}
+	key := b.String()
+	if key == "" || unicode.IsDigit([]rune(key)[0]) {
+		key = "_" + key
+	}
+	return key
+}
+
+// uniqueKey returns key, or key with a number if it is taken in values.
+func uniqueKey(values map[string]interface{}, key string) string {
+	unique := key
+	for i := 2; values[unique] != nil; i++ {
+		unique = fmt.Sprintf("%s%d", key, i)
+	}
+	return unique
+}
+
+// splitImage splits an image reference into its repository, and its tag and
+// digest if it has them.
+func splitImage(image string) (repository, tag, digest string) {
+	repository = image
+	if i := strings.Index(repository, "@"); i >= 0 {
+		repository, digest = repository[:i], repository[i+1:]
+	}
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository, tag = repository[:i], repository[i+1:]
+	}
+	return repository, tag, digest
+}
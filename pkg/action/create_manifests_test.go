@@ -0,0 +1,108 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"helm.sh/helm/v4/internal/test"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+)
+
+func TestCreateFromManifests(t *testing.T) {
+	dir := t.TempDir()
+	cdir, err := NewCreateFromManifests().Run("shop", dir, "testdata/manifests/app")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "shop"), cdir)
+
+	entries, err := os.ReadDir(filepath.Join(cdir, "templates"))
+	require.NoError(t, err)
+	var templates []string
+	for _, e := range entries {
+		templates = append(templates, e.Name())
+	}
+	assert.Equal(t, []string{"_helpers.tpl", "configmap-web-config.yaml", "deployment-web.yaml", "service-web.yaml"}, templates)
+
+	test.AssertGoldenFile(t, filepath.Join(cdir, "templates", "deployment-web.yaml"), "output/manifests-deployment.txt")
+	test.AssertGoldenFile(t, filepath.Join(cdir, "values.yaml"), "output/manifests-values.txt")
+
+	// The chart renders the manifests it was created from.
+	ch, err := loader.Load(cdir)
+	require.NoError(t, err)
+	client := NewInstall(actionConfigFixture(t))
+	client.ClientOnly = true
+	client.DryRun = true
+	client.DryRunOption = "client"
+	client.ReleaseName = "shop"
+	client.Namespace = "default"
+	rel, err := client.Run(ch, map[string]interface{}{})
+	require.NoError(t, err)
+	for _, expect := range []string{
+		"  name: web\n  namespace: shop\n",
+		"    app.kubernetes.io/managed-by: Helm\n    app: web\n",
+		"image: \"nginx:1.27\"",
+		"image: \"registry.example.com:5000/shop/migrate@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef\"",
+		"  replicas: 3\n",
+		"          limits:\n            memory: 128Mi\n",
+		"greeting.tpl: Hello {{ .Name }}",
+	} {
+		assert.Contains(t, rel.Manifest, expect)
+	}
+	assert.NotContains(t, rel.Manifest, "last-applied-configuration")
+	assert.NotContains(t, rel.Manifest, "app.kubernetes.io/managed-by: kubectl")
+}
+
+func TestCreateFromManifestsWithoutObjects(t *testing.T) {
+	manifests := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(manifests, "empty.yaml"), []byte("---\n# nothing\n"), 0644))
+	_, err := NewCreateFromManifests().Run("empty", t.TempDir(), manifests)
+	assert.ErrorContains(t, err, "no Kubernetes objects found")
+}
+
+func TestValuesKey(t *testing.T) {
+	for in, expect := range map[string]string{
+		"ConfigMap":    "configMap",
+		"APIService":   "apiService",
+		"HPA":          "hpa",
+		"my-app.v2":    "myAppV2",
+		"web":          "web",
+		"1st-replica":  "_1stReplica",
+		"---":          "_",
+		"service_name": "serviceName",
+	} {
+		assert.Equal(t, expect, valuesKey(in), in)
+	}
+}
+
+func TestSplitImage(t *testing.T) {
+	for image, expect := range map[string][3]string{
+		"nginx":                                {"nginx", "", ""},
+		"nginx:1.27":                           {"nginx", "1.27", ""},
+		"localhost:5000/nginx":                 {"localhost:5000/nginx", "", ""},
+		"localhost:5000/nginx:1.27@sha256:abc": {"localhost:5000/nginx", "1.27", "sha256:abc"},
+	} {
+		repository, tag, digest := splitImage(image)
+		assert.Equal(t, expect, [3]string{repository, tag, digest}, image)
+		assert.True(t, strings.HasPrefix(image, repository))
+	}
+}
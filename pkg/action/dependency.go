@@ -26,6 +26,7 @@ import (
 	"github.com/Masterminds/semver/v3"
 	"github.com/gosuri/uitable"
 
+	"helm.sh/helm/v4/internal/resolver"
 	chart "helm.sh/helm/v4/pkg/chart/v2"
 	"helm.sh/helm/v4/pkg/chart/v2/loader"
 )
@@ -45,6 +46,10 @@ type Dependency struct {
 	CaFile                string
 	InsecureSkipTLSverify bool
 	PlainHTTP             bool
+	Recursive             bool
+	// AllowUnlocked lets VerifyLock pass for dependencies that are not
+	// locked to a digest or a commit.
+	AllowUnlocked bool
 }
 
 // NewDependency creates a new Dependency object with the given configuration.
@@ -72,6 +77,72 @@ func (d *Dependency) List(chartpath string, out io.Writer) error {
 	return nil
 }
 
+// VerifyLock executes 'helm dependency verify'.
+//
+// It checks the archives in the charts/ directory against the digests in the
+// lock file, without downloading anything. Dependencies that are not locked
+// to a digest or a commit fail the verification unless AllowUnlocked is set.
+func (d *Dependency) VerifyLock(chartpath string, out io.Writer) error {
+	c, err := loader.LoadDir(chartpath)
+	if err != nil {
+		return err
+	}
+	if c.Lock == nil {
+		return fmt.Errorf("no lock file found in %s", chartpath)
+	}
+
+	failed := 0
+	table := uitable.New()
+	table.MaxColWidth = d.ColumnWidth
+	table.AddRow("NAME", "VERSION", "DIGEST", "STATUS")
+	for _, dep := range c.Lock.Dependencies {
+		status := lockStatus(chartpath, dep)
+		switch status {
+		case "ok", "locked to commit":
+		case "not locked":
+			if !d.AllowUnlocked {
+				failed++
+			}
+		default:
+			failed++
+		}
+		table.AddRow(dep.Name, dep.Version, dep.Digest, status)
+	}
+	fmt.Fprintln(out, table)
+
+	if failed > 0 {
+		return fmt.Errorf("%d dependencies are not locked or do not match the lock file", failed)
+	}
+	return nil
+}
+
+// lockStatus returns a string describing whether the archive of a dependency
+// matches the digest in the lock file.
+func lockStatus(chartpath string, dep *chart.Dependency) string {
+	// Dependencies from Git repositories are locked to a commit, which is
+	// verified when they are checked out.
+	if dep.Commit != "" {
+		return "locked to commit"
+	}
+	// Dependencies from the charts/ directory and from local paths are not
+	// locked to a digest.
+	if dep.Digest == "" {
+		return "not locked"
+	}
+	archive := filepath.Join(chartpath, "charts", fmt.Sprintf("%s-%s.tgz", dep.Name, dep.Version))
+	if _, err := os.Stat(archive); err != nil {
+		return "missing"
+	}
+	digest, err := resolver.HashArchive(archive)
+	if err != nil {
+		return "unreadable"
+	}
+	if digest != dep.Digest {
+		return "digest mismatch"
+	}
+	return "ok"
+}
+
 // dependencyStatus returns a string describing the status of a dependency viz a viz the parent chart.
 func (d *Dependency) dependencyStatus(chartpath string, dep *chart.Dependency, parent *chart.Chart) string {
 	filename := fmt.Sprintf("%s-%s.tgz", dep.Name, "*")
@@ -17,7 +17,9 @@ limitations under the License.
 package action
 
 import (
+	chart "helm.sh/helm/v4/pkg/chart/v2"
 	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	release "helm.sh/helm/v4/pkg/release/v1"
 )
 
 // GetValues is the action for checking a given release's values.
@@ -58,3 +60,49 @@ func (g *GetValues) Run(name string) (map[string]interface{}, error) {
 	}
 	return rel.Config, nil
 }
+
+// RunWithOrigins executes 'helm get values --show-origin' against the given
+// release. It returns the computed values together with the origin of each of
+// them.
+//
+// Releases that were created without recording origins report all of their
+// user-supplied values as chartutil.OriginUser.
+func (g *GetValues) RunWithOrigins(name string) (map[string]interface{}, chartutil.ValueOrigins, error) {
+	if err := g.cfg.KubeClient.IsReachable(); err != nil {
+		return nil, nil, err
+	}
+
+	rel, err := g.cfg.releaseContent(name, g.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	origins := valueOrigins(rel)
+	vals, err := chartutil.CoalesceValuesWithOrigins(rel.Chart, rel.Config, origins)
+	if err != nil {
+		return nil, nil, err
+	}
+	return vals, origins, nil
+}
+
+// releaseValuesOrigins returns the origins of the values of chrt to store with
+// a release.
+func releaseValuesOrigins(chrt *chart.Chart, origins chartutil.ValueOrigins) map[string]release.ValueOrigin {
+	if origins == nil {
+		return nil
+	}
+	out := make(map[string]release.ValueOrigin)
+	for k, o := range origins.Compact(chrt) {
+		out[k] = release.ValueOrigin{Type: string(o.Type), Source: o.Source}
+	}
+	return out
+}
+
+// valueOrigins returns the origins of the values stored with a release.
+func valueOrigins(rel *release.Release) chartutil.ValueOrigins {
+	out := make(chartutil.ValueOrigins, len(rel.ValuesOrigins))
+	for k, o := range rel.ValuesOrigins {
+		out[k] = chartutil.ValueOrigin{Type: chartutil.OriginType(o.Type), Source: o.Source}
+	}
+	return out
+}
@@ -0,0 +1,256 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/engine"
+	releaseutil "helm.sh/helm/v4/pkg/release/util"
+	release "helm.sh/helm/v4/pkg/release/v1"
+)
+
+// Image is a container image referenced by a Kubernetes resource.
+type Image struct {
+	// Image is the image reference, as written in the manifest.
+	Image string `json:"image"`
+	// Kind is the kind of the resource that references the image.
+	Kind string `json:"kind"`
+	// Name is the name of the resource that references the image.
+	Name string `json:"name"`
+	// Container is the name of the container that runs the image.
+	Container string `json:"container"`
+	// Source is the template that the resource was rendered from.
+	Source string `json:"source,omitempty"`
+}
+
+// chartImages returns the container images referenced by the manifests and
+// hooks that a chart renders with the given values, without accessing a
+// cluster.
+func chartImages(renderers engine.RendererProviders, ch *chart.Chart, vals map[string]interface{}) ([]Image, error) {
+	// Rendering in client-only mode replaces the clients of the
+	// configuration, so a configuration of its own is used.
+	client := NewInstall(&Configuration{Renderers: renderers})
+	client.ClientOnly = true
+	client.DryRun = true
+	client.DryRunOption = "client"
+	client.Replace = true
+	client.ReleaseName = "release-name"
+	client.Namespace = "default"
+	rel, err := client.Run(ch, vals)
+	if err != nil {
+		return nil, err
+	}
+	return ReleaseImages(rel)
+}
+
+// packagedImages returns the sorted list of container images referenced by a
+// chart rendered with the given values. The chart is rendered the way it is
+// linted, so templates that require values which are not given do not fail
+// the rendering. Library charts reference no images.
+func packagedImages(ch *chart.Chart, vals map[string]interface{}) ([]string, error) {
+	if ch.Metadata.Type == "library" {
+		return nil, nil
+	}
+	if err := chartutil.ProcessDependencies(ch, vals); err != nil {
+		return nil, fmt.Errorf("could not render the chart: %w", err)
+	}
+	cvals, err := chartutil.CoalesceValues(ch, vals)
+	if err != nil {
+		return nil, fmt.Errorf("could not render the chart: %w", err)
+	}
+	options := chartutil.ReleaseOptions{Name: "release-name", Namespace: "default", IsInstall: true}
+	valuesToRender, err := chartutil.ToRenderValuesWithSchemaValidation(ch, cvals, options, chartutil.DefaultCapabilities, true)
+	if err != nil {
+		return nil, fmt.Errorf("could not render the chart: %w", err)
+	}
+	e := engine.Engine{LintMode: true}
+	files, err := e.Render(ch, valuesToRender)
+	if err != nil {
+		return nil, fmt.Errorf("could not render the chart: %w", err)
+	}
+
+	var refs []string
+	for name, content := range files {
+		if !isManifestFile(name) {
+			continue
+		}
+		images, err := ManifestImages(content)
+		if err != nil {
+			return nil, fmt.Errorf("could not render the chart: %s: %w", name, err)
+		}
+		for _, image := range images {
+			if !slices.Contains(refs, image.Image) {
+				refs = append(refs, image.Image)
+			}
+		}
+	}
+	slices.Sort(refs)
+	return refs, nil
+}
+
+// isManifestFile reports whether a rendered template holds manifests, rather
+// than being a partial, the notes or another file.
+func isManifestFile(name string) bool {
+	if strings.HasPrefix(path.Base(name), "_") {
+		return false
+	}
+	switch path.Ext(name) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// containerFields are the fields of a pod spec that hold containers.
+var containerFields = []string{"initContainers", "containers", "ephemeralContainers"}
+
+// ReleaseImages returns the container images referenced by the manifest and
+// the hooks of a release.
+func ReleaseImages(rel *release.Release) ([]Image, error) {
+	images, err := ManifestImages(rel.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range rel.Hooks {
+		hookImages, err := ManifestImages(h.Manifest)
+		if err != nil {
+			return nil, fmt.Errorf("hook %s: %w", h.Path, err)
+		}
+		for i := range hookImages {
+			if hookImages[i].Source == "" {
+				hookImages[i].Source = h.Path
+			}
+		}
+		images = append(images, hookImages...)
+	}
+	return images, nil
+}
+
+// ManifestImages returns the container images referenced by the resources in
+// a manifest.
+//
+// Images are found in the pod spec of any resource: Pods, the pod templates
+// of workloads and CronJobs, and the pod templates of custom resources. Init
+// and ephemeral containers are included.
+func ManifestImages(manifest string) ([]Image, error) {
+	docs := releaseutil.SplitManifests(manifest)
+	keys := make([]string, 0, len(docs))
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	var images []Image
+	for _, k := range keys {
+		doc := docs[k]
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return nil, fmt.Errorf("%s: %w", manifestSource(doc), err)
+		}
+		images = appendObjectImages(images, obj, manifestSource(doc))
+	}
+	return images, nil
+}
+
+// appendObjectImages appends the images referenced by a resource, or by the
+// items of a list.
+func appendObjectImages(images []Image, obj map[string]interface{}, source string) []Image {
+	if obj == nil {
+		return images
+	}
+	kind, _ := obj["kind"].(string)
+	if items, ok := obj["items"].([]interface{}); ok && strings.HasSuffix(kind, "List") {
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok {
+				images = appendObjectImages(images, m, source)
+			}
+		}
+		return images
+	}
+	var name string
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		name, _ = metadata["name"].(string)
+	}
+	walkPodSpecs(obj, func(container, image string) {
+		images = append(images, Image{Image: image, Kind: kind, Name: name, Container: container, Source: source})
+	})
+	return images
+}
+
+// walkPodSpecs calls fn for every container in the pod specs nested in v.
+func walkPodSpecs(v interface{}, fn func(container, image string)) {
+	findPodSpecs(v, func(spec map[string]interface{}) {
+		for _, field := range containerFields {
+			containers, _ := spec[field].([]interface{})
+			for _, c := range containers {
+				c, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if image, ok := c["image"].(string); ok && image != "" {
+					name, _ := c["name"].(string)
+					fn(name, image)
+				}
+			}
+		}
+	})
+}
+
+// findPodSpecs calls fn for every pod spec nested in v, that is every object
+// with a list of containers.
+func findPodSpecs(v interface{}, fn func(spec map[string]interface{})) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if _, ok := v["containers"].([]interface{}); ok {
+			fn(v)
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if !slices.Contains(containerFields, k) {
+				findPodSpecs(v[k], fn)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			findPodSpecs(item, fn)
+		}
+	}
+}
+
+// manifestSource returns the template named by the "# Source:" comment of a
+// rendered manifest.
+func manifestSource(doc string) string {
+	for _, line := range strings.Split(doc, "\n") {
+		if source, ok := strings.CutPrefix(strings.TrimSpace(line), "# Source: "); ok {
+			return source
+		}
+	}
+	return ""
+}
@@ -0,0 +1,114 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+func TestManifestImages(t *testing.T) {
+	manifest := `---
+# Source: moby/templates/pod.yaml
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+    - name: app
+      image: app:1
+  ephemeralContainers:
+    - name: debugger
+      image: busybox:1
+---
+# Source: moby/templates/list.yaml
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: apps/v1
+    kind: DaemonSet
+    metadata:
+      name: agent
+    spec:
+      template:
+        spec:
+          containers:
+            - name: agent
+              image: agent:2
+  - apiVersion: v1
+    kind: Service
+    metadata:
+      name: agent
+---
+# Source: moby/templates/rollout.yaml
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+        - name: web
+          image: web:3
+`
+
+	images, err := ManifestImages(manifest)
+	require.NoError(t, err)
+	assert.Equal(t, []Image{
+		{Image: "app:1", Kind: "Pod", Name: "debug", Container: "app", Source: "moby/templates/pod.yaml"},
+		{Image: "busybox:1", Kind: "Pod", Name: "debug", Container: "debugger", Source: "moby/templates/pod.yaml"},
+		{Image: "agent:2", Kind: "DaemonSet", Name: "agent", Container: "agent", Source: "moby/templates/list.yaml"},
+		{Image: "web:3", Kind: "Rollout", Name: "web", Container: "web", Source: "moby/templates/rollout.yaml"},
+	}, images)
+}
+
+func TestShowImages(t *testing.T) {
+	client := NewShow(ShowAll, actionConfigFixture(t))
+	client.chart = &chart.Chart{
+		Metadata: &chart.Metadata{Name: "moby", Version: "1.0.0", APIVersion: chart.APIVersionV2},
+		Templates: []*chart.File{
+			{Name: "templates/cronjob.yaml", Data: []byte(`apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: {{ .Release.Name }}-backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: backup
+              image: {{ .Values.image }}
+`)},
+		},
+	}
+	images, err := client.Images("", map[string]interface{}{"image": "backup:latest"})
+	require.NoError(t, err)
+	assert.Equal(t, []Image{{
+		Image:     "backup:latest",
+		Kind:      "CronJob",
+		Name:      "release-name-backup",
+		Container: "backup",
+		Source:    "moby/templates/cronjob.yaml",
+	}}, images)
+}
@@ -105,6 +105,9 @@ type Install struct {
 	// (for things like templating). These are ignored if ClientOnly is false
 	KubeVersion *chartutil.KubeVersion
 	APIVersions chartutil.VersionSet
+	// Capabilities replaces the default capabilities that KubeVersion and
+	// APIVersions are applied to. It is ignored if ClientOnly is false.
+	Capabilities *chartutil.Capabilities
 	// Used by helm template to render charts with .Release.IsUpgrade. Ignored if Dry-Run is false
 	IsUpgrade bool
 	// Enable DNS lookups when rendering templates
@@ -115,6 +118,13 @@ type Install struct {
 	// TakeOwnership will ignore the check for helm annotations and take ownership of the resources.
 	TakeOwnership bool
 	PostRenderer  postrender.PostRenderer
+	// RecordValueOrigins stores the origin of every value with the release.
+	RecordValueOrigins bool
+	// ValueOrigins holds the origins of the user-supplied values, as returned by
+	// values.Options.MergeValuesWithOrigins. If RecordValueOrigins is set, the
+	// origins of the values of the chart are added to it and it is stored with
+	// the release.
+	ValueOrigins chartutil.ValueOrigins
 	// Lock to control raceconditions when the process receives a SIGTERM
 	Lock sync.Mutex
 }
@@ -255,7 +265,12 @@ func (i *Install) RunWithContext(ctx context.Context, chrt *chart.Chart, vals ma
 		return nil, fmt.Errorf("release name check failed: %w", err)
 	}
 
-	if err := chartutil.ProcessDependencies(chrt, vals); err != nil {
+	if !i.RecordValueOrigins {
+		i.ValueOrigins = nil
+	} else if i.ValueOrigins == nil {
+		i.ValueOrigins = make(chartutil.ValueOrigins)
+	}
+	if err := chartutil.ProcessDependenciesWithOrigins(chrt, vals, i.ValueOrigins); err != nil {
 		slog.Error("chart dependencies processing failed", slog.Any("error", err))
 		return nil, fmt.Errorf("chart dependencies processing failed: %w", err)
 	}
@@ -279,7 +294,11 @@ func (i *Install) RunWithContext(ctx context.Context, chrt *chart.Chart, vals ma
 	if i.ClientOnly {
 		// Add mock objects in here so it doesn't use Kube API server
 		// NOTE(bacongobbler): used for `helm template`
-		i.cfg.Capabilities = chartutil.DefaultCapabilities.Copy()
+		caps := chartutil.DefaultCapabilities
+		if i.Capabilities != nil {
+			caps = i.Capabilities
+		}
+		i.cfg.Capabilities = caps.Copy()
 		if i.KubeVersion != nil {
 			i.cfg.Capabilities.KubeVersion = *i.KubeVersion
 		}
@@ -323,6 +342,7 @@ func (i *Install) RunWithContext(ctx context.Context, chrt *chart.Chart, vals ma
 	}
 
 	rel := i.createRelease(chrt, vals, i.Labels)
+	rel.ValuesOrigins = releaseValuesOrigins(chrt, i.ValueOrigins)
 
 	var manifestDoc *bytes.Buffer
 	rel.Hooks, manifestDoc, rel.Info.Notes, err = i.cfg.renderResources(chrt, valuesToRender, i.ReleaseName, i.OutputDir, i.SubNotes, i.UseReleaseName, i.IncludeCRDs, i.PostRenderer, interactWithRemote, i.EnableDNS, i.HideSecret)
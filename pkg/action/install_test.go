@@ -261,6 +261,21 @@ func TestInstallReleaseClientOnly(t *testing.T) {
 	is.Equal(instAction.cfg.KubeClient, &kubefake.PrintingKubeClient{Out: io.Discard})
 }
 
+func TestInstallRelease_RecordValueOrigins(t *testing.T) {
+	is := assert.New(t)
+
+	instAction := installAction(t)
+	res, err := instAction.Run(buildChart(), map[string]interface{}{"name": "value"})
+	is.NoError(err)
+	is.Nil(res.ValuesOrigins, "Expected no value origins unless requested.")
+
+	instAction = installAction(t)
+	instAction.RecordValueOrigins = true
+	res, err = instAction.Run(buildChart(), map[string]interface{}{"name": "value"})
+	is.NoError(err)
+	is.Equal(release.ValueOrigin{Type: string(chartutil.OriginUser)}, res.ValuesOrigins["name"])
+}
+
 func TestInstallRelease_NoName(t *testing.T) {
 	instAction := installAction(t)
 	instAction.ReleaseName = ""
@@ -933,3 +948,19 @@ func TestInstallWithSystemLabels(t *testing.T) {
 
 	is.Equal(fmt.Errorf("user supplied labels contains system reserved label name. System labels: %+v", driver.GetSystemLabels()), err)
 }
+
+func TestInstallWithCapabilities(t *testing.T) {
+	is := assert.New(t)
+	instAction := installAction(t)
+	instAction.ClientOnly = true
+	instAction.Capabilities = &chartutil.Capabilities{
+		KubeVersion: chartutil.KubeVersion{Version: "v1.29.3", Major: "1", Minor: "29"},
+		APIVersions: chartutil.VersionSet{"v1", "example.com/v1"},
+	}
+	instAction.APIVersions = chartutil.VersionSet{"example.com/v2"}
+
+	_, err := instAction.Run(buildChart(), nil)
+	is.NoError(err)
+	is.Equal("v1.29.3", instAction.cfg.Capabilities.KubeVersion.Version)
+	is.Equal(chartutil.VersionSet{"v1", "example.com/v1", "example.com/v2"}, instAction.cfg.Capabilities.APIVersions)
+}
@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/engine"
 	"helm.sh/helm/v4/pkg/lint"
 	"helm.sh/helm/v4/pkg/lint/support"
 )
@@ -37,6 +38,14 @@ type Lint struct {
 	Quiet                bool
 	SkipSchemaValidation bool
 	KubeVersion          *chartutil.KubeVersion
+	// LookupClientProvider, if set, serves the lookup template function
+	// while linting.
+	LookupClientProvider engine.ClientProvider
+	// Renderers render the templates that are not Go templates.
+	Renderers engine.RendererProviders
+	// Capabilities replaces the default capabilities that KubeVersion is
+	// applied to.
+	Capabilities *chartutil.Capabilities
 }
 
 // LintResult is the result of Lint
@@ -58,8 +67,15 @@ func (l *Lint) Run(paths []string, vals map[string]interface{}) *LintResult {
 		lowestTolerance = support.WarningSev
 	}
 	result := &LintResult{}
+	options := []lint.LinterOption{
+		lint.WithKubeVersion(l.KubeVersion),
+		lint.WithSkipSchemaValidation(l.SkipSchemaValidation),
+		lint.WithLookupClientProvider(l.LookupClientProvider),
+		lint.WithRenderers(l.Renderers),
+		lint.WithCapabilities(l.Capabilities),
+	}
 	for _, path := range paths {
-		linter, err := lintChart(path, vals, l.Namespace, l.KubeVersion, l.SkipSchemaValidation)
+		linter, err := lintChart(path, vals, l.Namespace, options...)
 		if err != nil {
 			result.Errors = append(result.Errors, err)
 			continue
@@ -86,7 +102,7 @@ func HasWarningsOrErrors(result *LintResult) bool {
 	return len(result.Errors) > 0
 }
 
-func lintChart(path string, vals map[string]interface{}, namespace string, kubeVersion *chartutil.KubeVersion, skipSchemaValidation bool) (support.Linter, error) {
+func lintChart(path string, vals map[string]interface{}, namespace string, options ...lint.LinterOption) (support.Linter, error) {
 	var chartPath string
 	linter := support.Linter{}
 
@@ -125,11 +141,5 @@ func lintChart(path string, vals map[string]interface{}, namespace string, kubeV
 		return linter, fmt.Errorf("unable to check Chart.yaml file in chart: %w", err)
 	}
 
-	return lint.RunAll(
-		chartPath,
-		vals,
-		namespace,
-		lint.WithKubeVersion(kubeVersion),
-		lint.WithSkipSchemaValidation(skipSchemaValidation),
-	), nil
+	return lint.RunAll(chartPath, vals, namespace, options...), nil
 }
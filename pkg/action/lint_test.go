@@ -18,6 +18,8 @@ package action
 
 import (
 	"testing"
+
+	"helm.sh/helm/v4/pkg/lint"
 )
 
 var (
@@ -83,7 +85,7 @@ func TestLintChart(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := lintChart(tt.chartPath, map[string]interface{}{}, namespace, nil, tt.skipSchemaValidation)
+			_, err := lintChart(tt.chartPath, map[string]interface{}{}, namespace, lint.WithSkipSchemaValidation(tt.skipSchemaValidation))
 			switch {
 			case err != nil && !tt.err:
 				t.Errorf("%s", err)
@@ -21,14 +21,19 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"golang.org/x/term"
 
+	chart "helm.sh/helm/v4/pkg/chart/v2"
 	"helm.sh/helm/v4/pkg/chart/v2/loader"
 	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
 	"helm.sh/helm/v4/pkg/provenance"
+	"helm.sh/helm/v4/pkg/sbom"
 )
 
 // Package is the action for packaging a chart.
@@ -44,6 +49,13 @@ type Package struct {
 	AppVersion       string
 	Destination      string
 	DependencyUpdate bool
+	// Reproducible produces an archive that is byte-for-byte identical for
+	// identical chart sources. The files in the archive are timestamped with
+	// SOURCE_DATE_EPOCH, or with the Unix epoch when it is not set.
+	Reproducible bool
+	// SBOM is the format of the software bill of materials that is written
+	// next to the chart archive. No SBOM is written if it is empty.
+	SBOM sbom.Format
 
 	RepositoryConfig      string
 	RepositoryCache       string
@@ -66,7 +78,7 @@ func NewPackage() *Package {
 }
 
 // Run executes 'helm package' against the given chart and returns the path to the packaged chart.
-func (p *Package) Run(path string, _ map[string]interface{}) (string, error) {
+func (p *Package) Run(path string, vals map[string]interface{}) (string, error) {
 	ch, err := loader.LoadDir(path)
 	if err != nil {
 		return "", err
@@ -103,11 +115,27 @@ func (p *Package) Run(path string, _ map[string]interface{}) (string, error) {
 		dest = p.Destination
 	}
 
-	name, err := chartutil.Save(ch, dest)
+	var name string
+	modTime := time.Now()
+	if p.Reproducible {
+		modTime, err = sourceDateEpoch()
+		if err != nil {
+			return "", err
+		}
+		name, err = chartutil.SaveReproducible(ch, dest, modTime)
+	} else {
+		name, err = chartutil.Save(ch, dest)
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to save: %w", err)
 	}
 
+	if p.SBOM != "" {
+		if err := p.writeSBOM(ch, vals, name, modTime); err != nil {
+			return "", fmt.Errorf("failed to generate SBOM: %w", err)
+		}
+	}
+
 	if p.Sign {
 		err = p.Clearsign(name)
 	}
@@ -115,6 +143,58 @@ func (p *Package) Run(path string, _ map[string]interface{}) (string, error) {
 	return name, err
 }
 
+// Files returns the paths in the archive of the files that packaging the
+// chart at path includes, that is the files of the chart that its .helmignore
+// files do not ignore.
+func (p *Package) Files(path string) ([]string, error) {
+	ch, err := loader.LoadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(ch.Raw))
+	for _, f := range ch.Raw {
+		files = append(files, ch.Name()+"/"+f.Name)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// sourceDateEpoch returns the time set by the SOURCE_DATE_EPOCH environment
+// variable, or the Unix epoch if it is not set.
+//
+// See https://reproducible-builds.org/specs/source-date-epoch/
+func sourceDateEpoch() (time.Time, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return time.Unix(0, 0), nil
+	}
+	sec, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %w", epoch, err)
+	}
+	return time.Unix(sec, 0), nil
+}
+
+// writeSBOM writes the software bill of materials of a chart archive next to
+// it. The images it lists are those referenced by the templates rendered with
+// the given values.
+func (p *Package) writeSBOM(ch *chart.Chart, vals map[string]interface{}, archive string, created time.Time) error {
+	digest, err := provenance.DigestFile(archive)
+	if err != nil {
+		return err
+	}
+	images, err := packagedImages(ch, vals)
+	if err != nil {
+		return err
+	}
+	in := sbom.Input{Chart: ch, Digest: "sha256:" + digest, Images: images, Created: created}
+	data, err := sbom.Generate(p.SBOM, in)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(sbom.Filename(archive, p.SBOM), data, 0644)
+}
+
 // validateVersion Verify that version is a Version, and error out if it is not.
 func validateVersion(ver string) error {
 	if _, err := semver.NewVersion(ver); err != nil {
@@ -20,6 +20,7 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 
@@ -152,3 +153,28 @@ func TestValidateVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestSourceDateEpoch(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "")
+	epoch, err := sourceDateEpoch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !epoch.Equal(time.Unix(0, 0)) {
+		t.Errorf("Expected the Unix epoch, got %s", epoch)
+	}
+
+	t.Setenv("SOURCE_DATE_EPOCH", "1588561321")
+	epoch, err = sourceDateEpoch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !epoch.Equal(time.Date(2020, 5, 4, 3, 2, 1, 0, time.UTC)) {
+		t.Errorf("Expected 2020-05-04T03:02:01Z, got %s", epoch)
+	}
+
+	t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+	if _, err := sourceDateEpoch(); err == nil {
+		t.Error("Expected an error for an invalid SOURCE_DATE_EPOCH")
+	}
+}
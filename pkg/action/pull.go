@@ -41,9 +41,12 @@ type Pull struct {
 	Devel       bool
 	Untar       bool
 	VerifyLater bool
-	UntarDir    string
-	DestDir     string
-	cfg         *Configuration
+	// WithAttachments downloads the files attached to a chart in an OCI
+	// registry to DestDir, next to the chart.
+	WithAttachments bool
+	UntarDir        string
+	DestDir         string
+	cfg             *Configuration
 }
 
 type PullOpt func(*Pull)
@@ -144,6 +147,16 @@ func (p *Pull) Run(chartRef string) (string, error) {
 		fmt.Fprintf(&out, "Chart Hash Verified: %s\n", v.FileHash)
 	}
 
+	if p.WithAttachments {
+		files, err := c.DownloadAttachmentsTo(chartRef, p.Version, p.DestDir)
+		if err != nil {
+			return out.String(), fmt.Errorf("failed to download the attachments: %w", err)
+		}
+		for _, file := range files {
+			fmt.Fprintf(&out, "Attachment: %s\n", file)
+		}
+	}
+
 	// After verification, untar the chart into the requested directory.
 	if p.Untar {
 		ud := p.UntarDir
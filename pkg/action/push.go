@@ -17,7 +17,11 @@ limitations under the License.
 package action
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"helm.sh/helm/v4/pkg/cli"
@@ -37,6 +41,8 @@ type Push struct {
 	caFile                string
 	insecureSkipTLSverify bool
 	plainHTTP             bool
+	attachments           []string
+	sbom                  bool
 	out                   io.Writer
 }
 
@@ -80,6 +86,22 @@ func WithPushOptWriter(out io.Writer) PushOpt {
 	}
 }
 
+// WithAttachments sets the files that are attached to the pushed chart, as
+// 'file' or 'file:mediatype'.
+func WithAttachments(attachments []string) PushOpt {
+	return func(p *Push) {
+		p.attachments = attachments
+	}
+}
+
+// WithSBOM attaches the SBOM stored next to the chart archive to the pushed
+// chart.
+func WithSBOM(sbom bool) PushOpt {
+	return func(p *Push) {
+		p.sbom = sbom
+	}
+}
+
 // NewPushWithOpts creates a new push, with configuration options.
 func NewPushWithOpts(opts ...PushOpt) *Push {
 	p := &Push{}
@@ -108,5 +130,49 @@ func (p *Push) Run(chartRef string, remote string) (string, error) {
 		c.Options = append(c.Options, pusher.WithRegistryClient(p.cfg.RegistryClient))
 	}
 
+	if len(p.attachments) > 0 {
+		if !registry.IsOCI(remote) {
+			return "", errors.New("files can only be attached to charts pushed to OCI registries")
+		}
+		attachments := make([]*registry.Attachment, 0, len(p.attachments))
+		for _, spec := range p.attachments {
+			a, err := loadAttachment(spec)
+			if err != nil {
+				return "", err
+			}
+			attachments = append(attachments, a)
+		}
+		c.Options = append(c.Options, pusher.WithAttachments(attachments...))
+	}
+
+	if p.sbom {
+		if !registry.IsOCI(remote) {
+			return "", errors.New("SBOMs can only be attached to charts pushed to OCI registries")
+		}
+		c.Options = append(c.Options, pusher.WithSBOM(true))
+	}
+
 	return out.String(), c.UploadTo(chartRef, remote)
 }
+
+// loadAttachment loads a file attached to a pushed chart from 'file' or
+// 'file:mediatype'.
+func loadAttachment(spec string) (*registry.Attachment, error) {
+	file, mediaType := spec, registry.DefaultAttachmentMediaType
+	// A media type is a type and a subtype, unlike the rest of a path after
+	// a Windows drive letter.
+	if i := strings.LastIndex(spec, ":"); i > 0 {
+		if t, sub, ok := strings.Cut(spec[i+1:], "/"); ok && t != "" && sub != "" && !strings.ContainsAny(sub, `/\`) {
+			file, mediaType = spec[:i], spec[i+1:]
+		}
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot attach %s: %w", file, err)
+	}
+	return &registry.Attachment{
+		Name:      filepath.Base(file),
+		MediaType: mediaType,
+		Data:      data,
+	}, nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+func TestLoadAttachment(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "values-prod.yaml")
+	if err := os.WriteFile(file, []byte("replicaCount: 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		spec      string
+		mediaType string
+	}{
+		{file, registry.DefaultAttachmentMediaType},
+		{file + ":application/yaml", "application/yaml"},
+		{file + ":application/vnd.example+yaml", "application/vnd.example+yaml"},
+	}
+	for _, tt := range tests {
+		a, err := loadAttachment(tt.spec)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.spec, err)
+		}
+		if a.Name != "values-prod.yaml" {
+			t.Errorf("%s: expected the name values-prod.yaml, got %s", tt.spec, a.Name)
+		}
+		if a.MediaType != tt.mediaType {
+			t.Errorf("%s: expected the media type %s, got %s", tt.spec, tt.mediaType, a.MediaType)
+		}
+		if string(a.Data) != "replicaCount: 3\n" {
+			t.Errorf("%s: unexpected data %q", tt.spec, a.Data)
+		}
+	}
+
+	if _, err := loadAttachment(filepath.Join(dir, "missing.yaml") + ":application/yaml"); err == nil {
+		t.Error("expected an error attaching a missing file")
+	}
+}
@@ -27,6 +27,8 @@ import (
 	chart "helm.sh/helm/v4/pkg/chart/v2"
 	"helm.sh/helm/v4/pkg/chart/v2/loader"
 	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/downloader"
+	"helm.sh/helm/v4/pkg/engine"
 	"helm.sh/helm/v4/pkg/registry"
 )
 
@@ -60,6 +62,7 @@ type Show struct {
 	Devel            bool
 	OutputFormat     ShowOutputFormat
 	JSONPathTemplate string
+	cfg              *Configuration
 	chart            *chart.Chart // for testing
 }
 
@@ -67,6 +70,7 @@ type Show struct {
 func NewShow(output ShowOutputFormat, cfg *Configuration) *Show {
 	sh := &Show{
 		OutputFormat: output,
+		cfg:          cfg,
 	}
 	sh.registryClient = cfg.RegistryClient
 
@@ -140,6 +144,38 @@ func (s *Show) Run(chartpath string) (string, error) {
 	return out.String(), nil
 }
 
+// Images returns the container images referenced by the manifests and hooks
+// that the chart renders with the given values.
+//
+// The chart is rendered as by 'helm template', without accessing a cluster.
+func (s *Show) Images(chartpath string, vals map[string]interface{}) ([]Image, error) {
+	if s.chart == nil {
+		chrt, err := loader.Load(chartpath)
+		if err != nil {
+			return nil, err
+		}
+		s.chart = chrt
+	}
+	if err := CheckDependencies(s.chart, s.chart.Metadata.Dependencies); err != nil {
+		return nil, err
+	}
+
+	var renderers engine.RendererProviders
+	if s.cfg != nil {
+		renderers = s.cfg.Renderers
+	}
+	return chartImages(renderers, s.chart, vals)
+}
+
+// Attachments returns the files attached to a chart in an OCI registry,
+// without downloading the chart or the files.
+func (s *Show) Attachments(chartRef string) ([]*registry.Attachment, error) {
+	c := downloader.ChartDownloader{
+		RegistryClient: s.registryClient,
+	}
+	return c.Attachments(chartRef, s.Version)
+}
+
 func findReadme(files []*chart.File) (file *chart.File) {
 	for _, file := range files {
 		for _, n := range readmeFileNames {
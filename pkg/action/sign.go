@@ -0,0 +1,94 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+// Sign is the action for signing a chart.
+//
+// It provides the implementation of 'helm sign'.
+type Sign struct {
+	Key            string
+	Keyring        string
+	PassphraseFile string
+
+	cfg *Configuration
+}
+
+// NewSign creates a new Sign object with the given configuration.
+func NewSign(cfg *Configuration) *Sign {
+	return &Sign{cfg: cfg}
+}
+
+// Run signs a chart archive, or a chart in an OCI registry.
+//
+// A chart archive is signed by a provenance file next to it, and Run returns
+// its path. A chart in a registry is signed by a detached signature that is
+// attached to it through the referrers API, without pushing the chart again,
+// and Run returns the digest of the signature.
+func (s *Sign) Run(ref string) (string, error) {
+	signer := &Package{Key: s.Key, Keyring: s.Keyring, PassphraseFile: s.PassphraseFile}
+	if !registry.IsOCI(ref) {
+		if err := signer.Clearsign(ref); err != nil {
+			return "", err
+		}
+		return ref + ".prov", nil
+	}
+
+	ociRef := strings.TrimPrefix(ref, fmt.Sprintf("%s://", registry.OCIScheme))
+	result, err := s.cfg.RegistryClient.Pull(ociRef)
+	if err != nil {
+		return "", err
+	}
+	dir, err := os.MkdirTemp("", "helm-sign-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+	archive, err := writePulledChart(dir, result)
+	if err != nil {
+		return "", err
+	}
+	if err := signer.Clearsign(archive); err != nil {
+		return "", err
+	}
+	prov, err := os.ReadFile(archive + ".prov")
+	if err != nil {
+		return "", err
+	}
+	desc, err := s.cfg.RegistryClient.PushSignature(ociRef, prov)
+	if err != nil {
+		return "", err
+	}
+	return desc.Digest.String(), nil
+}
+
+// writePulledChart writes the archive of a chart pulled from a registry to
+// dir. The archive is named like the archives that charts are downloaded to,
+// since provenance files name the archive they sign.
+func writePulledChart(dir string, result *registry.PullResult) (string, error) {
+	meta := result.Chart.Meta
+	archive := filepath.Join(dir, fmt.Sprintf("%s-%s.tgz", meta.Name, meta.Version))
+	return archive, os.WriteFile(archive, result.Chart.Data, 0644)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"helm.sh/helm/v4/pkg/cli"
+	"helm.sh/helm/v4/pkg/downloader"
+	"helm.sh/helm/v4/pkg/getter"
+	"helm.sh/helm/v4/pkg/helmpath"
+	"helm.sh/helm/v4/pkg/plugin/cache"
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+// Starter is the action for locating the starter that 'helm create'
+// scaffolds a chart from.
+type Starter struct {
+	// Version is the version or the version constraint of a remote starter.
+	Version string
+	// Verify verifies the provenance of a remote starter.
+	Verify bool
+	// Keyring is the keyring that verifies the provenance of a remote starter.
+	Keyring string
+
+	// StarterDir is the directory of the local starters.
+	StarterDir string
+	// CacheDir is the directory remote starters are downloaded to.
+	CacheDir string
+
+	Out io.Writer
+	// Settings are the settings remote starters are downloaded with.
+	Settings       *cli.EnvSettings
+	registryClient *registry.Client
+}
+
+// NewStarter creates a new Starter object with the given configuration and
+// the default settings.
+func NewStarter(cfg *Configuration) *Starter {
+	return &Starter{
+		StarterDir:     helmpath.DataPath("starters"),
+		CacheDir:       helmpath.CachePath("starters"),
+		Out:            io.Discard,
+		Settings:       cli.New(),
+		registryClient: cfg.RegistryClient,
+	}
+}
+
+// IsRemoteStarter reports whether a starter reference names a starter that is
+// downloaded: an oci:// or another URL, or a 'repo/chart' reference that is
+// not a directory of the local starters.
+func (s *Starter) IsRemoteStarter(name string) bool {
+	if strings.Contains(name, "://") {
+		return true
+	}
+	if filepath.IsAbs(name) || !strings.Contains(name, "/") {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(s.StarterDir, name))
+	return err != nil
+}
+
+// Locate returns the path of a starter.
+//
+// Absolute paths are used as they are, and other local starters are found in
+// the starters directory. Remote starters are downloaded into the cache, and
+// verified if Verify is set. A remote starter of an exact version is only
+// downloaded once, unless it is to be verified and was cached without its
+// provenance file.
+func (s *Starter) Locate(name string) (string, error) {
+	if !s.IsRemoteStarter(name) {
+		if filepath.IsAbs(name) {
+			return name, nil
+		}
+		return filepath.Join(s.StarterDir, name), nil
+	}
+
+	key, err := cache.Key(name)
+	if err != nil {
+		return "", err
+	}
+	dest := filepath.Join(s.CacheDir, key)
+	if cached := s.cached(dest); cached != "" {
+		slog.Debug("using cached starter", "starter", name, "path", cached)
+		if s.Verify {
+			if _, err := downloader.VerifyChart(cached, s.Keyring); err != nil {
+				return "", err
+			}
+		}
+		return cached, nil
+	}
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return "", err
+	}
+
+	dl := downloader.ChartDownloader{
+		Out:              s.Out,
+		Keyring:          s.Keyring,
+		Getters:          getter.All(s.Settings),
+		RepositoryConfig: s.Settings.RepositoryConfig,
+		RepositoryCache:  s.Settings.RepositoryCache,
+		RegistryClient:   s.registryClient,
+	}
+	if registry.IsOCI(name) {
+		dl.Options = append(dl.Options, getter.WithRegistryClient(s.registryClient))
+	}
+	if s.Verify {
+		dl.Verify = downloader.VerifyAlways
+	}
+	filename, _, err := dl.DownloadTo(name, s.Version, dest)
+	if err != nil {
+		return "", err
+	}
+	return filename, nil
+}
+
+// cached returns the starter of an exact version in the cache directory of a
+// starter, if it has been downloaded. A starter that is to be verified is
+// only returned if its provenance file has been downloaded with it.
+func (s *Starter) cached(dir string) string {
+	v, err := semver.StrictNewVersion(s.Version)
+	if err != nil {
+		return ""
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "*-"+v.String()+".tgz"))
+	if len(matches) != 1 {
+		return ""
+	}
+	if s.Verify {
+		if _, err := os.Stat(matches[0] + ".prov"); err != nil {
+			return ""
+		}
+	}
+	return matches[0]
+}
@@ -0,0 +1,52 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStarter(t *testing.T) {
+	s := NewStarter(actionConfigFixture(t))
+	assert.NotNil(t, s.Settings, "expected default settings")
+}
+
+func TestStarterCached(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "mystarter-0.1.0.tgz")
+	require.NoError(t, os.WriteFile(archive, []byte("archive"), 0644))
+
+	s := NewStarter(actionConfigFixture(t))
+	s.Version = "0.1.0"
+	assert.Equal(t, archive, s.cached(dir))
+
+	// A starter cached without its provenance file is downloaded again to be
+	// verified.
+	s.Verify = true
+	assert.Empty(t, s.cached(dir))
+
+	require.NoError(t, os.WriteFile(archive+".prov", []byte("prov"), 0644))
+	assert.Equal(t, archive, s.cached(dir))
+
+	s.Version = "^0.1.0"
+	assert.Empty(t, s.cached(dir), "expected only exact versions to be cached")
+}
@@ -117,6 +117,13 @@ type Upgrade struct {
 	EnableDNS bool
 	// TakeOwnership will skip the check for helm annotations and adopt all existing resources.
 	TakeOwnership bool
+	// RecordValueOrigins stores the origin of every value with the release.
+	RecordValueOrigins bool
+	// ValueOrigins holds the origins of the user-supplied values, as returned by
+	// values.Options.MergeValuesWithOrigins. If RecordValueOrigins is set, the
+	// origins of reused values and of the values of the chart are added to it
+	// and it is stored with the release.
+	ValueOrigins chartutil.ValueOrigins
 }
 
 type resultMessage struct {
@@ -242,8 +249,17 @@ func (u *Upgrade) prepareUpgrade(name string, chart *chart.Chart, vals map[strin
 	if err != nil {
 		return nil, nil, err
 	}
+	if !u.RecordValueOrigins {
+		u.ValueOrigins = nil
+	} else {
+		if u.ValueOrigins == nil {
+			u.ValueOrigins = make(chartutil.ValueOrigins)
+		}
+		// Values carried over from the current release keep their origin.
+		u.ValueOrigins.RecordMissing(vals, valueOrigins(currentRelease))
+	}
 
-	if err := chartutil.ProcessDependencies(chart, vals); err != nil {
+	if err := chartutil.ProcessDependenciesWithOrigins(chart, vals, u.ValueOrigins); err != nil {
 		return nil, nil, err
 	}
 
@@ -284,10 +300,11 @@ func (u *Upgrade) prepareUpgrade(name string, chart *chart.Chart, vals map[strin
 
 	// Store an upgraded release.
 	upgradedRelease := &release.Release{
-		Name:      name,
-		Namespace: currentRelease.Namespace,
-		Chart:     chart,
-		Config:    vals,
+		Name:          name,
+		Namespace:     currentRelease.Namespace,
+		Chart:         chart,
+		Config:        vals,
+		ValuesOrigins: releaseValuesOrigins(chart, u.ValueOrigins),
 		Info: &release.Info{
 			FirstDeployed: currentRelease.Info.FirstDeployed,
 			LastDeployed:  Timestamper(),
@@ -18,9 +18,12 @@ package action
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"helm.sh/helm/v4/pkg/downloader"
+	"helm.sh/helm/v4/pkg/provenance"
+	"helm.sh/helm/v4/pkg/registry"
 )
 
 // Verify is the action for building a given chart's Verify tree.
@@ -29,6 +32,8 @@ import (
 type Verify struct {
 	Keyring string
 	Out     string
+
+	registryClient *registry.Client
 }
 
 // NewVerify creates a new Verify object with the given configuration.
@@ -36,10 +41,26 @@ func NewVerify() *Verify {
 	return &Verify{}
 }
 
+// SetRegistryClient sets the registry client that charts in OCI registries
+// are verified with.
+func (v *Verify) SetRegistryClient(client *registry.Client) {
+	v.registryClient = client
+}
+
 // Run executes 'helm verify'.
+//
+// The chart is a chart archive, or an oci:// reference to a chart in a
+// registry. A chart in a registry is verified by its provenance layer or by
+// its detached signatures.
 func (v *Verify) Run(chartfile string) error {
 	var out strings.Builder
-	p, err := downloader.VerifyChart(chartfile, v.Keyring)
+	var p *provenance.Verification
+	var err error
+	if registry.IsOCI(chartfile) {
+		p, err = v.verifyOCI(chartfile)
+	} else {
+		p, err = downloader.VerifyChart(chartfile, v.Keyring)
+	}
 	if err != nil {
 		return err
 	}
@@ -57,3 +78,47 @@ func (v *Verify) Run(chartfile string) error {
 
 	return nil
 }
+
+// verifyOCI verifies a chart in a registry by its provenance layer, or by the
+// first of its detached signatures that verifies it.
+func (v *Verify) verifyOCI(ref string) (*provenance.Verification, error) {
+	ociRef := strings.TrimPrefix(ref, fmt.Sprintf("%s://", registry.OCIScheme))
+	result, err := v.registryClient.Pull(ociRef, registry.PullOptWithProv(true), registry.PullOptIgnoreMissingProv(true))
+	if err != nil {
+		return nil, err
+	}
+	var provs [][]byte
+	if len(result.Prov.Data) > 0 {
+		provs = append(provs, result.Prov.Data)
+	}
+	signatures, err := v.registryClient.Signatures(ociRef)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range signatures {
+		provs = append(provs, s.Prov)
+	}
+	if len(provs) == 0 {
+		return nil, fmt.Errorf("%s has no provenance and no detached signatures", ref)
+	}
+
+	dir, err := os.MkdirTemp("", "helm-verify-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	archive, err := writePulledChart(dir, result)
+	if err != nil {
+		return nil, err
+	}
+	for _, prov := range provs {
+		if err = os.WriteFile(archive+".prov", prov, 0644); err != nil {
+			return nil, err
+		}
+		var p *provenance.Verification
+		if p, err = downloader.VerifyChart(archive, v.Keyring); err == nil {
+			return p, nil
+		}
+	}
+	return nil, err
+}
@@ -34,7 +34,9 @@ type Dependency struct {
 	// The URL to the repository.
 	//
 	// Appending `index.yaml` to this string should result in a URL that can be
-	// used to fetch the repository index.
+	// used to fetch the repository index. A dependency from an OCI registry may
+	// be pinned to a manifest with a reference to the chart and its digest, e.g.
+	// oci://registry.example.com/charts/mychart@sha256:...
 	Repository string `json:"repository" yaml:"repository"`
 	// A yaml path that resolves to a boolean, used for enabling/disabling charts (e.g. subchart1.enabled )
 	Condition string `json:"condition,omitempty" yaml:"condition,omitempty"`
@@ -47,6 +49,16 @@ type Dependency struct {
 	ImportValues []interface{} `json:"import-values,omitempty" yaml:"import-values,omitempty"`
 	// Alias usable alias to be used for the chart
 	Alias string `json:"alias,omitempty" yaml:"alias,omitempty"`
+	// Commit is the commit that a dependency from a Git repository is locked
+	// to. It is only set in lock files.
+	Commit string `json:"commit,omitempty" yaml:"commit,omitempty"`
+	// Digest is the digest of the archive of a dependency. It is only set in
+	// lock files, and is used to verify the downloaded archive.
+	Digest string `json:"digest,omitempty" yaml:"digest,omitempty"`
+	// ManifestDigest is the digest of the manifest that a dependency from an
+	// OCI registry is locked to. It is only set in lock files, and the
+	// dependency is pulled by this digest rather than by its tag.
+	ManifestDigest string `json:"manifestDigest,omitempty" yaml:"manifestDigest,omitempty"`
 }
 
 // Validate checks for common problems with the dependency datastructure in
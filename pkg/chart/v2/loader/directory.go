@@ -84,6 +84,13 @@ func LoadDir(dir string) (*chart.Chart, error) {
 			if rules.Ignore(n, fi) {
 				return filepath.SkipDir
 			}
+			// An ignore file in a subdirectory adds rules for the files in it.
+			ifile := filepath.Join(name, ignore.HelmIgnore)
+			if _, err := os.Stat(ifile); err == nil {
+				if err := rules.AddFile(n, ifile); err != nil {
+					return fmt.Errorf("error reading %s/%s: %w", n, ignore.HelmIgnore, err)
+				}
+			}
 			return nil
 		}
 
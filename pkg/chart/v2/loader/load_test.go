@@ -87,6 +87,44 @@ func TestLoadDirWithSymlink(t *testing.T) {
 	verifyDependenciesLock(t, c)
 }
 
+func TestLoadDirWithNestedHelmignore(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Chart.yaml":             "apiVersion: v2\nname: nested\nversion: 0.1.0\n",
+		".helmignore":            "**/*.bak\nbuild/\n",
+		"build/out.txt":          "ignored",
+		"docs/.helmignore":       "*.md\n!README.md\n",
+		"docs/README.md":         "kept",
+		"docs/guide.md":          "ignored",
+		"docs/old/notes.txt.bak": "ignored",
+		"files/config.yaml":      "kept",
+		"files/config.yaml.bak":  "ignored",
+		"files/guide.md":         "kept",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to load chart: %s", err)
+	}
+	var names []string
+	for _, f := range c.Raw {
+		names = append(names, f.Name)
+	}
+	expect := ".helmignore,Chart.yaml,docs/.helmignore,docs/README.md,files/config.yaml,files/guide.md"
+	if got := strings.Join(names, ","); got != expect {
+		t.Errorf("Expected files %s, got %s", expect, got)
+	}
+}
+
 func TestBomTestData(t *testing.T) {
 	testFiles := []string{"frobnitz_with_bom/.helmignore", "frobnitz_with_bom/templates/template.tpl", "frobnitz_with_bom/Chart.yaml"}
 	for _, file := range testFiles {
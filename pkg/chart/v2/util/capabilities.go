@@ -16,12 +16,15 @@ limitations under the License.
 package util
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"slices"
 	"strconv"
 
 	"github.com/Masterminds/semver/v3"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
@@ -120,3 +123,74 @@ func allKnownVersions() VersionSet {
 	}
 	return vs
 }
+
+// capabilitiesFile is the format of a file holding the capabilities of a
+// cluster.
+//
+// The Helm version is not part of it: capabilities read from a file always
+// carry the version of the running Helm, just like the ones read from a
+// cluster.
+type capabilitiesFile struct {
+	KubeVersion struct {
+		Version string `json:"version"`
+		Major   string `json:"major,omitempty"`
+		Minor   string `json:"minor,omitempty"`
+	} `json:"kubeVersion"`
+	APIVersions VersionSet `json:"apiVersions"`
+}
+
+// MarshalCapabilities encodes capabilities as YAML, in the format read by
+// ParseCapabilities.
+func MarshalCapabilities(caps *Capabilities) ([]byte, error) {
+	var f capabilitiesFile
+	f.KubeVersion.Version = caps.KubeVersion.Version
+	f.KubeVersion.Major = caps.KubeVersion.Major
+	f.KubeVersion.Minor = caps.KubeVersion.Minor
+	f.APIVersions = caps.APIVersions
+	return yaml.Marshal(f)
+}
+
+// ParseCapabilities decodes capabilities encoded by MarshalCapabilities.
+//
+// If the major or minor Kubernetes version is missing, it is derived from the
+// full version.
+func ParseCapabilities(data []byte) (*Capabilities, error) {
+	var f capabilitiesFile
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, err
+	}
+	if f.KubeVersion.Version == "" {
+		return nil, errors.New("kubeVersion.version is required")
+	}
+	kv := KubeVersion{
+		Version: f.KubeVersion.Version,
+		Major:   f.KubeVersion.Major,
+		Minor:   f.KubeVersion.Minor,
+	}
+	if kv.Major == "" || kv.Minor == "" {
+		parsed, err := ParseKubeVersion(kv.Version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubeVersion.version %q: %w", kv.Version, err)
+		}
+		kv.Major, kv.Minor = parsed.Major, parsed.Minor
+	}
+	return &Capabilities{
+		KubeVersion: kv,
+		APIVersions: f.APIVersions,
+		HelmVersion: DefaultCapabilities.HelmVersion,
+	}, nil
+}
+
+// LoadCapabilities reads capabilities from a file written with
+// MarshalCapabilities.
+func LoadCapabilities(filename string) (*Capabilities, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	caps, err := ParseCapabilities(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load capabilities from %s: %w", filename, err)
+	}
+	return caps, nil
+}
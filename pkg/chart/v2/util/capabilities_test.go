@@ -16,6 +16,7 @@ limitations under the License.
 package util
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -82,3 +83,45 @@ func TestParseKubeVersion(t *testing.T) {
 		t.Errorf("Expected parsed KubeVersion.Minor to be 16, got %q", kv.Minor)
 	}
 }
+
+func TestMarshalCapabilities(t *testing.T) {
+	caps := &Capabilities{
+		KubeVersion: KubeVersion{Version: "v1.29.3-gke.1", Major: "1", Minor: "29+"},
+		APIVersions: VersionSet{"v1", "apps/v1", "example.com/v1alpha1/Widget"},
+		HelmVersion: DefaultCapabilities.HelmVersion,
+	}
+	data, err := MarshalCapabilities(caps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseCapabilities(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, caps) {
+		t.Errorf("expected %+v, got %+v", caps, got)
+	}
+}
+
+func TestParseCapabilities(t *testing.T) {
+	caps, err := ParseCapabilities([]byte("kubeVersion:\n  version: v1.30.2\napiVersions: [v1]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if caps.KubeVersion.Major != "1" || caps.KubeVersion.Minor != "30" {
+		t.Errorf("expected the major and minor version to be derived, got %+v", caps.KubeVersion)
+	}
+	if !caps.APIVersions.Has("v1") || len(caps.APIVersions) != 1 {
+		t.Errorf("unexpected API versions %v", caps.APIVersions)
+	}
+
+	for name, data := range map[string]string{
+		"missing version": "apiVersions: [v1]\n",
+		"invalid version": "kubeVersion:\n  version: latest\n",
+		"unknown field":   "kubeVersion:\n  version: v1.30.2\nhelmVersion: v4\n",
+	} {
+		if _, err := ParseCapabilities([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -47,7 +47,7 @@ func CoalesceValues(chrt *chart.Chart, vals map[string]interface{}) (Values, err
 	if err != nil {
 		return vals, err
 	}
-	return coalesce(log.Printf, chrt, valsCopy, "", false)
+	return coalesce(log.Printf, chrt, valsCopy, "", false, nil)
 }
 
 // MergeValues is used to merge the values in a chart and its subcharts. This
@@ -69,7 +69,7 @@ func MergeValues(chrt *chart.Chart, vals map[string]interface{}) (Values, error)
 	if err != nil {
 		return vals, err
 	}
-	return coalesce(log.Printf, chrt, valsCopy, "", true)
+	return coalesce(log.Printf, chrt, valsCopy, "", true, nil)
 }
 
 func copyValues(vals map[string]interface{}) (Values, error) {
@@ -96,13 +96,16 @@ type printFn func(format string, v ...interface{})
 // Note, the merge argument specifies whether this is being used by MergeValues
 // or CoalesceValues. Coalescing removes null values and their keys in some
 // situations while merging keeps the null values.
-func coalesce(printf printFn, ch *chart.Chart, dest map[string]interface{}, prefix string, merge bool) (map[string]interface{}, error) {
-	coalesceValues(printf, ch, dest, prefix, merge)
-	return coalesceDeps(printf, ch, dest, prefix, merge)
+//
+// If origins is not nil, the origin of the values copied from the charts is
+// recorded.
+func coalesce(printf printFn, ch *chart.Chart, dest map[string]interface{}, prefix string, merge bool, origins *originScope) (map[string]interface{}, error) {
+	coalesceValues(printf, ch, dest, prefix, merge, origins)
+	return coalesceDeps(printf, ch, dest, prefix, merge, origins)
 }
 
 // coalesceDeps coalesces the dependencies of the given chart.
-func coalesceDeps(printf printFn, chrt *chart.Chart, dest map[string]interface{}, prefix string, merge bool) (map[string]interface{}, error) {
+func coalesceDeps(printf printFn, chrt *chart.Chart, dest map[string]interface{}, prefix string, merge bool, origins *originScope) (map[string]interface{}, error) {
 	for _, subchart := range chrt.Dependencies() {
 		if c, ok := dest[subchart.Name()]; !ok {
 			// If dest doesn't already have the key, create it.
@@ -113,11 +116,12 @@ func coalesceDeps(printf printFn, chrt *chart.Chart, dest map[string]interface{}
 		if dv, ok := dest[subchart.Name()]; ok {
 			dvmap := dv.(map[string]interface{})
 			subPrefix := concatPrefix(prefix, chrt.Metadata.Name)
+			subOrigins := origins.subchart(subchart)
 			// Get globals out of dest and merge them into dvmap.
-			coalesceGlobals(printf, dvmap, dest, subPrefix, merge)
+			coalesceGlobals(printf, dvmap, dest, subPrefix, merge, subOrigins.table(GlobalKey), origins.table(GlobalKey))
 			// Now coalesce the rest of the values.
 			var err error
-			dest[subchart.Name()], err = coalesce(printf, subchart, dvmap, subPrefix, merge)
+			dest[subchart.Name()], err = coalesce(printf, subchart, dvmap, subPrefix, merge, subOrigins)
 			if err != nil {
 				return dest, err
 			}
@@ -128,8 +132,9 @@ func coalesceDeps(printf printFn, chrt *chart.Chart, dest map[string]interface{}
 
 // coalesceGlobals copies the globals out of src and merges them into dest.
 //
-// For convenience, returns dest.
-func coalesceGlobals(printf printFn, dest, src map[string]interface{}, prefix string, _ bool) {
+// If destOrigins is not nil, the globals copied keep the origin they have at
+// srcOrigins.
+func coalesceGlobals(printf printFn, dest, src map[string]interface{}, prefix string, _ bool, destOrigins, srcOrigins *originScope) {
 	var dg, sg map[string]interface{}
 
 	if destglob, ok := dest[GlobalKey]; !ok {
@@ -156,6 +161,7 @@ func coalesceGlobals(printf printFn, dest, src map[string]interface{}, prefix st
 			if destv, ok := dg[key]; !ok {
 				// Here there is no merge. We're just adding.
 				dg[key] = vv
+				destOrigins.copied(key, vv, srcOrigins)
 			} else {
 				if destvmap, ok := destv.(map[string]interface{}); !ok {
 					printf("Conflict: cannot merge map onto non-map for %q. Skipping.", key)
@@ -163,10 +169,11 @@ func coalesceGlobals(printf printFn, dest, src map[string]interface{}, prefix st
 					// Basically, we reverse order of coalesce here to merge
 					// top-down.
 					subPrefix := concatPrefix(prefix, key)
+					destOrigins.copied(key, val, srcOrigins)
 					// In this location coalesceTablesFullKey should always have
 					// merge set to true. The output of coalesceGlobals is run
 					// through coalesce where any nils will be removed.
-					coalesceTablesFullKey(printf, vv, destvmap, subPrefix, true)
+					coalesceTablesFullKey(printf, vv, destvmap, subPrefix, true, nil)
 					dg[key] = vv
 				}
 			}
@@ -176,6 +183,7 @@ func coalesceGlobals(printf printFn, dest, src map[string]interface{}, prefix st
 		} else {
 			// TODO: Do we need to do any additional checking on the value?
 			dg[key] = val
+			destOrigins.copied(key, val, srcOrigins)
 		}
 	}
 	dest[GlobalKey] = dg
@@ -190,7 +198,7 @@ func copyMap(src map[string]interface{}) map[string]interface{} {
 // coalesceValues builds up a values map for a particular chart.
 //
 // Values in v will override the values in the chart.
-func coalesceValues(printf printFn, c *chart.Chart, v map[string]interface{}, prefix string, merge bool) {
+func coalesceValues(printf printFn, c *chart.Chart, v map[string]interface{}, prefix string, merge bool, origins *originScope) {
 	subPrefix := concatPrefix(prefix, c.Metadata.Name)
 
 	// Using c.Values directly when coalescing a table can cause problems where
@@ -240,12 +248,13 @@ func coalesceValues(printf printFn, c *chart.Chart, v map[string]interface{}, pr
 
 					// Because v has higher precedence than nv, dest values override src
 					// values.
-					coalesceTablesFullKey(printf, dest, src, concatPrefix(subPrefix, key), merge)
+					coalesceTablesFullKey(printf, dest, src, concatPrefix(subPrefix, key), merge, origins.table(key))
 				}
 			}
 		} else {
 			// If the key is not in v, copy it from nv.
 			v[key] = val
+			origins.chartValue(key, val)
 		}
 	}
 }
@@ -263,17 +272,18 @@ func childChartMergeTrue(chrt *chart.Chart, key string, merge bool) bool {
 //
 // dest is considered authoritative.
 func CoalesceTables(dst, src map[string]interface{}) map[string]interface{} {
-	return coalesceTablesFullKey(log.Printf, dst, src, "", false)
+	return coalesceTablesFullKey(log.Printf, dst, src, "", false, nil)
 }
 
 func MergeTables(dst, src map[string]interface{}) map[string]interface{} {
-	return coalesceTablesFullKey(log.Printf, dst, src, "", true)
+	return coalesceTablesFullKey(log.Printf, dst, src, "", true, nil)
 }
 
 // coalesceTablesFullKey merges a source map into a destination map.
 //
-// dest is considered authoritative.
-func coalesceTablesFullKey(printf printFn, dst, src map[string]interface{}, prefix string, merge bool) map[string]interface{} {
+// dest is considered authoritative. If origins is not nil, src holds values of
+// a chart and the origin of the values copied from it is recorded.
+func coalesceTablesFullKey(printf printFn, dst, src map[string]interface{}, prefix string, merge bool, origins *originScope) map[string]interface{} {
 	// When --reuse-values is set but there are no modifications yet, return new values
 	if src == nil {
 		return dst
@@ -294,9 +304,10 @@ func coalesceTablesFullKey(printf printFn, dst, src map[string]interface{}, pref
 			delete(dst, key)
 		} else if !ok {
 			dst[key] = val
+			origins.chartValue(key, val)
 		} else if istable(val) {
 			if istable(dv) {
-				coalesceTablesFullKey(printf, dv.(map[string]interface{}), val.(map[string]interface{}), fullkey, merge)
+				coalesceTablesFullKey(printf, dv.(map[string]interface{}), val.(map[string]interface{}), fullkey, merge, origins.table(key))
 			} else {
 				printf("warning: cannot overwrite table with non table for %s (%v)", fullkey, val)
 			}
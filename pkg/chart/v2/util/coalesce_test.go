@@ -705,7 +705,7 @@ func TestCoalesceValuesWarnings(t *testing.T) {
 		warnings = append(warnings, fmt.Sprintf(format, v...))
 	}
 
-	_, err := coalesce(printf, c, vals, "", false)
+	_, err := coalesce(printf, c, vals, "", false, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"sigs.k8s.io/yaml"
@@ -647,29 +648,55 @@ spec:
 var Stderr io.Writer = os.Stderr
 
 // CreateFrom creates a new chart, but scaffolds it from the src chart.
+//
+// The parameters of the starter take their default values.
 func CreateFrom(chartfile *chart.Metadata, dest, src string) error {
-	schart, err := loader.Load(src)
+	return CreateFromWithParameters(chartfile, dest, src, nil)
+}
+
+// CreateFromWithParameters creates a new chart, but scaffolds it from the src
+// chart, substituting the given values of the parameters of the starter.
+//
+// Parameters without a value take their default values. It is an error to
+// give a value to a parameter that the starter does not declare.
+func CreateFromWithParameters(chartfile *chart.Metadata, dest, src string, params map[string]string) error {
+	schart, err := loadStarter(src)
+	if err != nil {
+		return err
+	}
+	starterfile, err := loadStarterfile(schart)
+	if err != nil {
+		return err
+	}
+	values, err := resolveStarterParameters(starterfile, params)
 	if err != nil {
-		return fmt.Errorf("could not load %s: %w", src, err)
+		return err
 	}
+	excluded := excludedStarterFiles(starterfile, values)
 
 	schart.Metadata = chartfile
 
 	var updatedTemplates []*chart.File
 
 	for _, template := range schart.Templates {
-		newData := transform(string(template.Data), schart.Name())
+		if slices.Contains(excluded, template.Name) {
+			continue
+		}
+		newData := substituteParameters(transform(string(template.Data), schart.Name()), values)
 		updatedTemplates = append(updatedTemplates, &chart.File{Name: template.Name, Data: newData})
 	}
 
 	schart.Templates = updatedTemplates
+	schart.Files = slices.DeleteFunc(schart.Files, func(f *chart.File) bool {
+		return slices.Contains(excluded, f.Name)
+	})
 	b, err := yaml.Marshal(schart.Values)
 	if err != nil {
 		return fmt.Errorf("reading values file: %w", err)
 	}
 
 	var m map[string]interface{}
-	if err := yaml.Unmarshal(transform(string(b), schart.Name()), &m); err != nil {
+	if err := yaml.Unmarshal(substituteParameters(transform(string(b), schart.Name()), values), &m); err != nil {
 		return fmt.Errorf("transforming values file: %w", err)
 	}
 	schart.Values = m
@@ -679,13 +706,21 @@ func CreateFrom(chartfile *chart.Metadata, dest, src string) error {
 	// needs to be replaced on that file.
 	for _, f := range schart.Raw {
 		if f.Name == ValuesfileName {
-			f.Data = transform(string(f.Data), schart.Name())
+			f.Data = substituteParameters(transform(string(f.Data), schart.Name()), values)
 		}
 	}
 
 	return SaveDir(schart, dest)
 }
 
+func loadStarter(src string) (*chart.Chart, error) {
+	schart, err := loader.Load(src)
+	if err != nil {
+		return nil, fmt.Errorf("could not load %s: %w", src, err)
+	}
+	return schart, nil
+}
+
 // Create creates a new chart in a directory.
 //
 // Inside of dir, this will create a directory based on the name of
@@ -16,6 +16,7 @@ limitations under the License.
 package util
 
 import (
+	"log"
 	"log/slog"
 	"strings"
 
@@ -26,10 +27,34 @@ import (
 
 // ProcessDependencies checks through this chart's dependencies, processing accordingly.
 func ProcessDependencies(c *chart.Chart, v Values) error {
+	return ProcessDependenciesWithOrigins(c, v, nil)
+}
+
+// ProcessDependenciesWithOrigins processes the chart's dependencies like
+// ProcessDependencies. Unless origins is nil, it also records in origins the
+// origin of the values of the chart after processing, which combine the
+// values of its dependencies, the values it sets for them and the values
+// brought in through import-values.
+//
+// The values in v are user-supplied and keep the origin recorded for them.
+func ProcessDependenciesWithOrigins(c *chart.Chart, v Values, origins ValueOrigins) error {
 	if err := processDependencyEnabled(c, v, ""); err != nil {
 		return err
 	}
-	return processDependencyImportValues(c, true)
+	if origins == nil {
+		return processDependencyImportValues(c, true, nil)
+	}
+	charts := make(map[*chart.Chart]ValueOrigins)
+	if err := processDependencyImportValues(c, true, charts); err != nil {
+		return err
+	}
+	origins.RecordMissing(v, nil)
+	for k, o := range charts[c] {
+		if _, ok := origins[k]; !ok {
+			origins[k] = o
+		}
+	}
+	return nil
 }
 
 // processDependencyConditions disables charts based on condition path value in values
@@ -243,22 +268,33 @@ func set(path []string, data map[string]interface{}) map[string]interface{} {
 }
 
 // processImportValues merges values from child to parent based on the chart's dependencies' ImportValues field.
-func processImportValues(c *chart.Chart, merge bool) error {
+//
+// If charts is not nil, the origins of the resulting values of the chart are
+// recorded in it, and it must hold those of its dependencies.
+func processImportValues(c *chart.Chart, merge bool, charts map[*chart.Chart]ValueOrigins) error {
 	if c.Metadata.Dependencies == nil {
 		return nil
 	}
 	// combine chart values and empty config to get Values
+	var origins *originRecorder
 	var cvals Values
 	var err error
-	if merge {
-		cvals, err = MergeValues(c, nil)
+	if charts == nil {
+		if merge {
+			cvals, err = MergeValues(c, nil)
+		} else {
+			cvals, err = CoalesceValues(c, nil)
+		}
 	} else {
-		cvals, err = CoalesceValues(c, nil)
+		origins = &originRecorder{origins: make(ValueOrigins), raw: c, charts: charts}
+		cvals, err = coalesce(log.Printf, c, make(map[string]interface{}), "", merge, origins.scope(c))
 	}
 	if err != nil {
 		return err
 	}
 	b := make(map[string]interface{})
+	// imported holds the origins of the values in b, relative to c.
+	imported := make(ValueOrigins)
 	// import values from each dependency if specified in import-values
 	for _, r := range c.Metadata.Dependencies {
 		var outiv []interface{}
@@ -280,10 +316,12 @@ func processImportValues(c *chart.Chart, merge bool) error {
 					continue
 				}
 				// create value map from child to be merged into parent
+				im := pathToMap(parent, vv.AsMap())
+				imported.recordIfAbsent(im, ValueOrigin{Type: OriginImport, Source: r.Name + ": " + child})
 				if merge {
-					b = MergeTables(b, pathToMap(parent, vv.AsMap()))
+					b = MergeTables(b, im)
 				} else {
-					b = CoalesceTables(b, pathToMap(parent, vv.AsMap()))
+					b = CoalesceTables(b, im)
 				}
 			case string:
 				child := "exports." + iv
@@ -296,6 +334,7 @@ func processImportValues(c *chart.Chart, merge bool) error {
 					slog.Warn("ImportValues missing table", slog.Any("error", err))
 					continue
 				}
+				imported.recordIfAbsent(vm.AsMap(), ValueOrigin{Type: OriginImport, Source: r.Name + ": " + child})
 				if merge {
 					b = MergeTables(b, vm.AsMap())
 				} else {
@@ -324,6 +363,20 @@ func processImportValues(c *chart.Chart, merge bool) error {
 		c.Values = CoalesceTables(cvals, b)
 	}
 
+	if charts != nil {
+		// The values of the chart win over the imported values.
+		o := make(ValueOrigins)
+		walkLeaves(c.Values, nil, func(path []string, _ interface{}) {
+			key := joinPath(path...)
+			if v, ok := origins.origins[key]; ok {
+				o[key] = v
+			} else if v, ok := imported[key]; ok {
+				o[key] = v
+			}
+		})
+		charts[c] = o
+	}
+
 	return nil
 }
 
@@ -355,12 +408,12 @@ func trimNilValues(vals map[string]interface{}) map[string]interface{} {
 }
 
 // processDependencyImportValues imports specified chart values from child to parent.
-func processDependencyImportValues(c *chart.Chart, merge bool) error {
+func processDependencyImportValues(c *chart.Chart, merge bool, charts map[*chart.Chart]ValueOrigins) error {
 	for _, d := range c.Dependencies() {
 		// recurse
-		if err := processDependencyImportValues(d, merge); err != nil {
+		if err := processDependencyImportValues(d, merge, charts); err != nil {
 			return err
 		}
 	}
-	return processImportValues(c, merge)
+	return processImportValues(c, merge, charts)
 }
@@ -218,7 +218,7 @@ func TestProcessDependencyImportValues(t *testing.T) {
 	e["SCBexported2A"] = "blaster"
 	e["global.SC1exported2.all.SC1exported3"] = "SC1expstr"
 
-	if err := processDependencyImportValues(c, false); err != nil {
+	if err := processDependencyImportValues(c, false, nil); err != nil {
 		t.Fatalf("processing import values dependencies %v", err)
 	}
 	cc := Values(c.Values)
@@ -258,7 +258,7 @@ func TestProcessDependencyImportValues(t *testing.T) {
 	}
 
 	c = loadChart(t, "testdata/subpop")
-	if err := processDependencyImportValues(c, true); err != nil {
+	if err := processDependencyImportValues(c, true, nil); err != nil {
 		t.Fatalf("processing import values dependencies %v", err)
 	}
 	cc = Values(c.Values)
@@ -277,7 +277,7 @@ func TestProcessDependencyImportValuesFromSharedDependencyToAliases(t *testing.T
 	if err := processDependencyEnabled(c, c.Values, ""); err != nil {
 		t.Fatalf("expected no errors but got %q", err)
 	}
-	if err := processDependencyImportValues(c, true); err != nil {
+	if err := processDependencyImportValues(c, true, nil); err != nil {
 		t.Fatalf("processing import values dependencies %v", err)
 	}
 	e := make(map[string]string)
@@ -326,7 +326,7 @@ func TestProcessDependencyImportValuesMultiLevelPrecedence(t *testing.T) {
 	e["app2.service.port"] = "8080"
 	e["app3.service.port"] = "9090"
 	e["app4.service.port"] = "1234"
-	if err := processDependencyImportValues(c, true); err != nil {
+	if err := processDependencyImportValues(c, true, nil); err != nil {
 		t.Fatalf("processing import values dependencies %v", err)
 	}
 	cc := Values(c.Values)
@@ -353,7 +353,7 @@ func TestProcessDependencyImportValuesForEnabledCharts(t *testing.T) {
 	c := loadChart(t, "testdata/import-values-from-enabled-subchart/parent-chart")
 	nameOverride := "parent-chart-prod"
 
-	if err := processDependencyImportValues(c, true); err != nil {
+	if err := processDependencyImportValues(c, true, nil); err != nil {
 		t.Fatalf("processing import values dependencies %v", err)
 	}
 
@@ -0,0 +1,329 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+// OriginType describes the kind of source a value came from.
+type OriginType string
+
+const (
+	// OriginChart is a default from the values.yaml of the chart the value belongs to.
+	OriginChart OriginType = "chart"
+	// OriginParent is a value set for a subchart in the values.yaml of one of its parent charts.
+	OriginParent OriginType = "parent"
+	// OriginImport is a value imported from a dependency through import-values.
+	OriginImport OriginType = "import"
+	// OriginFile is a value supplied by a values file (-f/--values).
+	OriginFile OriginType = "file"
+	// OriginSet is a value supplied by one of the --set flags.
+	OriginSet OriginType = "set"
+	// OriginUser is a user-supplied value whose exact source was not recorded.
+	OriginUser OriginType = "user"
+)
+
+// ValueOrigin records where a value came from.
+type ValueOrigin struct {
+	// Type is the kind of source.
+	Type OriginType `json:"type"`
+	// Source identifies the source, e.g. a chart name, a file path or a flag.
+	Source string `json:"source,omitempty"`
+}
+
+// String returns a human readable description of the origin.
+func (o ValueOrigin) String() string {
+	switch o.Type {
+	case OriginChart:
+		return fmt.Sprintf("chart default (%s)", o.Source)
+	case OriginParent:
+		return fmt.Sprintf("parent chart (%s)", o.Source)
+	case OriginImport:
+		return fmt.Sprintf("import (%s)", o.Source)
+	case OriginFile:
+		return fmt.Sprintf("file (%s)", o.Source)
+	case OriginSet:
+		return o.Source
+	case OriginUser:
+		return "user-supplied"
+	}
+	return string(o.Type)
+}
+
+// ValueOrigins maps the dotted path of every leaf value to its origin.
+//
+// Maps are descended into; any other value, including lists, is a leaf.
+type ValueOrigins map[string]ValueOrigin
+
+// RecordLayer records origin for every leaf in vals, which are about to be
+// merged into dest.
+//
+// Layers are expected to be recorded in the order they are merged, so a leaf
+// replaces the origin of any value of dest it overrides.
+func (o ValueOrigins) RecordLayer(dest, vals map[string]interface{}, origin ValueOrigin) {
+	walkLeaves(vals, nil, func(path []string, val interface{}) {
+		// A leaf replaces the values above it that are not tables.
+		for i := 1; i < len(path); i++ {
+			delete(o, joinPath(path[:i]...))
+		}
+		// A leaf that is not a table also replaces the values below it.
+		if _, ok := val.(map[string]interface{}); !ok {
+			if old, ok := lookupPath(dest, path); ok {
+				if m, ok := old.(map[string]interface{}); ok {
+					walkLeaves(m, path, func(p []string, _ interface{}) {
+						delete(o, joinPath(p...))
+					})
+				}
+			}
+		}
+		o[joinPath(path...)] = origin
+	})
+}
+
+// RecordMissing records the origin of every leaf in vals that has no origin
+// yet. The origin is taken from previous if it recorded one of the
+// user-supplied values there, and is OriginUser otherwise.
+//
+// This is useful for values that are carried over from an earlier release.
+func (o ValueOrigins) RecordMissing(vals map[string]interface{}, previous ValueOrigins) {
+	walkLeaves(vals, nil, func(path []string, _ interface{}) {
+		key := joinPath(path...)
+		if _, ok := o[key]; ok {
+			return
+		}
+		if po, ok := previous[key]; ok && po.userSupplied() {
+			o[key] = po
+			return
+		}
+		o[key] = ValueOrigin{Type: OriginUser}
+	})
+}
+
+// Paths returns the recorded paths in sorted order.
+func (o ValueOrigins) Paths() []string {
+	paths := make([]string, 0, len(o))
+	for k := range o {
+		paths = append(paths, k)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// Compact returns the origins without those that CoalesceValuesWithOrigins
+// infers from the chart: the defaults of the chart a value belongs to. This
+// keeps the origins small enough to be stored with a release.
+func (o ValueOrigins) Compact(chrt *chart.Chart) ValueOrigins {
+	out := make(ValueOrigins)
+	for k, v := range o {
+		if v != chartDefault(chrt, parsePath(k)) {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func (o ValueOrigin) userSupplied() bool {
+	return o.Type == OriginFile || o.Type == OriginSet || o.Type == OriginUser
+}
+
+// recordIfAbsent records origin for every leaf in vals that has no origin yet.
+func (o ValueOrigins) recordIfAbsent(vals map[string]interface{}, origin ValueOrigin) {
+	walkLeaves(vals, nil, func(path []string, _ interface{}) {
+		key := joinPath(path...)
+		if _, ok := o[key]; !ok {
+			o[key] = origin
+		}
+	})
+}
+
+// CoalesceValuesWithOrigins coalesces the values like CoalesceValues, and
+// completes origins with the origin of every value that was not supplied by
+// the user.
+//
+// origins should hold the origins of the user-supplied values, as recorded by
+// RecordLayer, and the origins of the values of the chart, as recorded by
+// ProcessDependenciesWithOrigins. Values in vals without a recorded origin are
+// attributed to OriginUser, and values of the chart without a recorded origin
+// to the chart they belong to.
+func CoalesceValuesWithOrigins(chrt *chart.Chart, vals map[string]interface{}, origins ValueOrigins) (Values, error) {
+	valsCopy, err := copyValues(vals)
+	if err != nil {
+		return vals, err
+	}
+	origins.RecordMissing(vals, nil)
+	r := &originRecorder{origins: origins}
+	return coalesce(log.Printf, chrt, valsCopy, "", false, r.scope(chrt))
+}
+
+// originRecorder records the origins of values while charts are coalesced.
+type originRecorder struct {
+	origins ValueOrigins
+	// raw is the chart whose values are being processed for import-values.
+	// Its values still hold the overrides it sets for its dependencies, so
+	// the values it has for them are recorded as OriginParent.
+	raw *chart.Chart
+	// charts holds the origins of the values of the charts that were
+	// processed for import-values, relative to each chart.
+	charts map[*chart.Chart]ValueOrigins
+}
+
+// scope returns the scope of the values of the root chart c.
+func (r *originRecorder) scope(c *chart.Chart) *originScope {
+	return &originScope{recorder: r, chart: c}
+}
+
+// originOf returns the origin of the value at path in the values of c.
+func (r *originRecorder) originOf(c *chart.Chart, path []string) ValueOrigin {
+	if c == r.raw {
+		if len(path) > 1 && dependency(c, path[0]) != nil {
+			return ValueOrigin{Type: OriginParent, Source: c.Name()}
+		}
+		return ValueOrigin{Type: OriginChart, Source: c.Name()}
+	}
+	if o, ok := r.charts[c][joinPath(path...)]; ok {
+		return o
+	}
+	return chartDefault(c, path)
+}
+
+// originScope is the position of a table of values while they are coalesced.
+// A nil *originScope records nothing.
+type originScope struct {
+	recorder *originRecorder
+	// chart is the chart the table is coalesced with.
+	chart *chart.Chart
+	// path is the path of the table in the coalesced values, and base the
+	// length of the path of the values of chart.
+	path []string
+	base int
+}
+
+// table returns the scope of the table at key.
+func (s *originScope) table(key string) *originScope {
+	if s == nil {
+		return nil
+	}
+	return &originScope{
+		recorder: s.recorder,
+		chart:    s.chart,
+		path:     append(slices.Clone(s.path), key),
+		base:     s.base,
+	}
+}
+
+// subchart returns the scope of the values of the subchart c at key.
+func (s *originScope) subchart(c *chart.Chart) *originScope {
+	if s == nil {
+		return nil
+	}
+	sub := s.table(c.Name())
+	sub.chart = c
+	sub.base = len(sub.path)
+	return sub
+}
+
+// chartValue records the origin of every leaf of val, which is copied from
+// the values of the chart to key, unless it has one already.
+func (s *originScope) chartValue(key string, val interface{}) {
+	if s == nil {
+		return
+	}
+	s.walk(key, val, func(path []string) {
+		k := joinPath(path...)
+		if _, ok := s.recorder.origins[k]; !ok {
+			s.recorder.origins[k] = s.recorder.originOf(s.chart, path[s.base:])
+		}
+	})
+}
+
+// copied records the origin of every leaf of val, which is copied from the
+// table at from to key, replacing any origin it has.
+func (s *originScope) copied(key string, val interface{}, from *originScope) {
+	if s == nil {
+		return
+	}
+	s.walk(key, val, func(path []string) {
+		src := append(slices.Clone(from.path), path[len(s.path):]...)
+		if o, ok := s.recorder.origins[joinPath(src...)]; ok {
+			s.recorder.origins[joinPath(path...)] = o
+		}
+	})
+}
+
+func (s *originScope) walk(key string, val interface{}, fn func(path []string)) {
+	path := append(slices.Clone(s.path), key)
+	if m, ok := val.(map[string]interface{}); ok && len(m) > 0 {
+		walkLeaves(m, path, func(p []string, _ interface{}) { fn(p) })
+		return
+	}
+	fn(path)
+}
+
+// chartDefault returns the origin of a default of the chart the value at path
+// in the values of c belongs to.
+func chartDefault(c *chart.Chart, path []string) ValueOrigin {
+	for len(path) > 1 {
+		d := dependency(c, path[0])
+		if d == nil {
+			break
+		}
+		c, path = d, path[1:]
+	}
+	return ValueOrigin{Type: OriginChart, Source: c.Name()}
+}
+
+func dependency(c *chart.Chart, name string) *chart.Chart {
+	for _, d := range c.Dependencies() {
+		if d.Name() == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// lookupPath returns the value at path in vals.
+func lookupPath(vals map[string]interface{}, path []string) (interface{}, bool) {
+	var cur interface{} = vals
+	for _, k := range path {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[k]; !ok {
+			return nil, false
+		}
+	}
+	return cur, len(path) > 0
+}
+
+// walkLeaves calls fn with the path of every leaf in vals. Empty maps are
+// considered leaves.
+func walkLeaves(vals map[string]interface{}, prefix []string, fn func(path []string, val interface{})) {
+	for k, v := range vals {
+		path := append(slices.Clone(prefix), k)
+		if m, ok := v.(map[string]interface{}); ok && len(m) > 0 {
+			walkLeaves(m, path, fn)
+			continue
+		}
+		fn(path, v)
+	}
+}
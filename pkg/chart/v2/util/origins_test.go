@@ -0,0 +1,178 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+func TestValueOriginsRecordLayer(t *testing.T) {
+	o := ValueOrigins{}
+	dest := map[string]interface{}{}
+	for _, layer := range []struct {
+		vals   map[string]interface{}
+		origin ValueOrigin
+	}{{
+		vals: map[string]interface{}{
+			"a": map[string]interface{}{"b": 1, "c": 2},
+			"d": "x",
+			"f": map[string]interface{}{"g": 1},
+		},
+		origin: ValueOrigin{Type: OriginFile, Source: "one.yaml"},
+	}, {
+		vals: map[string]interface{}{
+			"a": map[string]interface{}{"b": 3},
+			"d": map[string]interface{}{"e": "y"},
+			"f": "z",
+		},
+		origin: ValueOrigin{Type: OriginSet, Source: "--set a.b=3,d.e=y,f=z"},
+	}} {
+		o.RecordLayer(dest, layer.vals, layer.origin)
+		dest = MergeTables(layer.vals, dest)
+	}
+
+	expect := ValueOrigins{
+		"a.b": {Type: OriginSet, Source: "--set a.b=3,d.e=y,f=z"},
+		"a.c": {Type: OriginFile, Source: "one.yaml"},
+		"d.e": {Type: OriginSet, Source: "--set a.b=3,d.e=y,f=z"},
+		"f":   {Type: OriginSet, Source: "--set a.b=3,d.e=y,f=z"},
+	}
+	if len(o) != len(expect) {
+		t.Fatalf("expected origins %v, got %v", expect, o)
+	}
+	for k, v := range expect {
+		if o[k] != v {
+			t.Errorf("expected origin of %q to be %v, got %v", k, v, o[k])
+		}
+	}
+}
+
+func TestValueOriginsRecordMissing(t *testing.T) {
+	o := ValueOrigins{"a": {Type: OriginSet, Source: "--set a=1"}}
+	previous := ValueOrigins{
+		"b": {Type: OriginFile, Source: "old.yaml"},
+		"c": {Type: OriginImport, Source: "sub: data"},
+	}
+	o.RecordMissing(map[string]interface{}{"a": 1, "b": 2, "c": 3, "d": 4}, previous)
+
+	expect := map[string]ValueOrigin{
+		"a": {Type: OriginSet, Source: "--set a=1"},
+		"b": {Type: OriginFile, Source: "old.yaml"},
+		"c": {Type: OriginUser},
+		"d": {Type: OriginUser},
+	}
+	for k, v := range expect {
+		if o[k] != v {
+			t.Errorf("expected origin of %q to be %v, got %v", k, v, o[k])
+		}
+	}
+}
+
+func TestCoalesceValuesWithOrigins(t *testing.T) {
+	c := loadChart(t, "testdata/subpop")
+
+	origins := ValueOrigins{}
+	vals := map[string]interface{}{
+		"overridden-chart1": map[string]interface{}{"SC1int": 1},
+	}
+	origins.RecordLayer(nil, vals, ValueOrigin{Type: OriginSet, Source: "--set overridden-chart1.SC1int=1"})
+	if err := ProcessDependenciesWithOrigins(c, vals, origins); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CoalesceValuesWithOrigins(c, vals, origins); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, expect := range map[string]string{
+		"overridden-chart1.SC1int":           "--set overridden-chart1.SC1int=1",
+		"overridden-chart1.SC1string":        "chart default (parentchart)",
+		"overridden-chart1.SC1extra1":        "import (subchart1: SC1data)",
+		"imported-chart1.SPextra1":           "chart default (parentchart)",
+		"imported-chart1.SC1bool":            "import (subchart1: SC1data)",
+		"subchart1.service.name":             "chart default (subchart1)",
+		"subchart1.SC1data.SC1int":           "chart default (subchart1)",
+		"subchart1.subcharta.SCAdata.SCAint": "chart default (subcharta)",
+	} {
+		if got := origins[path].String(); got != expect {
+			t.Errorf("expected origin of %q to be %q, got %q", path, expect, got)
+		}
+	}
+}
+
+func TestCoalesceValuesWithOriginsParentOverrides(t *testing.T) {
+	newChart := func() *chart.Chart {
+		sub := &chart.Chart{
+			Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "sub", Version: "0.1.0"},
+			Values:   map[string]interface{}{"a": 1, "b": 2, "c": 3},
+		}
+		parent := &chart.Chart{
+			Metadata: &chart.Metadata{
+				APIVersion:   chart.APIVersionV2,
+				Name:         "parent",
+				Version:      "0.1.0",
+				Dependencies: []*chart.Dependency{{Name: "sub", Version: "0.1.0"}},
+			},
+			Values: map[string]interface{}{
+				// The parent sets a to the default of the subchart.
+				"sub":    map[string]interface{}{"a": 1},
+				"global": map[string]interface{}{"g": true},
+			},
+		}
+		parent.AddDependency(sub)
+		return parent
+	}
+
+	c := newChart()
+	vals := map[string]interface{}{"sub": map[string]interface{}{"c": 4}}
+	origins := ValueOrigins{}
+	origins.RecordLayer(nil, vals, ValueOrigin{Type: OriginFile, Source: "values.yaml"})
+	if err := ProcessDependenciesWithOrigins(c, vals, origins); err != nil {
+		t.Fatal(err)
+	}
+	stored := origins.Compact(c)
+
+	expect := map[string]string{
+		"sub.a":        "parent chart (parent)",
+		"sub.b":        "chart default (sub)",
+		"sub.c":        "file (values.yaml)",
+		"sub.global.g": "chart default (parent)",
+		"global.g":     "chart default (parent)",
+	}
+	if _, err := CoalesceValuesWithOrigins(c, vals, origins); err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range expect {
+		if got := origins[path].String(); got != want {
+			t.Errorf("expected origin of %q to be %q, got %q", path, want, got)
+		}
+	}
+
+	// The compacted origins are enough to recover the others.
+	if _, ok := stored["sub.b"]; ok {
+		t.Error("expected the default of the subchart not to be kept")
+	}
+	if _, err := CoalesceValuesWithOrigins(c, vals, stored); err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range expect {
+		if got := stored[path].String(); got != want {
+			t.Errorf("expected origin of %q to be %q after compacting, got %q", path, want, got)
+		}
+	}
+}
@@ -25,6 +25,8 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"sigs.k8s.io/yaml"
@@ -105,6 +107,30 @@ func SaveDir(c *chart.Chart, dest string) error {
 //
 // This returns the absolute path to the chart archive file.
 func Save(c *chart.Chart, outDir string) (string, error) {
+	return save(c, outDir, saveOptions{modTime: time.Now()})
+}
+
+// SaveReproducible creates an archived chart like Save, but the archive is
+// byte-for-byte identical every time the same chart is saved with the same
+// modTime.
+//
+// Every file in the archive gets modTime, truncated to the second, as its
+// modification time. Files are written in a stable order, and are owned by
+// root with mode 0644.
+func SaveReproducible(c *chart.Chart, outDir string, modTime time.Time) (string, error) {
+	return save(c, outDir, saveOptions{modTime: modTime.UTC().Truncate(time.Second), reproducible: true})
+}
+
+// saveOptions controls how a chart is written to an archive.
+type saveOptions struct {
+	// modTime is the modification time of every file in the archive.
+	modTime time.Time
+	// reproducible sorts the templates, files and dependencies of a chart by
+	// name before they are written.
+	reproducible bool
+}
+
+func save(c *chart.Chart, outDir string, opts saveOptions) (string, error) {
 	if err := c.Validate(); err != nil {
 		return "", fmt.Errorf("chart validation: %w", err)
 	}
@@ -129,7 +155,8 @@ func Save(c *chart.Chart, outDir string) (string, error) {
 		return "", err
 	}
 
-	// Wrap in gzip writer
+	// Wrap in gzip writer. The gzip header carries no name or modification
+	// time, so it only depends on the contents.
 	zipper := gzip.NewWriter(f)
 	zipper.Extra = headerBytes
 	zipper.Comment = "Helm"
@@ -146,14 +173,14 @@ func Save(c *chart.Chart, outDir string) (string, error) {
 		}
 	}()
 
-	if err := writeTarContents(twriter, c, ""); err != nil {
+	if err := writeTarContents(twriter, c, "", opts); err != nil {
 		rollback = true
 		return filename, err
 	}
 	return filename, nil
 }
 
-func writeTarContents(out *tar.Writer, c *chart.Chart, prefix string) error {
+func writeTarContents(out *tar.Writer, c *chart.Chart, prefix string, opts saveOptions) error {
 	err := validateName(c.Name())
 	if err != nil {
 		return err
@@ -174,7 +201,7 @@ func writeTarContents(out *tar.Writer, c *chart.Chart, prefix string) error {
 	if err != nil {
 		return err
 	}
-	if err := writeToTar(out, filepath.Join(base, ChartfileName), cdata); err != nil {
+	if err := writeToTar(out, filepath.Join(base, ChartfileName), cdata, opts); err != nil {
 		return err
 	}
 
@@ -186,7 +213,7 @@ func writeTarContents(out *tar.Writer, c *chart.Chart, prefix string) error {
 			if err != nil {
 				return err
 			}
-			if err := writeToTar(out, filepath.Join(base, "Chart.lock"), ldata); err != nil {
+			if err := writeToTar(out, filepath.Join(base, "Chart.lock"), ldata, opts); err != nil {
 				return err
 			}
 		}
@@ -195,7 +222,7 @@ func writeTarContents(out *tar.Writer, c *chart.Chart, prefix string) error {
 	// Save values.yaml
 	for _, f := range c.Raw {
 		if f.Name == ValuesfileName {
-			if err := writeToTar(out, filepath.Join(base, ValuesfileName), f.Data); err != nil {
+			if err := writeToTar(out, filepath.Join(base, ValuesfileName), f.Data, opts); err != nil {
 				return err
 			}
 		}
@@ -206,44 +233,59 @@ func writeTarContents(out *tar.Writer, c *chart.Chart, prefix string) error {
 		if !json.Valid(c.Schema) {
 			return errors.New("invalid JSON in " + SchemafileName)
 		}
-		if err := writeToTar(out, filepath.Join(base, SchemafileName), c.Schema); err != nil {
+		if err := writeToTar(out, filepath.Join(base, SchemafileName), c.Schema, opts); err != nil {
 			return err
 		}
 	}
 
+	templates, files, deps := c.Templates, c.Files, c.Dependencies()
+	if opts.reproducible {
+		templates, files = sortedFiles(templates), sortedFiles(files)
+		deps = slices.SortedStableFunc(slices.Values(deps), func(a, b *chart.Chart) int {
+			return strings.Compare(a.Name(), b.Name())
+		})
+	}
+
 	// Save templates
-	for _, f := range c.Templates {
+	for _, f := range templates {
 		n := filepath.Join(base, f.Name)
-		if err := writeToTar(out, n, f.Data); err != nil {
+		if err := writeToTar(out, n, f.Data, opts); err != nil {
 			return err
 		}
 	}
 
 	// Save files
-	for _, f := range c.Files {
+	for _, f := range files {
 		n := filepath.Join(base, f.Name)
-		if err := writeToTar(out, n, f.Data); err != nil {
+		if err := writeToTar(out, n, f.Data, opts); err != nil {
 			return err
 		}
 	}
 
 	// Save dependencies
-	for _, dep := range c.Dependencies() {
-		if err := writeTarContents(out, dep, filepath.Join(base, ChartsDir)); err != nil {
+	for _, dep := range deps {
+		if err := writeTarContents(out, dep, filepath.Join(base, ChartsDir), opts); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// sortedFiles returns a copy of files sorted by name.
+func sortedFiles(files []*chart.File) []*chart.File {
+	return slices.SortedStableFunc(slices.Values(files), func(a, b *chart.File) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
 // writeToTar writes a single file to a tar archive.
-func writeToTar(out *tar.Writer, name string, body []byte) error {
+func writeToTar(out *tar.Writer, name string, body []byte, opts saveOptions) error {
 	// TODO: Do we need to create dummy parent directory names if none exist?
 	h := &tar.Header{
 		Name:    filepath.ToSlash(name),
 		Mode:    0644,
 		Size:    int64(len(body)),
-		ModTime: time.Now(),
+		ModTime: opts.modTime,
 	}
 	if err := out.WriteHeader(h); err != nil {
 		return err
@@ -179,6 +179,79 @@ func TestSavePreservesTimestamps(t *testing.T) {
 	}
 }
 
+func TestSaveReproducible(t *testing.T) {
+	modTime := time.Date(2020, 5, 4, 3, 2, 1, 500, time.UTC)
+	newChart := func(files ...*chart.File) *chart.Chart {
+		return &chart.Chart{
+			Metadata: &chart.Metadata{
+				APIVersion: chart.APIVersionV2,
+				Name:       "ahab",
+				Version:    "1.2.3",
+			},
+			Templates: []*chart.File{
+				{Name: "templates/service.yaml", Data: []byte("kind: Service")},
+				{Name: "templates/deployment.yaml", Data: []byte("kind: Deployment")},
+			},
+			Files: files,
+		}
+	}
+
+	first := newChart(
+		&chart.File{Name: "scheherazade/shahryar.txt", Data: []byte("1,001 Nights")},
+		&chart.File{Name: "moby/dick.txt", Data: []byte("Call me Ishmael")},
+	)
+	where, err := SaveReproducible(first, t.TempDir(), modTime)
+	if err != nil {
+		t.Fatalf("Failed to save: %s", err)
+	}
+	expected, err := os.ReadFile(where)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The order of the files in the chart does not matter.
+	second := newChart(
+		&chart.File{Name: "moby/dick.txt", Data: []byte("Call me Ishmael")},
+		&chart.File{Name: "scheherazade/shahryar.txt", Data: []byte("1,001 Nights")},
+	)
+	where, err = SaveReproducible(second, t.TempDir(), modTime)
+	if err != nil {
+		t.Fatalf("Failed to save: %s", err)
+	}
+	actual, err := os.ReadFile(where)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Fatal("Expected the archives to be identical")
+	}
+
+	headers, err := retrieveAllHeadersFromTar(where)
+	if err != nil {
+		t.Fatalf("Failed to parse tar: %v", err)
+	}
+	var names []string
+	for _, h := range headers {
+		names = append(names, h.Name)
+		if !h.ModTime.Equal(modTime.Truncate(time.Second)) {
+			t.Errorf("Expected %s to be timestamped %s, got %s", h.Name, modTime.Truncate(time.Second), h.ModTime)
+		}
+		if h.Mode != 0644 || h.Uid != 0 || h.Gid != 0 || h.Uname != "" || h.Gname != "" {
+			t.Errorf("Expected normalized ownership and mode for %s, got %o %d:%d %s:%s", h.Name, h.Mode, h.Uid, h.Gid, h.Uname, h.Gname)
+		}
+	}
+	expectedNames := []string{
+		"ahab/Chart.yaml",
+		"ahab/templates/deployment.yaml",
+		"ahab/templates/service.yaml",
+		"ahab/moby/dick.txt",
+		"ahab/scheherazade/shahryar.txt",
+	}
+	if strings.Join(names, ",") != strings.Join(expectedNames, ",") {
+		t.Errorf("Expected files %v, got %v", expectedNames, names)
+	}
+}
+
 // We could refactor `load.go` to use this `retrieveAllHeadersFromTar` function
 // as well, so we are not duplicating components of the code which iterate
 // through the tar.
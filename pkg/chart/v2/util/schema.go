@@ -0,0 +1,262 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+// SchemaDraft is the JSON Schema dialect of generated schemas.
+const SchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// schemaAnnotation is the comment prefix of annotations that control the
+// generated schema, e.g. "# @schema required".
+const schemaAnnotation = "@schema"
+
+// GenerateValuesSchema infers a JSON Schema from the values.yaml of the chart
+// and of its dependencies.
+//
+// Types are inferred from the values. A key is marked as required by
+// annotating it with a "# @schema required" comment.
+func GenerateValuesSchema(c *chart.Chart) ([]byte, error) {
+	schema, err := generateChartSchema(c)
+	if err != nil {
+		return nil, err
+	}
+	schema["$schema"] = SchemaDraft
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// GenerateSchema infers a JSON Schema from a YAML document of values.
+func GenerateSchema(data []byte) (map[string]interface{}, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return map[string]interface{}{"type": "object"}, nil
+	}
+	root := resolveAlias(doc.Content[0])
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("values must be a map, got %s", root.Tag)
+	}
+	return nodeSchema(root)
+}
+
+func generateChartSchema(c *chart.Chart) (map[string]interface{}, error) {
+	var data []byte
+	for _, f := range c.Raw {
+		if f.Name == ValuesfileName {
+			data = f.Data
+			break
+		}
+	}
+	schema, err := GenerateSchema(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.Name(), err)
+	}
+
+	// Dependencies are keyed by their alias. Charts in charts/ that are not
+	// listed in Chart.yaml are keyed by their name.
+	keys := map[string]*chart.Chart{}
+	listed := map[string]bool{}
+	if c.Metadata != nil {
+		for _, d := range c.Metadata.Dependencies {
+			if sub := dependency(c, d.Name); sub != nil {
+				key := d.Name
+				if d.Alias != "" {
+					key = d.Alias
+				}
+				keys[key] = sub
+				listed[d.Name] = true
+			}
+		}
+	}
+	for _, sub := range c.Dependencies() {
+		if !listed[sub.Name()] {
+			keys[sub.Name()] = sub
+		}
+	}
+
+	for key, sub := range keys {
+		subSchema, err := generateChartSchema(sub)
+		if err != nil {
+			return nil, err
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		if props == nil {
+			props = map[string]interface{}{}
+			schema["properties"] = props
+		}
+		// Values set by the parent for a subchart are merged over the
+		// defaults of the subchart.
+		if parent, ok := props[key].(map[string]interface{}); ok {
+			mergeSchemas(subSchema, parent)
+		}
+		props[key] = subSchema
+	}
+	return schema, nil
+}
+
+// nodeSchema returns the schema of a YAML node.
+func nodeSchema(n *yaml.Node) (map[string]interface{}, error) {
+	n = resolveAlias(n)
+	schema := map[string]interface{}{}
+	switch n.Kind {
+	case yaml.MappingNode:
+		schema["type"] = "object"
+		props := map[string]interface{}{}
+		var required []string
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			// Merge keys copy the values of another mapping.
+			if k.Tag == "!!merge" {
+				merged, err := nodeSchema(v)
+				if err != nil {
+					return nil, err
+				}
+				if mp, ok := merged["properties"].(map[string]interface{}); ok {
+					for mk, mv := range mp {
+						if _, ok := props[mk]; !ok {
+							props[mk] = mv
+						}
+					}
+				}
+				continue
+			}
+			prop, err := nodeSchema(v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", k.Line, err)
+			}
+			annotations, err := parseSchemaAnnotations(k.HeadComment, k.LineComment, v.LineComment)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", k.Line, err)
+			}
+			if slices.Contains(annotations, "required") {
+				required = append(required, k.Value)
+			}
+			props[k.Value] = prop
+		}
+		if len(props) > 0 {
+			schema["properties"] = props
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	case yaml.SequenceNode:
+		schema["type"] = "array"
+		// Only describe the items when they all share the same type.
+		var items map[string]interface{}
+		for i, item := range n.Content {
+			s, err := nodeSchema(item)
+			if err != nil {
+				return nil, err
+			}
+			if i == 0 {
+				items = s
+				continue
+			}
+			if items["type"] != s["type"] {
+				items = nil
+				break
+			}
+			mergeSchemas(items, s)
+		}
+		if items != nil {
+			schema["items"] = items
+		}
+	case yaml.ScalarNode:
+		switch n.ShortTag() {
+		case "!!str", "!!binary", "!!timestamp":
+			schema["type"] = "string"
+		case "!!int":
+			schema["type"] = "integer"
+		case "!!float":
+			schema["type"] = "number"
+		case "!!bool":
+			schema["type"] = "boolean"
+		}
+		// Null values leave the type open.
+	}
+	return schema, nil
+}
+
+// mergeSchemas merges the properties and required keys of src into dst.
+func mergeSchemas(dst, src map[string]interface{}) {
+	if t, ok := src["type"]; ok {
+		dst["type"] = t
+	}
+	if sp, ok := src["properties"].(map[string]interface{}); ok {
+		dp, _ := dst["properties"].(map[string]interface{})
+		if dp == nil {
+			dp = map[string]interface{}{}
+			dst["properties"] = dp
+		}
+		for k, v := range sp {
+			vs, ok := v.(map[string]interface{})
+			if ds, exists := dp[k].(map[string]interface{}); exists && ok && vs["type"] == ds["type"] {
+				mergeSchemas(ds, vs)
+				continue
+			}
+			dp[k] = v
+		}
+	}
+	if sr, ok := src["required"].([]string); ok {
+		dr, _ := dst["required"].([]string)
+		for _, r := range sr {
+			if !slices.Contains(dr, r) {
+				dr = append(dr, r)
+			}
+		}
+		dst["required"] = dr
+	}
+}
+
+// parseSchemaAnnotations returns the "@schema" annotations in the comments.
+func parseSchemaAnnotations(comments ...string) ([]string, error) {
+	var annotations []string
+	for _, c := range comments {
+		for _, line := range strings.Split(c, "\n") {
+			line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+			rest, ok := strings.CutPrefix(line, schemaAnnotation)
+			if !ok {
+				continue
+			}
+			for _, a := range strings.Fields(rest) {
+				if a != "required" {
+					return nil, fmt.Errorf("unknown schema annotation %q", a)
+				}
+				annotations = append(annotations, a)
+			}
+		}
+	}
+	return annotations, nil
+}
+
+func resolveAlias(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	return n
+}
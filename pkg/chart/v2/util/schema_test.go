@@ -0,0 +1,146 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateSchema(t *testing.T) {
+	values := `
+# @schema required
+name: frobnitz
+replicas: 3
+ratio: 0.5
+enabled: true
+empty: null
+image: # @schema required
+  repository: nginx
+  # The tag to deploy.
+  # @schema required
+  tag: "1.27"
+ports:
+  - 80
+  - 443
+mixed:
+  - 1
+  - one
+defaults: &defaults
+  timeout: 10
+override:
+  <<: *defaults
+  retries: 2
+`
+	schema, err := GenerateSchema([]byte(values))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := json.Marshal(schema)
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	expect := `{
+  "type": "object",
+  "required": ["name", "image"],
+  "properties": {
+    "name": {"type": "string"},
+    "replicas": {"type": "integer"},
+    "ratio": {"type": "number"},
+    "enabled": {"type": "boolean"},
+    "empty": {},
+    "image": {
+      "type": "object",
+      "required": ["tag"],
+      "properties": {
+        "repository": {"type": "string"},
+        "tag": {"type": "string"}
+      }
+    },
+    "ports": {"type": "array", "items": {"type": "integer"}},
+    "mixed": {"type": "array"},
+    "defaults": {
+      "type": "object",
+      "properties": {"timeout": {"type": "integer"}}
+    },
+    "override": {
+      "type": "object",
+      "properties": {
+        "timeout": {"type": "integer"},
+        "retries": {"type": "integer"}
+      }
+    }
+  }
+}`
+	var want map[string]interface{}
+	if err := json.Unmarshal([]byte(expect), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected schema:\n%s", data)
+	}
+}
+
+func TestGenerateSchemaErrors(t *testing.T) {
+	for name, values := range map[string]string{
+		"not a map":          "- one\n- two\n",
+		"unknown annotation": "# @schema optional\nname: frobnitz\n",
+		"invalid yaml":       "name: [frobnitz\n",
+	} {
+		if _, err := GenerateSchema([]byte(values)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestGenerateValuesSchema(t *testing.T) {
+	c := loadChart(t, "testdata/subpop")
+
+	data, err := GenerateValuesSchema(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateAgainstSingleSchema(c.Values, data); err != nil {
+		t.Fatalf("expected the values of the chart to match the generated schema: %s", err)
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema["$schema"] != SchemaDraft {
+		t.Errorf("expected $schema to be %q, got %v", SchemaDraft, schema["$schema"])
+	}
+
+	props := schema["properties"].(map[string]interface{})
+	for _, key := range []string{"subchart1", "subchart2", "subchart2alias"} {
+		if _, ok := props[key]; !ok {
+			t.Errorf("expected a schema for the values of dependency %s", key)
+		}
+	}
+
+	// The parent's values are merged over the subchart's own.
+	alias := props["subchart2alias"].(map[string]interface{})["properties"].(map[string]interface{})
+	for _, key := range []string{"enabled", "replicaCount"} {
+		if _, ok := alias[key]; !ok {
+			t.Errorf("expected subchart2alias to describe %s", key)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+// StarterfileName is the name of the file that declares the parameters of a
+// starter. It is not copied to the charts created from the starter.
+const StarterfileName = "starter.yaml"
+
+// starterParameterName is a regular expression for testing the name of a
+// starter parameter. Names are upper case, like the <CHARTNAME> placeholder.
+var starterParameterName = regexp.MustCompile("^[A-Z][A-Z0-9_]*$")
+
+// StarterParameter is a parameter of a starter.
+//
+// Every occurrence of <NAME> in the templates and the values of the starter
+// is replaced with the value of the parameter NAME when a chart is created.
+type StarterParameter struct {
+	// Name is the name of the parameter, e.g. SERVICE_PORT.
+	Name string `json:"name"`
+	// Description describes the parameter when it is prompted for.
+	Description string `json:"description,omitempty"`
+	// Type is the type of the value, "string" or "bool". It defaults to "string".
+	Type string `json:"type,omitempty"`
+	// Default is the value of the parameter when none is given.
+	Default string `json:"default,omitempty"`
+	// Required parameters need a value when there is no default.
+	Required bool `json:"required,omitempty"`
+	// Files are the files of the starter that are only created when the
+	// value of a bool parameter is true, e.g. templates/ingress.yaml.
+	Files []string `json:"files,omitempty"`
+}
+
+// Starterfile declares the parameters of a starter.
+type Starterfile struct {
+	Parameters []*StarterParameter `json:"parameters,omitempty"`
+}
+
+// Validate checks a value of the parameter and returns it in its canonical
+// form.
+func (p *StarterParameter) Validate(value string) (string, error) {
+	if p.Type != "bool" {
+		return value, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return "", fmt.Errorf("parameter %s must be true or false, got %q", p.Name, value)
+	}
+	return strconv.FormatBool(b), nil
+}
+
+func (s *Starterfile) validate() error {
+	var names []string
+	for _, p := range s.Parameters {
+		if !starterParameterName.MatchString(p.Name) {
+			return fmt.Errorf("invalid parameter name %q: must match %s", p.Name, starterParameterName)
+		}
+		if p.Name == "CHARTNAME" || slices.Contains(names, p.Name) {
+			return fmt.Errorf("parameter %s is declared more than once", p.Name)
+		}
+		names = append(names, p.Name)
+		switch p.Type {
+		case "", "string":
+			if len(p.Files) > 0 {
+				return fmt.Errorf("parameter %s: only bool parameters can select files", p.Name)
+			}
+		case "bool":
+		default:
+			return fmt.Errorf("parameter %s: unknown type %q", p.Name, p.Type)
+		}
+		if p.Default != "" {
+			if _, err := p.Validate(p.Default); err != nil {
+				return fmt.Errorf("invalid default: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// loadStarterfile returns the starter file of a starter. A starter without
+// one has no parameters.
+func loadStarterfile(c *chart.Chart) (*Starterfile, error) {
+	s := &Starterfile{}
+	for _, f := range c.Files {
+		if f.Name == StarterfileName {
+			if err := yaml.UnmarshalStrict(f.Data, s); err != nil {
+				return nil, fmt.Errorf("cannot load %s: %w", StarterfileName, err)
+			}
+		}
+	}
+	return s, s.validate()
+}
+
+// StarterParameters returns the parameters declared by the starter at src.
+func StarterParameters(src string) ([]*StarterParameter, error) {
+	schart, err := loadStarter(src)
+	if err != nil {
+		return nil, err
+	}
+	s, err := loadStarterfile(schart)
+	if err != nil {
+		return nil, err
+	}
+	return s.Parameters, nil
+}
+
+// resolveStarterParameters returns the values of the parameters of a starter,
+// falling back to their defaults.
+func resolveStarterParameters(s *Starterfile, params map[string]string) (map[string]string, error) {
+	values := map[string]string{}
+	for name := range params {
+		if !slices.ContainsFunc(s.Parameters, func(p *StarterParameter) bool { return p.Name == name }) {
+			return nil, fmt.Errorf("the starter has no parameter %s", name)
+		}
+	}
+	for _, p := range s.Parameters {
+		value, ok := params[p.Name]
+		if !ok {
+			value = p.Default
+		}
+		if value == "" {
+			if p.Required {
+				return nil, fmt.Errorf("parameter %s is required", p.Name)
+			}
+			if p.Type == "bool" {
+				value = "false"
+			}
+		}
+		value, err := p.Validate(value)
+		if err != nil {
+			return nil, err
+		}
+		values[p.Name] = value
+	}
+	return values, nil
+}
+
+// excludedStarterFiles returns the files of the starter that are not created
+// because their bool parameter is false.
+func excludedStarterFiles(s *Starterfile, values map[string]string) []string {
+	excluded := []string{StarterfileName}
+	for _, p := range s.Parameters {
+		if p.Type == "bool" && values[p.Name] == "false" {
+			excluded = append(excluded, p.Files...)
+		}
+	}
+	return excluded
+}
+
+// substituteParameters replaces the placeholders of the parameters in src.
+func substituteParameters(src []byte, values map[string]string) []byte {
+	if len(values) == 0 {
+		return src
+	}
+	oldnew := make([]string, 0, 2*len(values))
+	for name, value := range values {
+		oldnew = append(oldnew, "<"+name+">", value)
+	}
+	return []byte(strings.NewReplacer(oldnew...).Replace(string(src)))
+}
@@ -0,0 +1,147 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+// writeStarter writes a starter with the given starter file.
+func writeStarter(t *testing.T, starterfile string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "starter")
+	files := map[string]string{
+		ChartfileName:                         "apiVersion: v2\nname: starter\nversion: 0.1.0\n",
+		ValuesfileName:                        "name: <CHARTNAME>\nreplicas: <REPLICAS>\n",
+		StarterfileName:                       starterfile,
+		filepath.Join(TemplatesDir, "a.yaml"): "replicas: <REPLICAS>\nmonitoring: <MONITORING>\n",
+		filepath.Join(TemplatesDir, "b.yaml"): "kind: ServiceMonitor\n",
+	}
+	for name, data := range files {
+		if err := writeFile(filepath.Join(dir, name), []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+const testStarterfile = `parameters:
+- name: REPLICAS
+  default: "1"
+- name: MONITORING
+  type: bool
+  files: [templates/b.yaml]
+- name: OWNER
+  required: true
+`
+
+func TestCreateFromWithParameters(t *testing.T) {
+	src := writeStarter(t, testStarterfile)
+	cf := &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "foo", Version: "0.1.0"}
+
+	tests := []struct {
+		name   string
+		params map[string]string
+		files  map[string]string
+		err    string
+	}{
+		{
+			name:   "defaults",
+			params: map[string]string{"OWNER": "me"},
+			files: map[string]string{
+				ValuesfileName:                        "name: foo\nreplicas: 1\n",
+				filepath.Join(TemplatesDir, "a.yaml"): "replicas: 1\nmonitoring: false\n",
+				filepath.Join(TemplatesDir, "b.yaml"): "",
+				StarterfileName:                       "",
+			},
+		},
+		{
+			name:   "values",
+			params: map[string]string{"OWNER": "me", "REPLICAS": "3", "MONITORING": "1"},
+			files: map[string]string{
+				ValuesfileName:                        "name: foo\nreplicas: 3\n",
+				filepath.Join(TemplatesDir, "a.yaml"): "replicas: 3\nmonitoring: true\n",
+				filepath.Join(TemplatesDir, "b.yaml"): "kind: ServiceMonitor\n",
+			},
+		},
+		{
+			name: "missing required parameter",
+			err:  "parameter OWNER is required",
+		},
+		{
+			name:   "unknown parameter",
+			params: map[string]string{"OWNER": "me", "PORT": "80"},
+			err:    "the starter has no parameter PORT",
+		},
+		{
+			name:   "invalid bool",
+			params: map[string]string{"OWNER": "me", "MONITORING": "sometimes"},
+			err:    `parameter MONITORING must be true or false, got "sometimes"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := t.TempDir()
+			err := CreateFromWithParameters(cf, dest, src, tt.params)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, expect := range tt.files {
+				data, err := os.ReadFile(filepath.Join(dest, "foo", name))
+				if expect == "" {
+					if err == nil {
+						t.Errorf("expected %s not to be created", name)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != expect {
+					t.Errorf("expected %s to be %q, got %q", name, expect, data)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidStarterfile(t *testing.T) {
+	for starterfile, expect := range map[string]string{
+		"parameters:\n- name: port\n":                              "invalid parameter name",
+		"parameters:\n- name: PORT\n- name: PORT\n":                "declared more than once",
+		"parameters:\n- name: PORT\n  type: int\n":                 "unknown type",
+		"parameters:\n- name: PORT\n  files: [a.yaml]\n":           "only bool parameters can select files",
+		"parameters:\n- name: ENABLED\n  type: bool\n  default: x": "invalid default",
+		"params: []\n": "cannot load starter.yaml",
+	} {
+		_, err := StarterParameters(writeStarter(t, starterfile))
+		if err == nil || !strings.Contains(err.Error(), expect) {
+			t.Errorf("expected an error containing %q for %q, got %v", expect, starterfile, err)
+		}
+	}
+}
@@ -77,6 +77,8 @@ type EnvSettings struct {
 	Debug bool
 	// RegistryConfig is the path to the registry config file.
 	RegistryConfig string
+	// RegistryMirrorsConfig is the path to the registry mirrors file.
+	RegistryMirrorsConfig string
 	// RepositoryConfig is the path to the repositories file.
 	RepositoryConfig string
 	// RepositoryCache is the path to the repository cache directory.
@@ -105,6 +107,7 @@ func New() *EnvSettings {
 		KubeInsecureSkipTLSVerify: envBoolOr("HELM_KUBEINSECURE_SKIP_TLS_VERIFY", false),
 		PluginsDirectory:          envOr("HELM_PLUGINS", helmpath.DataPath("plugins")),
 		RegistryConfig:            envOr("HELM_REGISTRY_CONFIG", helmpath.ConfigPath("registry/config.json")),
+		RegistryMirrorsConfig:     envOr("HELM_REGISTRY_MIRRORS_CONFIG", helmpath.ConfigPath("registry/mirrors.yaml")),
 		RepositoryConfig:          envOr("HELM_REPOSITORY_CONFIG", helmpath.ConfigPath("repositories.yaml")),
 		RepositoryCache:           envOr("HELM_REPOSITORY_CACHE", helmpath.CachePath("repository")),
 		BurstLimit:                envIntOr("HELM_BURST_LIMIT", defaultBurstLimit),
@@ -156,6 +159,7 @@ func (s *EnvSettings) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&s.KubeInsecureSkipTLSVerify, "kube-insecure-skip-tls-verify", s.KubeInsecureSkipTLSVerify, "if true, the Kubernetes API server's certificate will not be checked for validity. This will make your HTTPS connections insecure")
 	fs.BoolVar(&s.Debug, "debug", s.Debug, "enable verbose output")
 	fs.StringVar(&s.RegistryConfig, "registry-config", s.RegistryConfig, "path to the registry config file")
+	fs.StringVar(&s.RegistryMirrorsConfig, "registry-mirrors-config", s.RegistryMirrorsConfig, "path to the registry mirrors file")
 	fs.StringVar(&s.RepositoryConfig, "repository-config", s.RepositoryConfig, "path to the file containing repository names and URLs")
 	fs.StringVar(&s.RepositoryCache, "repository-cache", s.RepositoryCache, "path to the directory containing cached repository indexes")
 	fs.IntVar(&s.BurstLimit, "burst-limit", s.BurstLimit, "client-side default throttling limit")
@@ -215,19 +219,20 @@ func envCSV(name string) (ls []string) {
 
 func (s *EnvSettings) EnvVars() map[string]string {
 	envvars := map[string]string{
-		"HELM_BIN":               os.Args[0],
-		"HELM_CACHE_HOME":        helmpath.CachePath(""),
-		"HELM_CONFIG_HOME":       helmpath.ConfigPath(""),
-		"HELM_DATA_HOME":         helmpath.DataPath(""),
-		"HELM_DEBUG":             fmt.Sprint(s.Debug),
-		"HELM_PLUGINS":           s.PluginsDirectory,
-		"HELM_REGISTRY_CONFIG":   s.RegistryConfig,
-		"HELM_REPOSITORY_CACHE":  s.RepositoryCache,
-		"HELM_REPOSITORY_CONFIG": s.RepositoryConfig,
-		"HELM_NAMESPACE":         s.Namespace(),
-		"HELM_MAX_HISTORY":       strconv.Itoa(s.MaxHistory),
-		"HELM_BURST_LIMIT":       strconv.Itoa(s.BurstLimit),
-		"HELM_QPS":               strconv.FormatFloat(float64(s.QPS), 'f', 2, 32),
+		"HELM_BIN":                     os.Args[0],
+		"HELM_CACHE_HOME":              helmpath.CachePath(""),
+		"HELM_CONFIG_HOME":             helmpath.ConfigPath(""),
+		"HELM_DATA_HOME":               helmpath.DataPath(""),
+		"HELM_DEBUG":                   fmt.Sprint(s.Debug),
+		"HELM_PLUGINS":                 s.PluginsDirectory,
+		"HELM_REGISTRY_CONFIG":         s.RegistryConfig,
+		"HELM_REGISTRY_MIRRORS_CONFIG": s.RegistryMirrorsConfig,
+		"HELM_REPOSITORY_CACHE":        s.RepositoryCache,
+		"HELM_REPOSITORY_CONFIG":       s.RepositoryConfig,
+		"HELM_NAMESPACE":               s.Namespace(),
+		"HELM_MAX_HISTORY":             strconv.Itoa(s.MaxHistory),
+		"HELM_BURST_LIMIT":             strconv.Itoa(s.BurstLimit),
+		"HELM_QPS":                     strconv.FormatFloat(float64(s.QPS), 'f', 2, 32),
 
 		// broken, these are populated from helm flags and not kubeconfig.
 		"HELM_KUBECONTEXT":                  s.KubeContext,
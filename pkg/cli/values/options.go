@@ -21,11 +21,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/url"
 	"os"
+	"slices"
 	"strings"
 
 	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
 	"helm.sh/helm/v4/pkg/getter"
 	"helm.sh/helm/v4/pkg/strvals"
 )
@@ -43,8 +46,47 @@ type Options struct {
 // MergeValues merges values from files specified via -f/--values and directly
 // via --set-json, --set, --set-string, or --set-file, marshaling them to YAML
 func (opts *Options) MergeValues(p getter.Providers) (map[string]interface{}, error) {
+	return opts.mergeValues(p, nil)
+}
+
+// MergeValuesWithOrigins merges values like MergeValues, and also returns the
+// file or flag that each of the merged values came from.
+func (opts *Options) MergeValuesWithOrigins(p getter.Providers) (map[string]interface{}, chartutil.ValueOrigins, error) {
+	origins := make(chartutil.ValueOrigins)
+	vals, err := opts.mergeValues(p, origins)
+	if err != nil {
+		return nil, nil, err
+	}
+	return vals, origins, nil
+}
+
+func (opts *Options) mergeValues(p getter.Providers, origins chartutil.ValueOrigins) (map[string]interface{}, error) {
 	base := map[string]interface{}{}
 
+	// record parses the key=value pairs of a flag value on their own to find
+	// out which values each of them sets, and records the pair as their
+	// origin. It must be called before the value is merged into base.
+	record := func(flag, value string, parse func(string, map[string]interface{}) error) {
+		if origins == nil {
+			return
+		}
+		pairs := splitPairs(value, flag == "--set-json")
+		layers := make([]map[string]interface{}, len(pairs))
+		for i, pair := range pairs {
+			layers[i] = map[string]interface{}{}
+			if err := parse(pair, layers[i]); err != nil {
+				// The pairs could not be parsed on their own, so record the
+				// value as a whole.
+				pairs, layers = []string{value}, []map[string]interface{}{{}}
+				if err := parse(value, layers[0]); err != nil {
+					return
+				}
+				break
+			}
+		}
+		recordPairs(origins, base, flag, pairs, layers)
+	}
+
 	// User specified a values files via -f/--values
 	for _, filePath := range opts.ValueFiles {
 		raw, err := readFile(filePath, p)
@@ -55,6 +97,9 @@ func (opts *Options) MergeValues(p getter.Providers) (map[string]interface{}, er
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
 		}
+		if origins != nil {
+			origins.RecordLayer(base, currentMap, chartutil.ValueOrigin{Type: chartutil.OriginFile, Source: filePath})
+		}
 		// Merge with the previous map
 		base = loader.MergeMaps(base, currentMap)
 	}
@@ -68,9 +113,13 @@ func (opts *Options) MergeValues(p getter.Providers) (map[string]interface{}, er
 			if err := json.Unmarshal([]byte(trimmedValue), &jsonMap); err != nil {
 				return nil, fmt.Errorf("failed parsing --set-json data JSON: %s", value)
 			}
+			if origins != nil {
+				recordJSONObject(origins, base, jsonMap)
+			}
 			base = loader.MergeMaps(base, jsonMap)
 		} else {
 			// Otherwise, parse it as key=value format
+			record("--set-json", value, strvals.ParseJSON)
 			if err := strvals.ParseJSON(value, base); err != nil {
 				return nil, fmt.Errorf("failed parsing --set-json data %s", value)
 			}
@@ -79,6 +128,7 @@ func (opts *Options) MergeValues(p getter.Providers) (map[string]interface{}, er
 
 	// User specified a value via --set
 	for _, value := range opts.Values {
+		record("--set", value, strvals.ParseInto)
 		if err := strvals.ParseInto(value, base); err != nil {
 			return nil, fmt.Errorf("failed parsing --set data: %w", err)
 		}
@@ -86,6 +136,7 @@ func (opts *Options) MergeValues(p getter.Providers) (map[string]interface{}, er
 
 	// User specified a value via --set-string
 	for _, value := range opts.StringValues {
+		record("--set-string", value, strvals.ParseIntoString)
 		if err := strvals.ParseIntoString(value, base); err != nil {
 			return nil, fmt.Errorf("failed parsing --set-string data: %w", err)
 		}
@@ -100,6 +151,11 @@ func (opts *Options) MergeValues(p getter.Providers) (map[string]interface{}, er
 			}
 			return string(bytes), err
 		}
+		// The file may be stdin, so don't read it just to find out which
+		// key it sets.
+		record("--set-file", value, func(pair string, m map[string]interface{}) error {
+			return strvals.ParseIntoFile(pair, m, func([]rune) (interface{}, error) { return "", nil })
+		})
 		if err := strvals.ParseIntoFile(value, base, reader); err != nil {
 			return nil, fmt.Errorf("failed parsing --set-file data: %w", err)
 		}
@@ -107,6 +163,14 @@ func (opts *Options) MergeValues(p getter.Providers) (map[string]interface{}, er
 
 	// User specified a value via --set-literal
 	for _, value := range opts.LiteralValues {
+		// The value of a literal pair may contain commas, so it is a single
+		// pair.
+		if origins != nil {
+			layer := map[string]interface{}{}
+			if err := strvals.ParseLiteralInto(value, layer); err == nil {
+				recordPairs(origins, base, "--set-literal", []string{value}, []map[string]interface{}{layer})
+			}
+		}
 		if err := strvals.ParseLiteralInto(value, base); err != nil {
 			return nil, fmt.Errorf("failed parsing --set-literal data: %w", err)
 		}
@@ -115,6 +179,67 @@ func (opts *Options) MergeValues(p getter.Providers) (map[string]interface{}, er
 	return base, nil
 }
 
+// recordPairs records each key=value pair of a flag as the origin of the
+// values it sets. The pairs are recorded in order, so a pair replaces the
+// origins of the values of earlier pairs that it replaces.
+func recordPairs(origins chartutil.ValueOrigins, base map[string]interface{}, flag string, pairs []string, layers []map[string]interface{}) {
+	for i, pair := range pairs {
+		origins.RecordLayer(base, layers[i], chartutil.ValueOrigin{Type: chartutil.OriginSet, Source: flag + " " + pair})
+		base = loader.MergeMaps(base, layers[i])
+	}
+}
+
+// recordJSONObject records the origins of the values set by a --set-json
+// JSON object. The origin of each value is the key=value pair of its top-level
+// key.
+func recordJSONObject(origins chartutil.ValueOrigins, base, obj map[string]interface{}) {
+	keys := slices.Sorted(maps.Keys(obj))
+	pairs := make([]string, len(keys))
+	layers := make([]map[string]interface{}, len(keys))
+	for i, k := range keys {
+		data, err := json.Marshal(obj[k])
+		if err != nil {
+			return
+		}
+		pairs[i] = k + "=" + string(data)
+		layers[i] = map[string]interface{}{k: obj[k]}
+	}
+	recordPairs(origins, base, "--set-json", pairs, layers)
+}
+
+// splitPairs splits a flag value into its comma-separated key=value pairs.
+// Commas that are escaped, or within braces or brackets, do not separate
+// pairs, nor, for JSON values, do commas within strings.
+func splitPairs(value string, jsonValue bool) []string {
+	var (
+		pairs   []string
+		start   int
+		depth   int
+		escaped bool
+		quoted  bool
+	)
+	for i, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case quoted:
+			quoted = r != '"'
+		case jsonValue && r == '"':
+			quoted = true
+		case r == '{' || r == '[':
+			depth++
+		case r == '}' || r == ']':
+			depth--
+		case r == ',' && depth <= 0:
+			pairs = append(pairs, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(pairs, value[start:])
+}
+
 // readFile load a file from stdin, the local directory, or a remote file with a url.
 func readFile(filePath string, p getter.Providers) ([]byte, error) {
 	if strings.TrimSpace(filePath) == "-" {
@@ -26,6 +26,7 @@ import (
 	"strings"
 	"testing"
 
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
 	"helm.sh/helm/v4/pkg/getter"
 )
 
@@ -387,3 +388,51 @@ func TestMergeValues(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeValuesWithOrigins(t *testing.T) {
+	tmpDir := t.TempDir()
+	valuesFile := filepath.Join(tmpDir, "values.yaml")
+	if err := os.WriteFile(valuesFile, []byte("a: 1\nb:\n  c: 2\n  d: 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := Options{
+		ValueFiles:   []string{valuesFile},
+		Values:       []string{"b.c=4,f=6"},
+		StringValues: []string{"e=5"},
+		JSONValues:   []string{`{"g":{"h":7},"i":[8,9]}`, `j={"k":"x,y"},l=10`},
+	}
+	vals, origins, err := opts.MergeValuesWithOrigins(getter.Providers{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectVals := map[string]interface{}{
+		"a": float64(1),
+		"b": map[string]interface{}{"c": int64(4), "d": float64(3)},
+		"e": "5",
+		"f": int64(6),
+		"g": map[string]interface{}{"h": float64(7)},
+		"i": []interface{}{float64(8), float64(9)},
+		"j": map[string]interface{}{"k": "x,y"},
+		"l": float64(10),
+	}
+	if !reflect.DeepEqual(vals, expectVals) {
+		t.Errorf("MergeValuesWithOrigins() = %v, want %v", vals, expectVals)
+	}
+
+	expectOrigins := chartutil.ValueOrigins{
+		"a":   {Type: chartutil.OriginFile, Source: valuesFile},
+		"b.c": {Type: chartutil.OriginSet, Source: "--set b.c=4"},
+		"b.d": {Type: chartutil.OriginFile, Source: valuesFile},
+		"e":   {Type: chartutil.OriginSet, Source: "--set-string e=5"},
+		"f":   {Type: chartutil.OriginSet, Source: "--set f=6"},
+		"g.h": {Type: chartutil.OriginSet, Source: `--set-json g={"h":7}`},
+		"i":   {Type: chartutil.OriginSet, Source: "--set-json i=[8,9]"},
+		"j.k": {Type: chartutil.OriginSet, Source: `--set-json j={"k":"x,y"}`},
+		"l":   {Type: chartutil.OriginSet, Source: "--set-json l=10"},
+	}
+	if !reflect.DeepEqual(origins, expectOrigins) {
+		t.Errorf("MergeValuesWithOrigins() origins = %v, want %v", origins, expectOrigins)
+	}
+}
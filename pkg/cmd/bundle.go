@@ -0,0 +1,152 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cmd/require"
+)
+
+const bundleHelp = `
+This command consists of multiple subcommands to move charts into disconnected
+environments.
+
+A bundle is a single OCI image layout tarball that holds a chart, its provenance
+file, every chart it depends on, and metadata listing the container images the
+chart references. Charts are installed from a bundle with the oci-layout://
+scheme:
+
+  $ helm install myrelease oci-layout://mychart-1.0.0.bundle.tar
+
+A chart in the bundle other than the one the bundle was created for is
+selected by its name and version:
+
+  $ helm pull oci-layout://mychart-1.0.0.bundle.tar#mysubchart:0.2.0
+`
+
+const bundleCreateDesc = `
+Create a bundle of a chart directory or a chart archive.
+
+The bundle holds the chart, with its provenance file if the chart is an archive
+that has one, and all the charts it depends on. The container images referenced
+by the chart rendered with its default values are listed in the metadata of the
+bundle. Dependencies must be present in the charts/ directory, see 'helm
+dependency build'.
+
+The bundle is written to the destination directory as NAME-VERSION.bundle.tar.
+`
+
+const bundlePushDesc = `
+Upload the charts of a bundle to a registry.
+
+Every chart is pushed to a repository named after the chart below the remote,
+e.g. oci://registry.example.com/charts/mychart:1.0.0. The charts keep the
+digests they have in the bundle.
+`
+
+func newBundleCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "create and push air-gapped chart bundles",
+		Long:  bundleHelp,
+	}
+	cmd.AddCommand(
+		newBundleCreateCmd(out),
+		newBundlePushCmd(cfg, out),
+	)
+	return cmd
+}
+
+func newBundleCreateCmd(out io.Writer) *cobra.Command {
+	client := action.NewBundleCreate()
+
+	cmd := &cobra.Command{
+		Use:   "create [CHART]",
+		Short: "create a bundle of a chart and its dependencies",
+		Long:  bundleCreateDesc,
+		Args:  require.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				// Allow file completion when completing the argument for the chart
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+			return noMoreArgsComp()
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			if _, err := os.Stat(args[0]); err != nil {
+				return err
+			}
+			p, metadata, err := client.Run(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Successfully created a bundle of %d charts and saved it to: %s\n", len(metadata.Charts), p)
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&client.Destination, "destination", "d", ".", "location to write the bundle")
+
+	return cmd
+}
+
+func newBundlePushCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
+	o := &registryClientOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "push [BUNDLE] [REMOTE]",
+		Short: "push the charts of a bundle to a registry",
+		Long:  bundlePushDesc,
+		Args:  require.ExactArgs(2),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				// Do file completion for the bundle to push
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+			if len(args) == 1 {
+				return []string{"oci://"}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+			}
+			return noMoreArgsComp()
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			registryClient, err := newManagedRegistryClient(args[1], o)
+			if err != nil {
+				return err
+			}
+			cfg.RegistryClient = registryClient
+
+			refs, err := action.NewBundlePush(cfg).Run(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Successfully pushed %d charts of %s to %s\n", len(refs), args[0], args[1])
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	addRegistryClientFlags(f, o)
+
+	return cmd
+}
@@ -0,0 +1,128 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+func TestBundleCreate(t *testing.T) {
+	dir := t.TempDir()
+	_, out, err := executeActionCommand(fmt.Sprintf("bundle create testdata/testcharts/subchart --destination %s", dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundlePath := filepath.Join(dir, "subchart-0.1.0.bundle.tar")
+	expect := fmt.Sprintf("Successfully created a bundle of 3 charts and saved it to: %s\n", bundlePath)
+	if out != expect {
+		t.Errorf("expected %q, got %q", expect, out)
+	}
+
+	b, err := registry.LoadBundle(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if charts := strings.Join(b.Metadata.Charts, ","); charts != "subchart:0.1.0,subcharta:0.1.0,subchartb:0.1.0" {
+		t.Errorf("unexpected charts in the bundle: %s", charts)
+	}
+	if images := strings.Join(b.Metadata.Images, ","); images != "alpine:latest" {
+		t.Errorf("unexpected images in the bundle: %s", images)
+	}
+
+	// A chart of the bundle is used with the oci-layout scheme.
+	_, out, err = executeActionCommand(fmt.Sprintf("show chart oci-layout://%s#subcharta:0.1.0 --repository-cache %s", bundlePath, t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "name: subcharta") {
+		t.Errorf("expected the chart of subcharta, got %q", out)
+	}
+}
+
+func TestBundleCreateWithProvenance(t *testing.T) {
+	dir := t.TempDir()
+	if _, _, err := executeActionCommand(fmt.Sprintf("bundle create testdata/testcharts/signtest-0.1.0.tgz --destination %s", dir)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The provenance file in the bundle verifies the chart.
+	pullDir := t.TempDir()
+	cmd := fmt.Sprintf("pull oci-layout://%s --verify --keyring testdata/helm-test-key.pub -d %s --repository-cache %s",
+		filepath.Join(dir, "signtest-0.1.0.bundle.tar"), pullDir, t.TempDir())
+	_, out, err := executeActionCommand(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Signed by: Helm Testing (This key should only be used for testing. DO NOT TRUST.) <helm-testing@helm.sh>") {
+		t.Errorf("expected the chart to be verified, got %q", out)
+	}
+	for _, name := range []string{"signtest-0.1.0.tgz", "signtest-0.1.0.tgz.prov"} {
+		if _, err := os.Stat(filepath.Join(pullDir, name)); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestBundleCreateWithVendoredProvenance(t *testing.T) {
+	chartDir := filepath.Join(t.TempDir(), "vendor")
+	if err := os.MkdirAll(filepath.Join(chartDir, "charts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	chartYAML := "apiVersion: v2\nname: vendor\nversion: 0.1.0\ndependencies:\n- name: signtest\n  version: 0.1.0\n"
+	if err := os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte(chartYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"signtest-0.1.0.tgz", "signtest-0.1.0.tgz.prov"} {
+		data, err := os.ReadFile(filepath.Join("testdata/testcharts", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(chartDir, "charts", name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := t.TempDir()
+	if _, _, err := executeActionCommand(fmt.Sprintf("bundle create %s --destination %s", chartDir, dir)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The vendored archive is bundled as it is, so its provenance file still
+	// verifies it.
+	cmd := fmt.Sprintf("pull oci-layout://%s#signtest:0.1.0 --verify --keyring testdata/helm-test-key.pub -d %s --repository-cache %s",
+		filepath.Join(dir, "vendor-0.1.0.bundle.tar"), t.TempDir(), t.TempDir())
+	_, out, err := executeActionCommand(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Signed by: Helm Testing") {
+		t.Errorf("expected the dependency to be verified, got %q", out)
+	}
+}
+
+func TestBundlePushRequiresOCI(t *testing.T) {
+	_, _, err := executeActionCommand("bundle push testdata/testcharts/signtest-0.1.0.tgz https://example.com/charts")
+	if err == nil || !strings.Contains(err.Error(), "is not an OCI reference") {
+		t.Errorf("expected an error about the remote, got %v", err)
+	}
+}
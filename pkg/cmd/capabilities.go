@@ -0,0 +1,89 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/pkg/action"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/cmd/require"
+)
+
+const capabilitiesHelp = `
+This command consists of multiple subcommands to work with the capabilities of
+a Kubernetes cluster, which templates see as '.Capabilities'.
+`
+
+const capabilitiesDumpDesc = `
+Write the capabilities of the current Kubernetes cluster to a file.
+
+The file holds the Kubernetes version and the full set of API versions served
+by the cluster, including the ones provided by CRDs. Pass it to
+'helm template --capabilities-file' or 'helm lint --capabilities-file' to render
+charts exactly as they would be rendered against the cluster, without access
+to it.
+
+If no file is given, the capabilities are written to stdout.
+
+    $ helm capabilities dump prod.yaml
+    $ helm template mychart --capabilities-file prod.yaml
+`
+
+func newCapabilitiesCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "capabilities",
+		Short: "work with the capabilities of a cluster",
+		Long:  capabilitiesHelp,
+		Args:  require.NoArgs,
+	}
+
+	cmd.AddCommand(newCapabilitiesDumpCmd(cfg, out))
+
+	return cmd
+}
+
+func newCapabilitiesDumpCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
+	client := action.NewCapabilitiesDump(cfg)
+
+	cmd := &cobra.Command{
+		Use:   "dump [FILE]",
+		Short: "write the capabilities of the cluster to a file",
+		Long:  capabilitiesDumpDesc,
+		Args:  require.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			caps, err := client.Run()
+			if err != nil {
+				return err
+			}
+			data, err := chartutil.MarshalCapabilities(caps)
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				_, err = out.Write(data)
+				return err
+			}
+			return os.WriteFile(args[0], data, 0644)
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,57 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+)
+
+func TestCapabilitiesDump(t *testing.T) {
+	_, out, err := executeActionCommand("capabilities dump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	caps, err := chartutil.ParseCapabilities([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(caps, chartutil.DefaultCapabilities) {
+		t.Errorf("expected the default capabilities of the test fixture, got %+v", caps)
+	}
+
+	file := filepath.Join(t.TempDir(), "caps.yaml")
+	if _, out, err = executeActionCommand(fmt.Sprintf("capabilities dump %s", file)); err != nil {
+		t.Fatal(err)
+	}
+	if out != "" {
+		t.Errorf("expected no output when writing to a file, got %q", out)
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Error(err)
+	}
+
+	// The written file can be read back by the commands that render charts.
+	if _, _, err := executeActionCommand(fmt.Sprintf("template %s --capabilities-file %s", chartPath, file)); err != nil {
+		t.Error(err)
+	}
+}
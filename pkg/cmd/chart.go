@@ -0,0 +1,102 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cmd/require"
+)
+
+const chartHelp = `
+This command consists of multiple subcommands to manage charts in registries.
+`
+
+const chartCopyDesc = `
+Copy charts into a registry.
+
+The source is an oci:// reference, a chart reference of a chart repository
+(repo/chartname), or the URL of a chart archive. The charts are copied below
+the destination, to a repository named after the chart:
+
+  $ helm chart copy oci://dev.example.com/charts/mychart:1.2.3 oci://prod.example.com/charts
+
+copies the chart to oci://prod.example.com/charts/mychart:1.2.3.
+
+Charts copied between registries keep their manifests, configs, chart and
+provenance layers byte-identical, so their digests are preserved. Detached
+signatures of the charts are copied with them. Charts copied from a chart
+repository are pushed with their provenance files, if they have one.
+
+Without a tag in the source reference, the latest version of the chart is
+copied. The '--version' flag copies every version that matches a version or a
+constraint, e.g. '--version ">=1.0.0 <2.0.0"'.
+`
+
+func newChartCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "chart",
+		Short: "manage charts in registries",
+		Long:  chartHelp,
+	}
+	cmd.AddCommand(
+		newChartCopyCmd(cfg, out),
+	)
+	return cmd
+}
+
+func newChartCopyCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
+	client := action.NewChartCopy(cfg)
+	o := &registryClientOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "copy [SOURCE] [DESTINATION]",
+		Short: "copy charts into a registry",
+		Long:  chartCopyDesc,
+		Args:  require.ExactArgs(2),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) < 2 {
+				return []string{"oci://"}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+			}
+			return noMoreArgsComp()
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			registryClient, err := newManagedRegistryClient(args[1], o)
+			if err != nil {
+				return err
+			}
+			cfg.RegistryClient = registryClient
+			client.Settings = settings
+
+			copied, err := client.Run(args[0], args[1])
+			for _, c := range copied {
+				fmt.Fprintf(out, "Copied %s to %s\nDigest: %s\n", c.Source, c.Destination, c.Digest)
+			}
+			return err
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&client.Version, "version", "", "copy the versions that match a version or a version constraint. If this is not specified, the latest version is copied")
+	addRegistryClientFlags(f, o)
+
+	return cmd
+}
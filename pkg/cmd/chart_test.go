@@ -0,0 +1,116 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/registry"
+	"helm.sh/helm/v4/pkg/repo/repotest"
+)
+
+func TestChartCopy(t *testing.T) {
+	srv := repotest.NewTempServer(
+		t,
+		repotest.WithChartSourceGlob("testdata/testcharts/*.tgz*"),
+	)
+	defer srv.Stop()
+
+	ociSrv, err := repotest.NewOCIServer(t, srv.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ociSrv.Run(t)
+
+	flags := fmt.Sprintf("--repository-config %s --repository-cache %s --registry-config %s --plain-http",
+		filepath.Join(srv.Root(), "repositories.yaml"), srv.Root(), filepath.Join(srv.Root(), "config.json"))
+	if _, _, err := executeActionCommand(fmt.Sprintf("repo update --repository-config %s --repository-cache %s",
+		filepath.Join(srv.Root(), "repositories.yaml"), srv.Root())); err != nil {
+		t.Fatal(err)
+	}
+	prod := fmt.Sprintf("oci://%s/prod", ociSrv.RegistryURL)
+	stage := fmt.Sprintf("oci://%s/stage", ociSrv.RegistryURL)
+
+	// The versions of a chart repository that match a constraint are pushed.
+	_, out, err := executeActionCommand(fmt.Sprintf("chart copy test/compressedchart %s --version '>=0.2.0' %s", prod, flags))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range []string{"0.3.0", "0.2.0"} {
+		if !strings.Contains(out, fmt.Sprintf("Copied test/compressedchart:%s to %s/compressedchart:%s\n", version, prod, version)) {
+			t.Errorf("expected version %s to be copied, got %q", version, out)
+		}
+	}
+	if strings.Contains(out, "0.1.0") {
+		t.Errorf("expected version 0.1.0 not to be copied, got %q", out)
+	}
+
+	// Charts are pushed with their provenance files.
+	if _, _, err := executeActionCommand(fmt.Sprintf("chart copy test/signtest %s %s", prod, flags)); err != nil {
+		t.Fatal(err)
+	}
+	src := fmt.Sprintf("%s/signtest:0.1.0", strings.TrimPrefix(prod, "oci://"))
+	result, err := ociSrv.Client.Pull(src, registry.PullOptWithProv(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile("testdata/testcharts/signtest-0.1.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(result.Chart.Data, data) {
+		t.Error("expected the chart archive to be unchanged")
+	}
+
+	// Charts copied between registries keep their digests and provenance.
+	_, out, err = executeActionCommand(fmt.Sprintf("chart copy oci://%s %s %s", src, stage, flags))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := fmt.Sprintf("Copied oci://%s to %s/signtest:0.1.0\nDigest: %s\n", src, stage, result.Manifest.Digest)
+	if out != expect {
+		t.Errorf("expected %q, got %q", expect, out)
+	}
+	copied, err := ociSrv.Client.Pull(strings.TrimPrefix(stage, "oci://")+"/signtest:0.1.0", registry.PullOptWithProv(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied.Chart.Digest != result.Chart.Digest || copied.Prov.Digest != result.Prov.Digest || copied.Config.Digest != result.Config.Digest {
+		t.Error("expected the layers of the chart to be unchanged")
+	}
+
+	// Without a version, the latest version is copied.
+	_, out, err = executeActionCommand(fmt.Sprintf("chart copy %s/compressedchart %s %s", prod, stage, flags))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "compressedchart:0.3.0") || strings.Contains(out, "0.2.0") {
+		t.Errorf("expected only the latest version to be copied, got %q", out)
+	}
+
+	if _, _, err := executeActionCommand(fmt.Sprintf("chart copy %s/compressedchart %s --version '>=1.0.0' %s", prod, stage, flags)); err == nil {
+		t.Error("expected an error when no version matches")
+	}
+	if _, _, err := executeActionCommand(fmt.Sprintf("chart copy test/signtest https://example.com/charts %s", flags)); err == nil {
+		t.Error("expected an error for a destination that is not a registry")
+	}
+}
@@ -17,12 +17,17 @@ limitations under the License.
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 
+	"helm.sh/helm/v4/pkg/action"
 	chart "helm.sh/helm/v4/pkg/chart/v2"
 	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
 	"helm.sh/helm/v4/pkg/cmd/require"
@@ -48,16 +53,51 @@ something like this:
 do not exist, Helm will attempt to create them as it goes. If the given
 destination exists and there are files in that directory, conflicting files
 will be overwritten, but other files will be left alone.
+
+The '--starter' flag scaffolds the chart from a starter instead. A starter is
+the name of a directory in the starters data directory, an absolute path, or a
+chart that is downloaded like 'helm pull' does: an oci:// reference, a chart URL
+or a 'repo/chart' reference. Downloaded starters are cached, and the
+'--starter-version' and '--verify' flags select their version and verify their
+provenance.
+
+    $ helm create foo --starter oci://registry.example.com/starters/web --starter-version ^1.0.0
+
+A starter can declare parameters in a starter.yaml file. Every <NAME> in the
+templates and the values of the starter is replaced with the value of the
+parameter NAME, and bool parameters can select files that are only created
+when they are true. Parameters are set with '--set-param', and are prompted for
+when they are not set and the standard input is a terminal:
+
+    $ helm create foo --starter web --set-param SERVICE_PORT=8080 --set-param INGRESS=true
+
+The '--from-manifests' flag creates the chart from existing Kubernetes
+manifests, a file or a directory of YAML and JSON files, instead. Every object
+becomes a template of its own, named after its kind and name. The names,
+namespaces, labels, replicas, container images and resources of the objects
+are moved to values.yaml, and the objects are labeled by the labels helper in
+_helpers.tpl:
+
+    $ helm create foo --from-manifests ./manifests
 `
 
 type createOptions struct {
-	starter    string // --starter
-	name       string
-	starterDir string
+	starter        string   // --starter
+	starterVersion string   // --starter-version
+	verify         bool     // --verify
+	keyring        string   // --keyring
+	params         []string // --set-param
+	fromManifests  string   // --from-manifests
+	name           string
+	starterDir     string
+
+	// in is read from to prompt for parameters, if it is set.
+	in  io.Reader
+	cfg *action.Configuration
 }
 
-func newCreateCmd(out io.Writer) *cobra.Command {
-	o := &createOptions{}
+func newCreateCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
+	o := &createOptions{cfg: cfg}
 
 	cmd := &cobra.Command{
 		Use:   "create NAME",
@@ -76,11 +116,21 @@ func newCreateCmd(out io.Writer) *cobra.Command {
 		RunE: func(_ *cobra.Command, args []string) error {
 			o.name = args[0]
 			o.starterDir = helmpath.DataPath("starters")
+			if term.IsTerminal(int(os.Stdin.Fd())) {
+				o.in = os.Stdin
+			}
 			return o.run(out)
 		},
 	}
 
-	cmd.Flags().StringVarP(&o.starter, "starter", "p", "", "the name or absolute path to Helm starter scaffold")
+	f := cmd.Flags()
+	f.StringVarP(&o.starter, "starter", "p", "", "the name or absolute path to Helm starter scaffold, or an oci://, URL or repo/chart reference to a remote starter")
+	f.StringVar(&o.starterVersion, "starter-version", "", "specify a version constraint for a remote starter. If this is not specified, the latest version is used")
+	f.BoolVar(&o.verify, "verify", false, "verify the provenance of a remote starter")
+	f.StringVar(&o.keyring, "keyring", defaultKeyring(), "location of public keys used for verification")
+	f.StringArrayVar(&o.params, "set-param", []string{}, "set a parameter of the starter (can specify multiple): NAME=VALUE")
+	f.StringVar(&o.fromManifests, "from-manifests", "", "create the chart from the Kubernetes manifests in a file or a directory")
+	cmd.MarkFlagsMutuallyExclusive("starter", "from-manifests")
 	return cmd
 }
 
@@ -97,17 +147,89 @@ func (o *createOptions) run(out io.Writer) error {
 		APIVersion:  chart.APIVersionV2,
 	}
 
+	if o.fromManifests != "" {
+		chartutil.Stderr = out
+		_, err := action.NewCreateFromManifests().Run(chartname, filepath.Dir(o.name), o.fromManifests)
+		return err
+	}
+
 	if o.starter != "" {
 		// Create from the starter
-		lstarter := filepath.Join(o.starterDir, o.starter)
-		// If path is absolute, we don't want to prefix it with helm starters folder
-		if filepath.IsAbs(o.starter) {
-			lstarter = o.starter
+		client := action.NewStarter(o.cfg)
+		client.Version = o.starterVersion
+		client.Verify = o.verify
+		client.Keyring = o.keyring
+		client.StarterDir = o.starterDir
+		client.Settings = settings
+		client.Out = out
+		lstarter, err := client.Locate(o.starter)
+		if err != nil {
+			return err
+		}
+		params, err := o.starterParameters(lstarter, out)
+		if err != nil {
+			return err
 		}
-		return chartutil.CreateFrom(cfile, filepath.Dir(o.name), lstarter)
+		return chartutil.CreateFromWithParameters(cfile, filepath.Dir(o.name), lstarter, params)
 	}
 
 	chartutil.Stderr = out
 	_, err := chartutil.Create(chartname, filepath.Dir(o.name))
 	return err
 }
+
+// starterParameters returns the values of the parameters of a starter that
+// are set with flags, and prompts for the others if it can.
+func (o *createOptions) starterParameters(starter string, out io.Writer) (map[string]string, error) {
+	params := map[string]string{}
+	for _, p := range o.params {
+		name, value, ok := strings.Cut(p, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid parameter %q, must be NAME=VALUE", p)
+		}
+		params[name] = value
+	}
+	if o.in == nil {
+		return params, nil
+	}
+
+	declared, err := chartutil.StarterParameters(starter)
+	if err != nil {
+		return nil, err
+	}
+	in := bufio.NewReader(o.in)
+	for _, p := range declared {
+		if _, ok := params[p.Name]; ok {
+			continue
+		}
+		for {
+			prompt := p.Name
+			if p.Description != "" {
+				prompt = fmt.Sprintf("%s (%s)", p.Description, p.Name)
+			}
+			if p.Default != "" {
+				prompt = fmt.Sprintf("%s [%s]", prompt, p.Default)
+			}
+			fmt.Fprintf(out, "%s: ", prompt)
+			line, err := in.ReadString('\n')
+			if err != nil && line == "" {
+				return nil, fmt.Errorf("could not read parameter %s: %w", p.Name, err)
+			}
+			value := strings.TrimSpace(line)
+			if value == "" {
+				if p.Default == "" && p.Required {
+					fmt.Fprintf(out, "%s is required\n", p.Name)
+					continue
+				}
+				break
+			}
+			if _, err := p.Validate(value); err != nil {
+				fmt.Fprintln(out, err)
+				continue
+			}
+			params[p.Name] = value
+			break
+		}
+	}
+	return params, nil
+}
@@ -17,16 +17,21 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"helm.sh/helm/v4/internal/test/ensure"
+	"helm.sh/helm/v4/pkg/action"
 	chart "helm.sh/helm/v4/pkg/chart/v2"
 	"helm.sh/helm/v4/pkg/chart/v2/loader"
 	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
 	"helm.sh/helm/v4/pkg/helmpath"
+	"helm.sh/helm/v4/pkg/repo/repotest"
 )
 
 func TestCreateCmd(t *testing.T) {
@@ -186,6 +191,180 @@ func TestCreateStarterAbsoluteCmd(t *testing.T) {
 	}
 }
 
+// checkWebStarter checks a chart created from the web starter.
+func checkWebStarter(t *testing.T, cname, port string, ingress bool) {
+	t.Helper()
+	c, err := loader.LoadDir(cname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := os.ReadFile(filepath.Join(cname, "values.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := fmt.Sprintf("# Default values for %s.\nservice:\n  port: %s\n", cname, port); string(values) != expect {
+		t.Errorf("expected values %q, got %q", expect, values)
+	}
+	if _, err := os.Stat(filepath.Join(cname, "starter.yaml")); err == nil {
+		t.Error("expected starter.yaml not to be created")
+	}
+	var names []string
+	for _, tpl := range c.Templates {
+		names = append(names, tpl.Name)
+	}
+	if slices.Contains(names, "templates/ingress.yaml") != ingress {
+		t.Errorf("expected ingress %t, got templates %v", ingress, names)
+	}
+}
+
+func TestCreateStarterWithParameters(t *testing.T) {
+	starter, err := filepath.Abs("testdata/starters/web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(t.TempDir())
+	defer resetEnv()()
+	ensure.HelmHome(t)
+
+	if _, _, err := executeActionCommand(fmt.Sprintf("create --starter=%s defaults", starter)); err != nil {
+		t.Fatal(err)
+	}
+	checkWebStarter(t, "defaults", "80", false)
+
+	if _, _, err := executeActionCommand(fmt.Sprintf("create --starter=%s --set-param SERVICE_PORT=8080 --set-param INGRESS=yes params", starter)); err == nil {
+		t.Error("expected an error for an invalid bool parameter")
+	}
+	if _, _, err := executeActionCommand(fmt.Sprintf("create --starter=%s --set-param PORT=8080 params", starter)); err == nil {
+		t.Error("expected an error for an unknown parameter")
+	}
+	if _, _, err := executeActionCommand(fmt.Sprintf("create --starter=%s --set-param SERVICE_PORT=8080 --set-param INGRESS=true params", starter)); err != nil {
+		t.Fatal(err)
+	}
+	checkWebStarter(t, "params", "8080", true)
+}
+
+func TestCreateStarterPrompt(t *testing.T) {
+	starter, err := filepath.Abs("testdata/starters/web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(t.TempDir())
+	defer resetEnv()()
+	ensure.HelmHome(t)
+
+	var out bytes.Buffer
+	o := &createOptions{
+		starter: starter,
+		params:  []string{"SERVICE_PORT=8443"},
+		name:    "prompted",
+		in:      strings.NewReader("maybe\ntrue\n"),
+		cfg:     &action.Configuration{},
+	}
+	if err := o.run(&out); err != nil {
+		t.Fatal(err)
+	}
+	expect := "Creating prompted\n" +
+		"Whether to create an ingress (INGRESS): parameter INGRESS must be true or false, got \"maybe\"\n" +
+		"Whether to create an ingress (INGRESS): "
+	if out.String() != expect {
+		t.Errorf("expected output %q, got %q", expect, out.String())
+	}
+	checkWebStarter(t, "prompted", "8443", true)
+}
+
+func TestCreateRemoteStarter(t *testing.T) {
+	srv := repotest.NewTempServer(
+		t,
+		repotest.WithChartSourceGlob("testdata/starters/*.tgz*"),
+	)
+	defer srv.Stop()
+	keyring, err := filepath.Abs("testdata/helm-test-key.pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(t.TempDir())
+	defer resetEnv()()
+	ensure.HelmHome(t)
+
+	repoSetup := fmt.Sprintf("--repository-config %s --repository-cache %s",
+		filepath.Join(srv.Root(), "repositories.yaml"), srv.Root())
+	if _, _, err := executeActionCommand(fmt.Sprintf("repo update %s", repoSetup)); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := fmt.Sprintf("create --starter test/web --starter-version ^1.0.0 --verify --keyring %s --set-param INGRESS=true remote %s", keyring, repoSetup)
+	if _, _, err := executeActionCommand(cmd); err != nil {
+		t.Fatal(err)
+	}
+	checkWebStarter(t, "remote", "80", true)
+	if _, err := os.Stat(helmpath.CachePath("starters", "test-web", "web-1.0.0.tgz.prov")); err != nil {
+		t.Errorf("expected the starter to be cached: %s", err)
+	}
+
+	// The starter of an exact version is used from the cache.
+	srv.Stop()
+	if _, _, err := executeActionCommand(fmt.Sprintf("create --starter test/web --starter-version 1.0.0 cached %s", repoSetup)); err != nil {
+		t.Fatal(err)
+	}
+	checkWebStarter(t, "cached", "80", false)
+}
+
+func TestCreateFromManifests(t *testing.T) {
+	t.Chdir(t.TempDir())
+	defer resetEnv()()
+	ensure.HelmHome(t)
+
+	manifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: api
+  template:
+    metadata:
+      labels:
+        app: api
+    spec:
+      containers:
+      - name: api
+        image: example.com/api:2.0.1
+`
+	if err := os.MkdirAll("manifests", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("manifests", "api.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := executeActionCommand("create api --from-manifests manifests --starter web"); err == nil {
+		t.Error("expected an error for a starter and manifests")
+	}
+	if _, _, err := executeActionCommand("create api --from-manifests manifests"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"deployment.yaml", "service.yaml", "NOTES.txt"} {
+		if _, err := os.Stat(filepath.Join("api", "templates", name)); !os.IsNotExist(err) {
+			t.Errorf("expected the default template %s to be removed", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join("api", "templates", "deployment-api.yaml")); err != nil {
+		t.Error(err)
+	}
+
+	_, out, err := executeActionCommand("template api --set deployment.api.replicas=5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{"replicas: 5", `image: "example.com/api:2.0.1"`, "app.kubernetes.io/name: api"} {
+		if !strings.Contains(out, expect) {
+			t.Errorf("expected %q in the rendered chart, got %q", expect, out)
+		}
+	}
+}
+
 func TestCreateFileCompletion(t *testing.T) {
 	checkFileCompletion(t, "create", true)
 	checkFileCompletion(t, "create myname", false)
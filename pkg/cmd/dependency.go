@@ -72,6 +72,23 @@ the dependency charts stored locally. The path should start with a prefix of
 If the dependency chart is retrieved locally, it is not required to have the
 repository added to helm by "helm add repo". Version matching is also supported
 for this case.
+
+A dependency can also be fetched from a Git repository. The repository starts
+with "git+https://", "git+ssh://" or "git+file://", and may be followed by
+"//" and the path of the chart in the repository, and a "ref" query parameter
+naming a branch, tag or commit. For example,
+
+    # Chart.yaml
+    dependencies:
+    - name: library
+      version: "^1.2.0"
+      repository: "git+https://example.com/charts.git//charts/library"
+
+Without a ref, the highest tag that satisfies the version constraint is checked
+out. The commit that a dependency resolves to is recorded in Chart.lock.
+
+Chart.lock also records the digest of every downloaded dependency. 'helm
+dependency build' fails if a downloaded dependency does not match its digest.
 `
 
 const dependencyListDesc = `
@@ -83,9 +100,21 @@ the contents of a chart.
 This will produce an error if the chart cannot be loaded.
 `
 
+const dependencyVerifyDesc = `
+Verify the dependencies in the charts/ directory against Chart.lock.
+
+'helm dependency update' records the digest of every downloaded dependency in
+Chart.lock. This command checks that the archives in charts/ match those
+digests. It does not access the network.
+
+Dependencies that are not locked to a digest, such as those from local paths,
+fail the verification. To only check the dependencies that are locked, use the
+'--allow-unlocked' flag.
+`
+
 func newDependencyCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "dependency update|build|list",
+		Use:     "dependency update|build|list|verify",
 		Aliases: []string{"dep", "dependencies"},
 		Short:   "manage a chart's dependencies",
 		Long:    dependencyDesc,
@@ -95,6 +124,7 @@ func newDependencyCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 	cmd.AddCommand(newDependencyListCmd(out))
 	cmd.AddCommand(newDependencyUpdateCmd(cfg, out))
 	cmd.AddCommand(newDependencyBuildCmd(out))
+	cmd.AddCommand(newDependencyVerifyCmd(out))
 
 	return cmd
 }
@@ -122,6 +152,29 @@ func newDependencyListCmd(out io.Writer) *cobra.Command {
 	return cmd
 }
 
+func newDependencyVerifyCmd(out io.Writer) *cobra.Command {
+	client := action.NewDependency()
+	cmd := &cobra.Command{
+		Use:   "verify CHART",
+		Short: "verify the dependencies in charts/ against Chart.lock",
+		Long:  dependencyVerifyDesc,
+		Args:  require.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			chartpath := "."
+			if len(args) > 0 {
+				chartpath = filepath.Clean(args[0])
+			}
+			return client.VerifyLock(chartpath, out)
+		},
+	}
+
+	f := cmd.Flags()
+
+	f.UintVar(&client.ColumnWidth, "max-col-width", 80, "maximum column width for output table")
+	f.BoolVar(&client.AllowUnlocked, "allow-unlocked", false, "do not fail on dependencies that are not locked to a digest")
+	return cmd
+}
+
 func addDependencySubcommandFlags(f *pflag.FlagSet, client *action.Dependency) {
 	f.BoolVar(&client.Verify, "verify", false, "verify the packages against signatures")
 	f.StringVar(&client.Keyring, "keyring", defaultKeyring(), "keyring containing public keys")
@@ -133,4 +186,5 @@ func addDependencySubcommandFlags(f *pflag.FlagSet, client *action.Dependency) {
 	f.BoolVar(&client.InsecureSkipTLSverify, "insecure-skip-tls-verify", false, "skip tls certificate checks for the chart download")
 	f.BoolVar(&client.PlainHTTP, "plain-http", false, "use insecure HTTP connections for the chart download")
 	f.StringVar(&client.CaFile, "ca-file", "", "verify certificates of HTTPS-enabled servers using this CA bundle")
+	f.BoolVar(&client.Recursive, "recursive", false, "first process the dependencies of dependencies from local paths (\"file://\")")
 }
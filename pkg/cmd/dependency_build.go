@@ -39,6 +39,10 @@ does.
 
 If no lock file is found, 'helm dependency build' will mirror the behavior
 of 'helm dependency update'.
+
+With '--recursive', the dependencies of dependencies from local paths
+("file://") are built first, starting with the charts that have no local
+dependencies of their own.
 `
 
 func newDependencyBuildCmd(out io.Writer) *cobra.Command {
@@ -70,6 +74,7 @@ func newDependencyBuildCmd(out io.Writer) *cobra.Command {
 				RepositoryConfig: settings.RepositoryConfig,
 				RepositoryCache:  settings.RepositoryCache,
 				Debug:            settings.Debug,
+				Recursive:        client.Recursive,
 			}
 			if client.Verify {
 				man.Verify = downloader.VerifyIfPossible
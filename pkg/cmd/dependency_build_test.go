@@ -151,7 +151,15 @@ func TestDependencyBuildCmd(t *testing.T) {
 }
 
 func TestDependencyBuildCmdWithHelmV2Hash(t *testing.T) {
-	chartName := "testdata/testcharts/issue-7233"
+	// The chart and its local dependency are built in a copy, so that the
+	// dependency is not packaged into the testdata.
+	dir := t.TempDir()
+	for _, name := range []string{"issue-7233", "alpine"} {
+		if err := os.CopyFS(filepath.Join(dir, name), os.DirFS(filepath.Join("testdata/testcharts", name))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chartName := filepath.Join(dir, "issue-7233")
 
 	cmd := fmt.Sprintf("dependency build '%s'", chartName)
 	_, out, err := executeActionCommand(cmd)
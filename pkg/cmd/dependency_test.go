@@ -52,6 +52,30 @@ func TestDependencyListCmd(t *testing.T) {
 	runTestCmd(t, tests)
 }
 
+func TestDependencyVerifyCmd(t *testing.T) {
+	tests := []cmdTestCase{{
+		name:   "dependencies matching the lock file",
+		cmd:    "dependency verify testdata/testcharts/chart-with-locked-deps --allow-unlocked",
+		golden: "output/dependency-verify.txt",
+	}, {
+		name:      "dependencies not locked to a digest",
+		cmd:       "dependency verify testdata/testcharts/chart-with-locked-deps",
+		golden:    "output/dependency-verify-unlocked.txt",
+		wantError: true,
+	}, {
+		name:      "dependencies not matching the lock file",
+		cmd:       "dependency verify testdata/testcharts/chart-with-mismatched-deps",
+		golden:    "output/dependency-verify-mismatch.txt",
+		wantError: true,
+	}, {
+		name:      "chart without lock file",
+		cmd:       "dependency verify testdata/testcharts/alpine",
+		golden:    "output/dependency-verify-no-lock.txt",
+		wantError: true,
+	}}
+	runTestCmd(t, tests)
+}
+
 func TestDependencyFileCompletion(t *testing.T) {
 	checkFileCompletion(t, "dependency", false)
 }
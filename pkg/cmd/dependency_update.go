@@ -41,6 +41,10 @@ rebuild the dependencies to an exact version.
 Dependencies are not required to be represented in 'Chart.yaml'. For that
 reason, an update command will not remove charts unless they are (a) present
 in the Chart.yaml file, but (b) at the wrong version.
+
+With '--recursive', the dependencies of dependencies from local paths
+("file://") are updated first, starting with the charts that have no local
+dependencies of their own.
 `
 
 // newDependencyUpdateCmd creates a new dependency update command.
@@ -74,6 +78,7 @@ func newDependencyUpdateCmd(_ *action.Configuration, out io.Writer) *cobra.Comma
 				RepositoryConfig: settings.RepositoryConfig,
 				RepositoryCache:  settings.RepositoryCache,
 				Debug:            settings.Debug,
+				Recursive:        client.Recursive,
 			}
 			if client.Verify {
 				man.Verify = downloader.VerifyAlways
@@ -16,6 +16,7 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -26,6 +27,7 @@ import (
 
 	"helm.sh/helm/v4/internal/test/ensure"
 	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
 	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
 	"helm.sh/helm/v4/pkg/helmpath"
 	"helm.sh/helm/v4/pkg/provenance"
@@ -149,6 +151,119 @@ func TestDependencyUpdateCmd(t *testing.T) {
 	}
 }
 
+func TestDependencyUpdateCmd_OCIDigest(t *testing.T) {
+	srv := repotest.NewTempServer(
+		t,
+		repotest.WithChartSourceGlob("testdata/testcharts/*.tgz"),
+	)
+	defer srv.Stop()
+
+	ociSrv, err := repotest.NewOCIServer(t, srv.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ociSrv.Run(t)
+
+	dir := func(p ...string) string {
+		return filepath.Join(append([]string{srv.Root()}, p...)...)
+	}
+	lockOf := func(name string) *chart.Lock {
+		t.Helper()
+		ch, err := loader.LoadDir(dir(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ch.Lock
+	}
+	flags := fmt.Sprintf("--repository-config %s --repository-cache %s --registry-config %s --plain-http",
+		dir("repositories.yaml"), dir(), dir("config.json"))
+	ref := fmt.Sprintf("%s/u/ocitestuser/oci-dependent-chart", ociSrv.RegistryURL)
+	desc, err := ociSrv.Client.Resolve(ref + ":0.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pulled, err := ociSrv.Client.Pull(ref + ":0.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := pulled.Chart.Data
+
+	// Dependencies are locked to the digest of their manifest.
+	chartname := "pinned"
+	if err := chartutil.SaveDir(createTestingMetadataForOCI(chartname, ociSrv.RegistryURL), dir()); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := executeActionCommand(fmt.Sprintf("dependency update '%s' %s", dir(chartname), flags)); err != nil {
+		t.Fatal(err)
+	}
+	lock := lockOf(chartname)
+	if lock.Dependencies[0].ManifestDigest != desc.Digest.String() {
+		t.Errorf("expected the manifest digest %s, got %q", desc.Digest, lock.Dependencies[0].ManifestDigest)
+	}
+
+	// A tag that is pushed again does not change the chart that is built.
+	ch, err := loader.Load("testdata/testcharts/oci-dependent-chart-0.1.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch.Metadata.Description = "pushed again"
+	repushed, err := chartutil.Save(ch, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(repushed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ociSrv.Client.Push(data, ref+":0.1.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(dir(chartname, "charts")); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := executeActionCommand(fmt.Sprintf("dependency build '%s' %s", dir(chartname), flags)); err != nil {
+		t.Fatal(err)
+	}
+	built, err := os.ReadFile(dir(chartname, "charts/oci-dependent-chart-0.1.0.tgz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(built, original) {
+		t.Error("expected the chart the lock file is pinned to")
+	}
+
+	// Dependencies can be pinned to a digest in Chart.yaml.
+	chartname = "pinned-in-chart"
+	c := createTestingMetadataForOCI(chartname, ociSrv.RegistryURL)
+	c.Metadata.Dependencies[0].Repository = fmt.Sprintf("oci://%s@%s", ref, desc.Digest)
+	if err := chartutil.SaveDir(c, dir()); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := executeActionCommand(fmt.Sprintf("dependency update '%s' %s", dir(chartname), flags)); err != nil {
+		t.Fatal(err)
+	}
+	lock = lockOf(chartname)
+	if dep := lock.Dependencies[0]; dep.ManifestDigest != desc.Digest.String() || dep.Version != "0.1.0" {
+		t.Errorf("expected version 0.1.0 with the manifest digest %s, got %s with %q", desc.Digest, dep.Version, dep.ManifestDigest)
+	}
+	built, err = os.ReadFile(dir(chartname, "charts/oci-dependent-chart-0.1.0.tgz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(built, original) {
+		t.Error("expected the chart Chart.yaml is pinned to")
+	}
+
+	// Charts are located by digest on the command line.
+	_, out, err := executeActionCommand(fmt.Sprintf("show chart oci://%s@%s %s", ref, desc.Digest, flags))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "pushed again") {
+		t.Errorf("expected the chart of the digest, got %q", out)
+	}
+}
+
 func TestDependencyUpdateCmd_DoNotDeleteOldChartsOnError(t *testing.T) {
 	defer resetEnv()()
 	ensure.HelmHome(t)
@@ -30,8 +30,11 @@ import (
 	"k8s.io/klog/v2"
 
 	"helm.sh/helm/v4/pkg/action"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
 	"helm.sh/helm/v4/pkg/cli/output"
 	"helm.sh/helm/v4/pkg/cli/values"
+	"helm.sh/helm/v4/pkg/engine"
+	"helm.sh/helm/v4/pkg/getter"
 	"helm.sh/helm/v4/pkg/helmpath"
 	"helm.sh/helm/v4/pkg/kube"
 	"helm.sh/helm/v4/pkg/postrender"
@@ -39,11 +42,20 @@ import (
 )
 
 const (
-	outputFlag         = "output"
-	postRenderFlag     = "post-renderer"
-	postRenderArgsFlag = "post-renderer-args"
+	outputFlag           = "output"
+	postRenderFlag       = "post-renderer"
+	postRenderArgsFlag   = "post-renderer-args"
+	lookupFixturesFlag   = "lookup-fixtures"
+	capabilitiesFileFlag = "capabilities-file"
 )
 
+// valuesGetters returns the getters for the values files given as URLs. Files
+// fetched over HTTP are cached in the repository cache, and only downloaded
+// again when they have been modified.
+func valuesGetters() getter.Providers {
+	return getter.All(settings, getter.WithCacheDir(filepath.Join(settings.RepositoryCache, helmpath.CacheHTTPDir())))
+}
+
 func addValueOptionsFlags(f *pflag.FlagSet, v *values.Options) {
 	f.StringSliceVarP(&v.ValueFiles, "values", "f", []string{}, "specify values in a YAML file or a URL (can specify multiple)")
 	f.StringArrayVar(&v.Values, "set", []string{}, "set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
@@ -246,6 +258,61 @@ func (p *postRendererArgsSlice) GetSlice() []string {
 	return p.options.args
 }
 
+func bindLookupFixturesFlag(cmd *cobra.Command, varRef *engine.ClientProvider) {
+	cmd.Flags().Var(&lookupFixturesValue{provider: varRef}, lookupFixturesFlag, "YAML or JSON files of Kubernetes objects returned by the lookup function when not connected to a cluster (can specify multiple or separate values with commas)")
+}
+
+type lookupFixturesValue struct {
+	provider *engine.ClientProvider
+	paths    []string
+}
+
+func (l *lookupFixturesValue) String() string {
+	return "[" + strings.Join(l.paths, ",") + "]"
+}
+
+func (l *lookupFixturesValue) Type() string {
+	return "stringSlice"
+}
+
+func (l *lookupFixturesValue) Set(val string) error {
+	paths := append(l.paths, strings.Split(val, ",")...)
+	p, err := engine.LoadLookupFixtures(paths...)
+	if err != nil {
+		return err
+	}
+	l.paths = paths
+	*l.provider = p
+	return nil
+}
+
+func bindCapabilitiesFileFlag(cmd *cobra.Command, varRef **chartutil.Capabilities) {
+	cmd.Flags().Var(&capabilitiesFileValue{capabilities: varRef}, capabilitiesFileFlag, "a file written by 'helm capabilities dump' with the capabilities of the cluster to render for")
+}
+
+type capabilitiesFileValue struct {
+	capabilities **chartutil.Capabilities
+	path         string
+}
+
+func (c *capabilitiesFileValue) String() string {
+	return c.path
+}
+
+func (c *capabilitiesFileValue) Type() string {
+	return "string"
+}
+
+func (c *capabilitiesFileValue) Set(val string) error {
+	caps, err := chartutil.LoadCapabilities(val)
+	if err != nil {
+		return err
+	}
+	c.path = val
+	*c.capabilities = caps
+	return nil
+}
+
 func compVersionFlag(chartRef string, _ string) ([]string, cobra.ShellCompDirective) {
 	chartInfo := strings.Split(chartRef, "/")
 	if len(chartInfo) != 2 {
@@ -255,11 +322,17 @@ func compVersionFlag(chartRef string, _ string) ([]string, cobra.ShellCompDirect
 	repoName := chartInfo[0]
 	chartName := chartInfo[1]
 
-	path := filepath.Join(settings.RepositoryCache, helmpath.CacheIndexFile(repoName))
+	// Without a getter, only the cached versions of the charts in a sharded
+	// index are completed.
+	r := &repo.ChartRepository{
+		Config:    &repo.Entry{Name: repoName},
+		CachePath: settings.RepositoryCache,
+	}
 
 	var versions []string
-	if indexFile, err := repo.LoadIndexFile(path); err == nil {
-		for _, details := range indexFile.Entries[chartName] {
+	if index, err := r.LoadIndex(); err == nil {
+		cvs, _ := index.Versions(chartName)
+		for _, details := range cvs {
 			appVersion := details.AppVersion
 			appVersionDesc := ""
 			if appVersion != "" {
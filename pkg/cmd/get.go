@@ -50,6 +50,7 @@ func newGetCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 	cmd.AddCommand(newGetHooksCmd(cfg, out))
 	cmd.AddCommand(newGetNotesCmd(cfg, out))
 	cmd.AddCommand(newGetMetadataCmd(cfg, out))
+	cmd.AddCommand(newGetImagesCmd(cfg, out))
 
 	return cmd
 }
@@ -0,0 +1,100 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+	"log"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/cmd/require"
+)
+
+var getImagesHelp = `
+This command lists the container images referenced by a named release.
+
+Images are collected from the pod specs of all resources in the release's
+manifest and hooks, including init and ephemeral containers.
+`
+
+func newGetImagesCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
+	var outfmt output.Format
+	client := action.NewGet(cfg)
+
+	cmd := &cobra.Command{
+		Use:   "images RELEASE_NAME",
+		Short: "list the container images of a named release",
+		Long:  getImagesHelp,
+		Args:  require.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return noMoreArgsComp()
+			}
+			return compListReleases(toComplete, args, cfg)
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			res, err := client.Run(args[0])
+			if err != nil {
+				return err
+			}
+			images, err := action.ReleaseImages(res)
+			if err != nil {
+				return err
+			}
+			return outfmt.Write(out, imagesWriter(images))
+		},
+	}
+
+	cmd.Flags().IntVar(&client.Version, "revision", 0, "get the named release with revision")
+	err := cmd.RegisterFlagCompletionFunc("revision", func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 1 {
+			return compListRevisions(toComplete, cfg, args[0])
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	bindOutputFlag(cmd, &outfmt)
+
+	return cmd
+}
+
+type imagesWriter []action.Image
+
+func (w imagesWriter) WriteTable(out io.Writer) error {
+	tbl := uitable.New()
+	tbl.AddRow("IMAGE", "RESOURCE", "CONTAINER", "SOURCE")
+	for _, img := range w {
+		tbl.AddRow(img.Image, img.Kind+"/"+img.Name, img.Container, img.Source)
+	}
+	return output.EncodeTable(out, tbl)
+}
+
+func (w imagesWriter) WriteJSON(out io.Writer) error {
+	return output.EncodeJSON(out, w)
+}
+
+func (w imagesWriter) WriteYAML(out io.Writer) error {
+	return output.EncodeYAML(out, w)
+}
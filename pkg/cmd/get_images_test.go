@@ -0,0 +1,82 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	release "helm.sh/helm/v4/pkg/release/v1"
+)
+
+func TestGetImages(t *testing.T) {
+	rel := release.Mock(&release.MockReleaseOptions{Name: "thomas-guide"})
+	rel.Manifest = `---
+# Source: foo/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+        - name: web
+          image: registry.example.com/web:1.0.0
+`
+	rel.Hooks[0].Manifest = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  annotations:
+    "helm.sh/hook": pre-install
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: wait
+          image: docker.io/library/busybox:1.36
+      containers:
+        - name: migrate
+          image: registry.example.com/web-migrations:1.0.0
+`
+
+	tests := []cmdTestCase{{
+		name:   "get images with release",
+		cmd:    "get images thomas-guide",
+		golden: "output/get-images.txt",
+		rels:   []*release.Release{rel},
+	}, {
+		name:   "get images with release in yaml",
+		cmd:    "get images thomas-guide -o yaml",
+		golden: "output/get-images.yaml",
+		rels:   []*release.Release{rel},
+	}, {
+		name:      "get images without args",
+		cmd:       "get images",
+		golden:    "output/get-images-no-args.txt",
+		wantError: true,
+	}}
+	runTestCmd(t, tests)
+}
+
+func TestGetImagesCompletion(t *testing.T) {
+	checkReleaseCompletion(t, "get images", false)
+}
+
+func TestGetImagesRevisionCompletion(t *testing.T) {
+	revisionFlagCompletionTest(t, "get images")
+}
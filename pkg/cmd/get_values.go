@@ -20,16 +20,23 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"sort"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
 	"helm.sh/helm/v4/pkg/action"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
 	"helm.sh/helm/v4/pkg/cli/output"
 	"helm.sh/helm/v4/pkg/cmd/require"
 )
 
 var getValuesHelp = `
 This command downloads a values file for a given release.
+
+To show the source of each of the computed values, use the '--show-origin' flag.
+The files and flags that user-supplied values came from are only known for
+releases installed or upgraded with '--record-value-origins'.
 `
 
 type valuesWriter struct {
@@ -37,9 +44,15 @@ type valuesWriter struct {
 	allValues bool
 }
 
+type valuesOriginsWriter struct {
+	vals    map[string]interface{}
+	origins chartutil.ValueOrigins
+}
+
 func newGetValuesCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 	var outfmt output.Format
 	client := action.NewGetValues(cfg)
+	var showOrigin bool
 
 	cmd := &cobra.Command{
 		Use:   "values RELEASE_NAME",
@@ -53,6 +66,13 @@ func newGetValuesCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 			return compListReleases(toComplete, args, cfg)
 		},
 		RunE: func(_ *cobra.Command, args []string) error {
+			if showOrigin {
+				vals, origins, err := client.RunWithOrigins(args[0])
+				if err != nil {
+					return err
+				}
+				return outfmt.Write(out, &valuesOriginsWriter{vals, origins})
+			}
 			vals, err := client.Run(args[0])
 			if err != nil {
 				return err
@@ -75,6 +95,7 @@ func newGetValuesCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 	}
 
 	f.BoolVarP(&client.AllValues, "all", "a", false, "dump all (computed) values")
+	f.BoolVar(&showOrigin, "show-origin", false, "dump all (computed) values along with the source of each of them")
 	bindOutputFlag(cmd, &outfmt)
 
 	return cmd
@@ -96,3 +117,89 @@ func (v valuesWriter) WriteJSON(out io.Writer) error {
 func (v valuesWriter) WriteYAML(out io.Writer) error {
 	return output.EncodeYAML(out, v.vals)
 }
+
+func (v valuesOriginsWriter) WriteTable(out io.Writer) error {
+	fmt.Fprintln(out, "COMPUTED VALUES:")
+	var doc yaml.Node
+	if err := doc.Encode(v.vals); err != nil {
+		return fmt.Errorf("unable to write YAML output: %w", err)
+	}
+	annotateOrigins(&doc, "", v.origins)
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("unable to write YAML output: %w", err)
+	}
+	return enc.Close()
+}
+
+func (v valuesOriginsWriter) WriteJSON(out io.Writer) error {
+	return output.EncodeJSON(out, v.entries())
+}
+
+func (v valuesOriginsWriter) WriteYAML(out io.Writer) error {
+	return output.EncodeYAML(out, v.entries())
+}
+
+type valueOriginEntry struct {
+	Key    string                `json:"key"`
+	Value  interface{}           `json:"value"`
+	Origin chartutil.ValueOrigin `json:"origin"`
+}
+
+// entries flattens the values into a list of leaf values, sorted by key.
+func (v valuesOriginsWriter) entries() []valueOriginEntry {
+	var entries []valueOriginEntry
+	var walk func(vals map[string]interface{}, prefix string)
+	walk = func(vals map[string]interface{}, prefix string) {
+		for k, val := range vals {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			if m, ok := val.(map[string]interface{}); ok && len(m) > 0 {
+				walk(m, key)
+				continue
+			}
+			entries = append(entries, valueOriginEntry{Key: key, Value: val, Origin: v.origins[key]})
+		}
+	}
+	walk(v.vals, "")
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+// annotateOrigins adds the origin of every leaf value as a line comment.
+func annotateOrigins(node *yaml.Node, prefix string, origins chartutil.ValueOrigins) {
+	if node.Kind == yaml.DocumentNode {
+		for _, n := range node.Content {
+			annotateOrigins(n, prefix, origins)
+		}
+		return
+	}
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, val := node.Content[i], node.Content[i+1]
+		key := k.Value
+		if prefix != "" {
+			key = prefix + "." + k.Value
+		}
+		if val.Kind == yaml.MappingNode && len(val.Content) > 0 {
+			annotateOrigins(val, key, origins)
+			continue
+		}
+		o, ok := origins[key]
+		if !ok {
+			continue
+		}
+		// Comments on a block value would end up on its first item, so
+		// attach them to the key instead.
+		if val.Kind == yaml.ScalarNode || val.Style&yaml.FlowStyle != 0 {
+			val.LineComment = o.String()
+		} else {
+			k.LineComment = o.String()
+		}
+	}
+}
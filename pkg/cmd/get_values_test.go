@@ -49,6 +49,16 @@ func TestGetValuesCmd(t *testing.T) {
 		cmd:    "get values thomas-guide --output yaml",
 		golden: "output/values.yaml",
 		rels:   []*release.Release{release.Mock(&release.MockReleaseOptions{Name: "thomas-guide"})},
+	}, {
+		name:   "get values with origins",
+		cmd:    "get values thomas-guide --show-origin",
+		golden: "output/get-values-show-origin.txt",
+		rels:   []*release.Release{release.Mock(&release.MockReleaseOptions{Name: "thomas-guide"})},
+	}, {
+		name:   "get values with origins to json",
+		cmd:    "get values thomas-guide --show-origin --output json",
+		golden: "output/get-values-show-origin.json",
+		rels:   []*release.Release{release.Mock(&release.MockReleaseOptions{Name: "thomas-guide"})},
 	}}
 	runTestCmd(t, tests)
 }
@@ -39,7 +39,6 @@ import (
 	"helm.sh/helm/v4/pkg/cli/values"
 	"helm.sh/helm/v4/pkg/cmd/require"
 	"helm.sh/helm/v4/pkg/downloader"
-	"helm.sh/helm/v4/pkg/getter"
 	release "helm.sh/helm/v4/pkg/release/v1"
 )
 
@@ -145,6 +144,8 @@ func newInstallCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 			return compInstall(args, toComplete, client)
 		},
 		RunE: func(_ *cobra.Command, args []string) error {
+			cfg.Renderers = pluginRenderers()
+
 			registryClient, err := newRegistryClient(client.CertFile, client.KeyFile, client.CaFile,
 				client.InsecureSkipTLSverify, client.PlainHTTP, client.Username, client.Password)
 			if err != nil {
@@ -158,6 +159,9 @@ func newInstallCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 			if client.DryRunOption == "" {
 				client.DryRunOption = "none"
 			}
+			if err := validateLookupFixturesFlag(cfg, client.DryRunOption); err != nil {
+				return err
+			}
 			rel, err := runInstall(args, client, valueOpts, out)
 			if err != nil {
 				return fmt.Errorf("INSTALLATION FAILED: %w", err)
@@ -177,8 +181,10 @@ func newInstallCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 	// it is added separately
 	f := cmd.Flags()
 	f.BoolVar(&client.HideSecret, "hide-secret", false, "hide Kubernetes Secrets when also using the --dry-run flag")
+	f.BoolVar(&client.RecordValueOrigins, "record-value-origins", false, "store the origin of every value with the release, to be shown by 'helm get values --show-origin'")
 	bindOutputFlag(cmd, &outfmt)
 	bindPostRenderFlag(cmd, &client.PostRenderer)
+	bindLookupFixturesFlag(cmd, &cfg.LookupClientProvider)
 
 	return cmd
 }
@@ -250,8 +256,13 @@ func runInstall(args []string, client *action.Install, valueOpts *values.Options
 
 	slog.Debug("Chart path", "path", cp)
 
-	p := getter.All(settings)
-	vals, err := valueOpts.MergeValues(p)
+	p := valuesGetters()
+	var vals map[string]interface{}
+	if client.RecordValueOrigins {
+		vals, client.ValueOrigins, err = valueOpts.MergeValuesWithOrigins(p)
+	} else {
+		vals, err = valueOpts.MergeValues(p)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -357,3 +368,12 @@ func validateDryRunOptionFlag(dryRunOptionFlagValue string) error {
 	}
 	return nil
 }
+
+// validateLookupFixturesFlag checks that lookup fixtures are only given when
+// rendering without connecting to the cluster, the only case they are used in.
+func validateLookupFixturesFlag(cfg *action.Configuration, dryRunOption string) error {
+	if cfg.LookupClientProvider != nil && dryRunOption != "client" && dryRunOption != "true" {
+		return fmt.Errorf("--%s requires --dry-run=client", lookupFixturesFlag)
+	}
+	return nil
+}
@@ -274,6 +274,12 @@ func TestInstall(t *testing.T) {
 			wantError: true,
 			golden:    "output/install-hide-secret.txt",
 		},
+		{
+			name:      "lookup-fixtures error without client dry-run",
+			cmd:       "install lookup testdata/testcharts/chart-with-lookup --lookup-fixtures testdata/lookup-fixtures.yaml --dry-run=server",
+			wantError: true,
+			golden:    "output/install-lookup-fixtures-without-dry-run.txt",
+		},
 	}
 
 	runTestCmd(t, tests)
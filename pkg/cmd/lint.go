@@ -29,7 +29,6 @@ import (
 	"helm.sh/helm/v4/pkg/action"
 	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
 	"helm.sh/helm/v4/pkg/cli/values"
-	"helm.sh/helm/v4/pkg/getter"
 	"helm.sh/helm/v4/pkg/lint/support"
 )
 
@@ -52,6 +51,8 @@ func newLintCmd(out io.Writer) *cobra.Command {
 		Short: "examine a chart for possible issues",
 		Long:  longLintHelp,
 		RunE: func(_ *cobra.Command, args []string) error {
+			client.Renderers = pluginRenderers()
+
 			paths := []string{"."}
 			if len(args) > 0 {
 				paths = args
@@ -81,7 +82,7 @@ func newLintCmd(out io.Writer) *cobra.Command {
 			}
 
 			client.Namespace = settings.Namespace()
-			vals, err := valueOpts.MergeValues(getter.All(settings))
+			vals, err := valueOpts.MergeValues(valuesGetters())
 			if err != nil {
 				return err
 			}
@@ -151,6 +152,8 @@ func newLintCmd(out io.Writer) *cobra.Command {
 	f.BoolVar(&client.SkipSchemaValidation, "skip-schema-validation", false, "if set, disables JSON schema validation")
 	f.StringVar(&kubeVersion, "kube-version", "", "Kubernetes version used for capabilities and deprecation checks")
 	addValueOptionsFlags(f, valueOpts)
+	bindLookupFixturesFlag(cmd, &client.LookupClientProvider)
+	bindCapabilitiesFileFlag(cmd, &client.Capabilities)
 
 	return cmd
 }
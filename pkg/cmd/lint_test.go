@@ -95,3 +95,25 @@ func TestLintFileCompletion(t *testing.T) {
 	checkFileCompletion(t, "lint", true)
 	checkFileCompletion(t, "lint mypath", true) // Multiple paths can be given
 }
+
+func TestLintCmdWithLookupFixtures(t *testing.T) {
+	tests := []cmdTestCase{{
+		name:   "lint chart using lookup with --lookup-fixtures flag",
+		cmd:    "lint testdata/testcharts/chart-with-lookup --lookup-fixtures testdata/lookup-fixtures.yaml",
+		golden: "output/lint-with-lookup-fixtures.txt",
+	}, {
+		name:      "lint chart with invalid --lookup-fixtures flag",
+		cmd:       "lint testdata/testcharts/chart-with-lookup --lookup-fixtures testdata/does-not-exist.yaml",
+		wantError: true,
+	}}
+	runTestCmd(t, tests)
+}
+
+func TestLintCmdWithSchemaDrift(t *testing.T) {
+	tests := []cmdTestCase{{
+		name:   "lint chart with values not described in its schema",
+		cmd:    "lint testdata/testcharts/chart-with-schema-annotations",
+		golden: "output/lint-chart-with-schema-drift.txt",
+	}}
+	runTestCmd(t, tests)
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/yaml"
 
+	"helm.sh/helm/v4/pkg/engine"
 	"helm.sh/helm/v4/pkg/plugin"
 )
 
@@ -44,6 +45,19 @@ type PluginError struct {
 	Code int
 }
 
+// pluginRenderers returns the template renderers provided by plugins.
+func pluginRenderers() engine.RendererProviders {
+	// If HELM_NO_PLUGINS is set to 1, do not load plugins.
+	if os.Getenv("HELM_NO_PLUGINS") == "1" {
+		return nil
+	}
+	renderers, err := engine.CollectPluginRenderers(settings)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load plugin renderers: %s\n", err)
+	}
+	return renderers
+}
+
 // loadPlugins loads plugins into the command list.
 //
 // This follows a different pattern than the other commands because it has
@@ -28,7 +28,7 @@ import (
 	"helm.sh/helm/v4/pkg/action"
 	"helm.sh/helm/v4/pkg/cli/values"
 	"helm.sh/helm/v4/pkg/downloader"
-	"helm.sh/helm/v4/pkg/getter"
+	"helm.sh/helm/v4/pkg/sbom"
 )
 
 const packageDesc = `
@@ -45,11 +45,37 @@ provide '--keyring path/to/secret/keys' and '--key keyname'.
 
 If '--keyring' is not specified, Helm usually defaults to the public keyring
 unless your environment is otherwise configured.
+
+To produce an archive that is byte-for-byte identical every time the same
+chart is packaged, use the '--reproducible' flag. Files in the archive are
+timestamped with the SOURCE_DATE_EPOCH environment variable, or with the Unix
+epoch when it is not set.
+
+  $ SOURCE_DATE_EPOCH=$(git log -1 --format=%ct) helm package --reproducible ./mychart
+
+To generate a software bill of materials, use the '--sbom' flag. The SBOM lists
+the chart, its locked dependencies and the container images referenced by the
+templates rendered with the default values, or with the values given with the
+'--values' and '--set' flags. Values the templates require but are not given
+do not fail the generation. The SBOM is written in the SPDX or the CycloneDX
+JSON format next to the chart archive, e.g. mychart-0.1.0.spdx.json. To attach
+it to the chart when pushing the archive to an OCI registry, use the '--sbom'
+flag of 'helm push'.
+
+  $ helm package --sbom=cyclonedx -f prod-values.yaml ./mychart
+
+To see which files would be packaged without packaging the chart, use the
+'--list-files' flag. It prints the files of the chart that are not ignored by
+its .helmignore files, as they are named in the archive.
+
+  $ helm package --list-files ./mychart
 `
 
 func newPackageCmd(out io.Writer) *cobra.Command {
 	client := action.NewPackage()
 	valueOpts := &values.Options{}
+	var sbomFormat string
+	var listFiles bool
 
 	cmd := &cobra.Command{
 		Use:   "package [CHART_PATH] [...]",
@@ -67,9 +93,16 @@ func newPackageCmd(out io.Writer) *cobra.Command {
 					return errors.New("--keyring is required for signing a package")
 				}
 			}
+			if sbomFormat != "" {
+				format, err := sbom.ParseFormat(sbomFormat)
+				if err != nil {
+					return err
+				}
+				client.SBOM = format
+			}
 			client.RepositoryConfig = settings.RepositoryConfig
 			client.RepositoryCache = settings.RepositoryCache
-			p := getter.All(settings)
+			p := valuesGetters()
 			vals, err := valueOpts.MergeValues(p)
 			if err != nil {
 				return err
@@ -90,6 +123,17 @@ func newPackageCmd(out io.Writer) *cobra.Command {
 					return err
 				}
 
+				if listFiles {
+					files, err := client.Files(path)
+					if err != nil {
+						return err
+					}
+					for _, f := range files {
+						fmt.Fprintln(out, f)
+					}
+					continue
+				}
+
 				if client.DependencyUpdate {
 					downloadManager := &downloader.Manager{
 						Out:              io.Discard,
@@ -111,6 +155,9 @@ func newPackageCmd(out io.Writer) *cobra.Command {
 					return err
 				}
 				fmt.Fprintf(out, "Successfully packaged chart and saved it to: %s\n", p)
+				if client.SBOM != "" {
+					fmt.Fprintf(out, "Saved the SBOM to: %s\n", sbom.Filename(p, client.SBOM))
+				}
 			}
 			return nil
 		},
@@ -125,6 +172,11 @@ func newPackageCmd(out io.Writer) *cobra.Command {
 	f.StringVar(&client.AppVersion, "app-version", "", "set the appVersion on the chart to this version")
 	f.StringVarP(&client.Destination, "destination", "d", ".", "location to write the chart.")
 	f.BoolVarP(&client.DependencyUpdate, "dependency-update", "u", false, `update dependencies from "Chart.yaml" to dir "charts/" before packaging`)
+	f.BoolVar(&client.Reproducible, "reproducible", false, "produce a byte-for-byte reproducible archive. Files are timestamped with SOURCE_DATE_EPOCH, or with the Unix epoch when it is not set")
+	f.StringVar(&sbomFormat, "sbom", "", `generate a software bill of materials next to the package, in the "spdx" or the "cyclonedx" format`)
+	f.Lookup("sbom").NoOptDefVal = string(sbom.SPDX)
+	addValueOptionsFlags(f, valueOpts)
+	f.BoolVar(&listFiles, "list-files", false, "list the files that would be packaged, without packaging the chart")
 	f.StringVar(&client.Username, "username", "", "chart repository username where to locate the requested chart")
 	f.StringVar(&client.Password, "password", "", "chart repository password where to locate the requested chart")
 	f.StringVar(&client.CertFile, "cert-file", "", "identify HTTPS client using this SSL certificate file")
@@ -16,6 +16,7 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -23,6 +24,7 @@ import (
 	"strings"
 	"testing"
 
+	"helm.sh/helm/v4/internal/test"
 	"helm.sh/helm/v4/internal/test/ensure"
 	chart "helm.sh/helm/v4/pkg/chart/v2"
 	"helm.sh/helm/v4/pkg/chart/v2/loader"
@@ -192,6 +194,118 @@ func TestSetAppVersion(t *testing.T) {
 	}
 }
 
+func TestPackageReproducible(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1588561321")
+	chartToPackage := "testdata/testcharts/alpine"
+
+	var archives [][]byte
+	for range 2 {
+		dir := t.TempDir()
+		cmd := fmt.Sprintf("package %s --destination=%s --reproducible", chartToPackage, dir)
+		if _, output, err := executeActionCommand(cmd); err != nil {
+			t.Logf("Output: %s", output)
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "alpine-0.1.0.tgz"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		archives = append(archives, data)
+	}
+	if !bytes.Equal(archives[0], archives[1]) {
+		t.Error("expected packaging the same chart twice to produce identical archives")
+	}
+}
+
+func TestPackageSBOM(t *testing.T) {
+	for _, tt := range []struct{ flag, filename string }{
+		{"--sbom", "alpine-0.1.0.spdx.json"},
+		{"--sbom=cyclonedx", "alpine-0.1.0.cdx.json"},
+	} {
+		t.Run(tt.flag, func(t *testing.T) {
+			dir := t.TempDir()
+			cmd := fmt.Sprintf("package testdata/testcharts/alpine --destination=%s %s", dir, tt.flag)
+			_, output, err := executeActionCommand(cmd)
+			if err != nil {
+				t.Logf("Output: %s", output)
+				t.Fatal(err)
+			}
+			if !strings.Contains(output, "Saved the SBOM to: "+filepath.Join(dir, tt.filename)) {
+				t.Errorf("expected the SBOM path in the output, got %q", output)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, tt.filename))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The image of the alpine chart is rendered from its appVersion.
+			if !strings.Contains(string(data), `"alpine:3.9"`) {
+				t.Errorf("expected the SBOM to list the alpine image, got %s", data)
+			}
+		})
+	}
+
+	if _, _, err := executeActionCommand("package testdata/testcharts/alpine --destination=" + t.TempDir() + " --sbom=xml"); err == nil {
+		t.Error("expected an error for an unknown SBOM format")
+	}
+}
+
+func TestPackageSBOMValues(t *testing.T) {
+	chartDir := filepath.Join(t.TempDir(), "required")
+	files := map[string]string{
+		"Chart.yaml": "apiVersion: v2\nname: required\nversion: 0.1.0\n",
+		"templates/pod.yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  name: {{ required "a name is required" .Values.name }}
+spec:
+  containers:
+  - name: app
+    image: {{ .Values.image | default "app:latest" }}
+`,
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(chartDir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(chartDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range []struct{ flags, image string }{
+		// Required values that are not given do not fail the generation.
+		{"", `"app:latest"`},
+		{"--set image=app:1.0", `"app:1.0"`},
+	} {
+		dir := t.TempDir()
+		cmd := fmt.Sprintf("package %s --destination=%s --sbom %s", chartDir, dir, tt.flags)
+		if _, output, err := executeActionCommand(cmd); err != nil {
+			t.Logf("Output: %s", output)
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "required-0.1.0.spdx.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), tt.image) {
+			t.Errorf("expected the SBOM to list the image %s, got %s", tt.image, data)
+		}
+	}
+}
+
+func TestPackageListFiles(t *testing.T) {
+	_, output, err := executeActionCommand("package --list-files testdata/testcharts/chart-with-helmignore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.AssertGoldenString(t, output, "output/package-list-files.txt")
+
+	// Nothing is packaged.
+	if _, err := os.Stat("chart-with-helmignore-0.1.0.tgz"); !os.IsNotExist(err) {
+		t.Error("expected the chart not to be packaged")
+	}
+}
+
 func TestPackageFileCompletion(t *testing.T) {
 	checkFileCompletion(t, "package", true)
 	checkFileCompletion(t, "package mypath", true) // Multiple paths can be given
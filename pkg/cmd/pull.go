@@ -41,6 +41,9 @@ directory for the chart and uncompress into that directory.
 If the --verify flag is specified, the requested chart MUST have a provenance
 file, and MUST pass the verification process. Failure in any part of this will
 result in an error, and the chart will not be saved locally.
+
+If the --with-attachments flag is specified, the files attached to a chart in
+an OCI registry are downloaded next to the chart.
 `
 
 func newPullCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
@@ -87,6 +90,7 @@ func newPullCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 	f.BoolVar(&client.Devel, "devel", false, "use development versions, too. Equivalent to version '>0.0.0-0'. If --version is set, this is ignored.")
 	f.BoolVar(&client.Untar, "untar", false, "if set to true, will untar the chart after downloading it")
 	f.BoolVar(&client.VerifyLater, "prov", false, "fetch the provenance file, but don't perform verification")
+	f.BoolVar(&client.WithAttachments, "with-attachments", false, "download the files attached to a chart in an OCI registry")
 	f.StringVar(&client.UntarDir, "untardir", ".", "if untar is specified, this flag specifies the name of the directory into which the chart is expanded")
 	f.StringVarP(&client.DestDir, "destination", "d", ".", "location to write the chart. If this and untardir are specified, untardir is appended to this")
 	addChartPathOptionsFlags(f, &client.ChartPathOptions)
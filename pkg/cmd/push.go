@@ -32,6 +32,21 @@ Upload a chart to a registry.
 
 If the chart has an associated provenance file,
 it will also be uploaded.
+
+Files such as example values, documentation or test reports can be attached to
+a chart pushed to an OCI registry with --attach, as 'file' or 'file:mediatype'.
+Each file is uploaded as an artifact that refers to the chart, and is listed by
+'helm show attachments':
+
+    $ helm push mychart-0.1.0.tgz oci://registry.example.com/charts \
+        --attach values-prod.yaml:application/yaml --attach report.xml
+
+The software bill of materials written next to the chart archive by
+'helm package --sbom' is attached with --sbom. It must describe the pushed
+archive:
+
+    $ helm package --sbom ./mychart
+    $ helm push mychart-0.1.0.tgz oci://registry.example.com/charts --sbom
 `
 
 type registryPushOptions struct {
@@ -42,6 +57,8 @@ type registryPushOptions struct {
 	plainHTTP             bool
 	password              string
 	username              string
+	attachments           []string
+	sbom                  bool
 }
 
 func newPushCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
@@ -84,6 +101,8 @@ func newPushCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 				action.WithTLSClientConfig(o.certFile, o.keyFile, o.caFile),
 				action.WithInsecureSkipTLSVerify(o.insecureSkipTLSverify),
 				action.WithPlainHTTP(o.plainHTTP),
+				action.WithAttachments(o.attachments),
+				action.WithSBOM(o.sbom),
 				action.WithPushOptWriter(out))
 			client.Settings = settings
 			output, err := client.Run(chartRef, remote)
@@ -103,6 +122,8 @@ func newPushCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 	f.BoolVar(&o.plainHTTP, "plain-http", false, "use insecure HTTP connections for the chart upload")
 	f.StringVar(&o.username, "username", "", "chart repository username where to locate the requested chart")
 	f.StringVar(&o.password, "password", "", "chart repository password where to locate the requested chart")
+	f.BoolVar(&o.sbom, "sbom", false, "attach the SBOM generated by 'helm package --sbom' next to the chart archive")
+	f.StringArrayVar(&o.attachments, "attach", []string{}, "attach a file to the chart, as 'file' or 'file:mediatype' (can specify multiple)")
 
 	return cmd
 }
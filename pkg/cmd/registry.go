@@ -16,11 +16,14 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/registry"
 )
 
 const registryHelp = `
@@ -30,12 +33,63 @@ This command consists of multiple subcommands to interact with registries.
 func newRegistryCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "registry",
-		Short: "login to or logout from a registry",
+		Short: "login to, logout from, or manage a registry",
 		Long:  registryHelp,
 	}
 	cmd.AddCommand(
 		newRegistryLoginCmd(cfg, out),
 		newRegistryLogoutCmd(cfg, out),
+		newRegistryTagCmd(out),
+		newRegistryDeleteCmd(out),
+		newRegistryInspectCmd(out),
 	)
 	return cmd
 }
+
+// registryClientOptions are the options of the registry client of the
+// commands that manage or search a registry.
+type registryClientOptions struct {
+	certFile              string
+	keyFile               string
+	caFile                string
+	insecureSkipTLSverify bool
+	plainHTTP             bool
+	username              string
+	password              string
+}
+
+// addRegistryClientFlags adds the flags that configure the registry client of
+// the commands that manage or search a registry.
+func addRegistryClientFlags(f *pflag.FlagSet, o *registryClientOptions) {
+	f.StringVar(&o.certFile, "cert-file", "", "identify registry client using this SSL certificate file")
+	f.StringVar(&o.keyFile, "key-file", "", "identify registry client using this SSL key file")
+	f.StringVar(&o.caFile, "ca-file", "", "verify certificates of HTTPS-enabled servers using this CA bundle")
+	f.BoolVar(&o.insecureSkipTLSverify, "insecure-skip-tls-verify", false, "skip tls certificate checks for the registry")
+	f.BoolVar(&o.plainHTTP, "plain-http", false, "use insecure HTTP connections for the registry")
+	f.StringVar(&o.username, "username", "", "registry username")
+	f.StringVar(&o.password, "password", "", "registry password or identity token")
+}
+
+// newManagedRegistryClient returns the registry client of a reference to an
+// OCI registry.
+func newManagedRegistryClient(ref string, o *registryClientOptions) (*registry.Client, error) {
+	if !registry.IsOCI(ref) {
+		return nil, fmt.Errorf("%s is not an OCI reference, it must start with %s://", ref, registry.OCIScheme)
+	}
+	registryClient, err := newRegistryClient(
+		o.certFile, o.keyFile, o.caFile, o.insecureSkipTLSverify, o.plainHTTP, o.username, o.password,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("missing registry client: %w", err)
+	}
+	return registryClient, nil
+}
+
+// ociRefComp completes the argument of the commands that take a reference to
+// an OCI registry.
+func ociRefComp(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return []string{registry.OCIScheme + "://"}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+	return noMoreArgsComp()
+}
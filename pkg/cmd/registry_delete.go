@@ -0,0 +1,64 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/pkg/cmd/require"
+)
+
+const registryDeleteDesc = `
+Delete a tag or a manifest from an OCI registry.
+
+A reference with a tag deletes the tag only, and leaves the manifest and its
+other tags in place:
+
+    $ helm registry delete oci://registry.example.com/charts/mychart:1.0.0
+
+A reference with a digest deletes the manifest, and with it all of its tags:
+
+    $ helm registry delete oci://registry.example.com/charts/mychart@sha256:...
+
+Registries may not allow deleting tags or manifests, or may restrict it to
+some users.
+`
+
+func newRegistryDeleteCmd(_ io.Writer) *cobra.Command {
+	o := &registryClientOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "delete [REF]",
+		Short:             "delete a tag or a manifest from a registry",
+		Long:              registryDeleteDesc,
+		Args:              require.ExactArgs(1),
+		ValidArgsFunction: ociRefComp,
+		RunE: func(_ *cobra.Command, args []string) error {
+			registryClient, err := newManagedRegistryClient(args[0], o)
+			if err != nil {
+				return err
+			}
+			return registryClient.Delete(args[0])
+		},
+	}
+
+	addRegistryClientFlags(cmd.Flags(), o)
+
+	return cmd
+}
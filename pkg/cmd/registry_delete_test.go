@@ -0,0 +1,55 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRegistryDeleteCmd(t *testing.T) {
+	ociSrv, result, ref, flags := pushRegistryChart(t)
+	repo := fmt.Sprintf("%s/charts/compressedchart", ociSrv.RegistryURL)
+	if _, err := ociSrv.Client.Tag(ref, "stable"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Deleting a tag leaves the manifest in place.
+	if _, _, err := executeActionCommand(fmt.Sprintf("registry delete oci://%s:stable %s", repo, flags)); err != nil {
+		t.Fatal(err)
+	}
+	tags, err := ociSrv.Client.Tags(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(tags, ","); got != "0.1.0" {
+		t.Errorf("expected the tags 0.1.0, got %s", got)
+	}
+
+	// Deleting the manifest deletes its tags.
+	if _, _, err := executeActionCommand(fmt.Sprintf("registry delete oci://%s@%s %s", repo, result.Manifest.Digest, flags)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ociSrv.Client.Resolve(strings.TrimPrefix(ref, "oci://")); err == nil {
+		t.Error("expected the deleted chart not to resolve")
+	}
+}
+
+func TestRegistryDeleteFileCompletion(t *testing.T) {
+	checkFileCompletion(t, "registry delete", false)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/cmd/require"
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+const registryInspectDesc = `
+Inspect a chart, or any other manifest, in an OCI registry.
+
+This command prints the digest of the manifest, its annotations, its config and
+layers, and the manifests that refer to it, such as its detached signatures.
+Nothing is downloaded besides the manifest:
+
+    $ helm registry inspect oci://registry.example.com/charts/mychart:1.0.0
+`
+
+func newRegistryInspectCmd(out io.Writer) *cobra.Command {
+	o := &registryClientOptions{}
+	var outfmt output.Format
+
+	cmd := &cobra.Command{
+		Use:               "inspect [REF]",
+		Short:             "inspect a chart in a registry",
+		Long:              registryInspectDesc,
+		Args:              require.ExactArgs(1),
+		ValidArgsFunction: ociRefComp,
+		RunE: func(_ *cobra.Command, args []string) error {
+			registryClient, err := newManagedRegistryClient(args[0], o)
+			if err != nil {
+				return err
+			}
+			result, err := registryClient.Inspect(args[0])
+			if err != nil {
+				return err
+			}
+			return outfmt.Write(out, &registryInspectWriter{result})
+		},
+	}
+
+	addRegistryClientFlags(cmd.Flags(), o)
+	bindOutputFlag(cmd, &outfmt)
+
+	return cmd
+}
+
+type registryInspectWriter struct {
+	result *registry.InspectResult
+}
+
+func (w *registryInspectWriter) WriteTable(out io.Writer) error {
+	r := w.result
+	fmt.Fprintf(out, "Reference: %s\n", r.Ref)
+	fmt.Fprintf(out, "Media type: %s\n", r.Manifest.MediaType)
+	fmt.Fprintf(out, "Artifact type: %s\n", r.ArtifactType)
+	fmt.Fprintf(out, "Size: %d\n", r.Manifest.Size)
+
+	keys := make([]string, 0, len(r.Annotations))
+	for k := range r.Annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Fprintln(out, "Annotations:")
+	for _, k := range keys {
+		fmt.Fprintf(out, "  %s: %s\n", k, r.Annotations[k])
+	}
+
+	fmt.Fprintln(out, "\nLayers:")
+	table := uitable.New()
+	table.AddRow("DIGEST", "MEDIA TYPE", "SIZE")
+	table.AddRow(r.Config.Digest, r.Config.MediaType, strconv.FormatInt(r.Config.Size, 10))
+	for _, l := range r.Layers {
+		table.AddRow(l.Digest, l.MediaType, strconv.FormatInt(l.Size, 10))
+	}
+	if err := output.EncodeTable(out, table); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\nReferrers:")
+	if len(r.Referrers) == 0 {
+		fmt.Fprintln(out, "none")
+		return nil
+	}
+	table = uitable.New()
+	table.AddRow("DIGEST", "ARTIFACT TYPE", "SIZE")
+	for _, d := range r.Referrers {
+		table.AddRow(d.Digest, d.ArtifactType, strconv.FormatInt(d.Size, 10))
+	}
+	return output.EncodeTable(out, table)
+}
+
+func (w *registryInspectWriter) WriteJSON(out io.Writer) error {
+	return output.EncodeJSON(out, w.result)
+}
+
+func (w *registryInspectWriter) WriteYAML(out io.Writer) error {
+	return output.EncodeYAML(out, w.result)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+func TestRegistryInspectCmd(t *testing.T) {
+	ociSrv, result, ref, flags := pushRegistryChart(t)
+
+	_, out, err := executeActionCommand(fmt.Sprintf("registry inspect %s %s", ref, flags))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{
+		fmt.Sprintf("Reference: %s/charts/compressedchart@%s", ociSrv.RegistryURL, result.Manifest.Digest),
+		"Artifact type: " + registry.ConfigMediaType,
+		"org.opencontainers.image.title: compressedchart",
+		"org.opencontainers.image.version: 0.1.0",
+		result.Chart.Digest + "\t" + registry.ChartLayerMediaType,
+	} {
+		if !strings.Contains(out, expect) {
+			t.Errorf("expected %q in %q", expect, out)
+		}
+	}
+
+	_, out, err = executeActionCommand(fmt.Sprintf("registry inspect %s --output json %s", ref, flags))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var inspected registry.InspectResult
+	if err := json.Unmarshal([]byte(out), &inspected); err != nil {
+		t.Fatal(err)
+	}
+	if inspected.Manifest.Digest.String() != result.Manifest.Digest {
+		t.Errorf("expected the manifest %s, got %s", result.Manifest.Digest, inspected.Manifest.Digest)
+	}
+	if len(inspected.Layers) != 1 || len(inspected.Referrers) != 0 {
+		t.Errorf("expected a layer and no referrers, got %v and %v", inspected.Layers, inspected.Referrers)
+	}
+}
+
+func TestRegistryInspectFileCompletion(t *testing.T) {
+	checkFileCompletion(t, "registry inspect", false)
+}
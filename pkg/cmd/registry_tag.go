@@ -0,0 +1,61 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/pkg/cmd/require"
+)
+
+const registryTagDesc = `
+Add tags to a chart in an OCI registry.
+
+The tags are added to the manifest of the chart in the same repository, and the
+chart is not uploaded again:
+
+    $ helm registry tag oci://registry.example.com/charts/mychart:1.0.0 stable latest
+
+As for pushed charts, plus (+) signs in the tags are replaced with underscores
+(_).
+`
+
+func newRegistryTagCmd(_ io.Writer) *cobra.Command {
+	o := &registryClientOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "tag [REF] [TAG...]",
+		Short:             "add tags to a chart in a registry",
+		Long:              registryTagDesc,
+		Args:              require.MinimumNArgs(2),
+		ValidArgsFunction: ociRefComp,
+		RunE: func(_ *cobra.Command, args []string) error {
+			registryClient, err := newManagedRegistryClient(args[0], o)
+			if err != nil {
+				return err
+			}
+			_, err = registryClient.Tag(args[0], args[1:]...)
+			return err
+		},
+	}
+
+	addRegistryClientFlags(cmd.Flags(), o)
+
+	return cmd
+}
@@ -0,0 +1,88 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/registry"
+	"helm.sh/helm/v4/pkg/repo/repotest"
+)
+
+// pushRegistryChart pushes a test chart to an OCI server, and returns the
+// reference of the chart and the flags of the commands that manage it.
+func pushRegistryChart(t *testing.T) (*repotest.OCIServer, *registry.PushResult, string, string) {
+	t.Helper()
+	srv := repotest.NewTempServer(
+		t,
+		repotest.WithChartSourceGlob("testdata/testcharts/*.tgz*"),
+	)
+	t.Cleanup(srv.Stop)
+
+	ociSrv, err := repotest.NewOCIServer(t, srv.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ociSrv.Run(t)
+
+	data, err := os.ReadFile("testdata/testcharts/compressedchart-0.1.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := fmt.Sprintf("oci://%s/charts/compressedchart:0.1.0", ociSrv.RegistryURL)
+	result, err := ociSrv.Client.Push(data, strings.TrimPrefix(ref, "oci://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	flags := fmt.Sprintf("--registry-config %s --plain-http", filepath.Join(srv.Root(), "config.json"))
+	return ociSrv, result, ref, flags
+}
+
+func TestRegistryTagCmd(t *testing.T) {
+	ociSrv, result, ref, flags := pushRegistryChart(t)
+
+	if _, _, err := executeActionCommand(fmt.Sprintf("registry tag %s stable 0.1.1+build %s", ref, flags)); err != nil {
+		t.Fatal(err)
+	}
+	repo := fmt.Sprintf("%s/charts/compressedchart", ociSrv.RegistryURL)
+	tags, err := ociSrv.Client.Tags(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(tags, ","); got != "0.1.1+build,0.1.0" {
+		t.Errorf("expected the tags 0.1.1+build,0.1.0, got %s", got)
+	}
+	desc, err := ociSrv.Client.Resolve(repo + ":stable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.Digest.String() != result.Manifest.Digest {
+		t.Errorf("expected the manifest %s, got %s", result.Manifest.Digest, desc.Digest)
+	}
+
+	if _, _, err := executeActionCommand(fmt.Sprintf("registry tag %s stable", strings.TrimPrefix(ref, "oci://"))); err == nil {
+		t.Error("expected an error with a reference that is not an OCI reference")
+	}
+}
+
+func TestRegistryTagFileCompletion(t *testing.T) {
+	checkFileCompletion(t, "registry tag", false)
+}
@@ -29,13 +29,18 @@ import (
 var repoHelm = `
 This command consists of multiple subcommands to interact with chart repositories.
 
-It can be used to add, remove, list, and index chart repositories.
+It can be used to add, remove, list, index, and serve chart repositories.
+
+The indexes of the repositories are cached in the repository cache
+($HELM_REPOSITORY_CACHE). Chart archives and values files downloaded over HTTP
+are cached in its 'http' directory, and only downloaded again when they have
+been modified. Cached files that have not been used for 30 days are removed.
 `
 
 func newRepoCmd(out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "repo add|remove|list|index|update [ARGS]",
-		Short: "add, list, remove, update, and index chart repositories",
+		Use:   "repo add|remove|list|index|update|serve [ARGS]",
+		Short: "add, list, remove, update, index, and serve chart repositories",
 		Long:  repoHelm,
 		Args:  require.NoArgs,
 	}
@@ -45,6 +50,7 @@ func newRepoCmd(out io.Writer) *cobra.Command {
 	cmd.AddCommand(newRepoRemoveCmd(out))
 	cmd.AddCommand(newRepoIndexCmd(out))
 	cmd.AddCommand(newRepoUpdateCmd(out))
+	cmd.AddCommand(newRepoServeCmd(out))
 
 	return cmd
 }
@@ -209,7 +209,7 @@ func (o *repoAddOptions) run(out io.Writer) error {
 	if o.repoCache != "" {
 		r.CachePath = o.repoCache
 	}
-	if _, err := r.DownloadIndexFile(); err != nil {
+	if err := r.DownloadIndex(); err != nil {
 		return fmt.Errorf("looks like %q is not a valid chart repository or cannot be reached: %w", o.url, err)
 	}
 
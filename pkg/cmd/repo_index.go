@@ -41,13 +41,21 @@ To merge the generated index with an existing index file, use the '--merge'
 flag. In this case, the charts found in the current directory will be merged
 into the index passed in with --merge, with local charts taking priority over
 existing charts.
+
+To also write a sharded index to the 'index.d' directory, use the '--sharded'
+flag. A sharded index lists the charts of the repository in 'index.d/index.yaml',
+and the versions of each chart in a file of their own, so that clients of large
+repositories only download the versions of the charts they need. Clients use
+the sharded index of a repository when it has one, and fall back to
+'index.yaml' otherwise.
 `
 
 type repoIndexOptions struct {
-	dir   string
-	url   string
-	merge string
-	json  bool
+	dir     string
+	url     string
+	merge   string
+	json    bool
+	sharded bool
 }
 
 func newRepoIndexCmd(out io.Writer) *cobra.Command {
@@ -76,6 +84,7 @@ func newRepoIndexCmd(out io.Writer) *cobra.Command {
 	f.StringVar(&o.url, "url", "", "url of chart repository")
 	f.StringVar(&o.merge, "merge", "", "merge the generated index into the given index")
 	f.BoolVar(&o.json, "json", false, "output in JSON format")
+	f.BoolVar(&o.sharded, "sharded", false, "also write a sharded index to the index.d directory")
 
 	return cmd
 }
@@ -86,10 +95,10 @@ func (i *repoIndexOptions) run(_ io.Writer) error {
 		return err
 	}
 
-	return index(path, i.url, i.merge, i.json)
+	return index(path, i.url, i.merge, i.json, i.sharded)
 }
 
-func index(dir, url, mergeTo string, json bool) error {
+func index(dir, url, mergeTo string, json, sharded bool) error {
 	out := filepath.Join(dir, "index.yaml")
 
 	i, err := repo.IndexDirectory(dir, url)
@@ -111,6 +120,11 @@ func index(dir, url, mergeTo string, json bool) error {
 		i.Merge(i2)
 	}
 	i.SortEntries()
+	if sharded {
+		if err := i.WriteShardedIndex(filepath.Join(dir, repo.ShardedIndexDir), 0o644); err != nil {
+			return err
+		}
+	}
 	return writeIndexFile(i, out, json)
 }
 
@@ -162,6 +162,32 @@ func TestRepoIndexCmd(t *testing.T) {
 	}
 }
 
+func TestRepoIndexCmdSharded(t *testing.T) {
+	dir := t.TempDir()
+	if err := linkOrCopy("testdata/testcharts/compressedchart-0.1.0.tgz", filepath.Join(dir, "compressedchart-0.1.0.tgz")); err != nil {
+		t.Fatal(err)
+	}
+	if err := linkOrCopy("testdata/testcharts/compressedchart-0.2.0.tgz", filepath.Join(dir, "compressedchart-0.2.0.tgz")); err != nil {
+		t.Fatal(err)
+	}
+
+	c := newRepoIndexCmd(bytes.NewBuffer(nil))
+	c.ParseFlags([]string{"--sharded"})
+	if err := c.RunE(c, []string{dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The index file is still written for clients without sharded indexes.
+	if _, err := repo.LoadIndexFile(filepath.Join(dir, "index.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"index.yaml", "charts/compressedchart.yaml"} {
+		if _, err := os.Stat(filepath.Join(dir, repo.ShardedIndexDir, f)); err != nil {
+			t.Errorf("expected %s in the sharded index: %s", f, err)
+		}
+	}
+}
+
 func linkOrCopy(source, target string) error {
 	if err := os.Link(source, target); err != nil {
 		return copyFile(source, target)
@@ -27,10 +27,19 @@ import (
 	"github.com/spf13/cobra"
 
 	"helm.sh/helm/v4/pkg/cmd/require"
+	"helm.sh/helm/v4/pkg/getter"
 	"helm.sh/helm/v4/pkg/helmpath"
 	"helm.sh/helm/v4/pkg/repo"
 )
 
+const repoRemoveDesc = `
+Remove one or more chart repositories.
+
+The cached index of the repositories is removed, along with the chart archives
+downloaded from them that are cached in the 'http' directory of the repository
+cache ($HELM_REPOSITORY_CACHE).
+`
+
 type repoRemoveOptions struct {
 	names     []string
 	repoFile  string
@@ -44,6 +53,7 @@ func newRepoRemoveCmd(out io.Writer) *cobra.Command {
 		Use:     "remove [REPO1 [REPO2 ...]]",
 		Aliases: []string{"rm"},
 		Short:   "remove one or more chart repositories",
+		Long:    repoRemoveDesc,
 		Args:    require.MinimumNArgs(1),
 		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return compListRepos(toComplete, args), cobra.ShellCompDirectiveNoFileComp
@@ -65,6 +75,7 @@ func (o *repoRemoveOptions) run(out io.Writer) error {
 	}
 
 	for _, name := range o.names {
+		entry := r.Get(name)
 		if !r.Remove(name) {
 			return fmt.Errorf("no repo named %q found", name)
 		}
@@ -75,6 +86,10 @@ func (o *repoRemoveOptions) run(out io.Writer) error {
 		if err := removeRepoCache(o.repoCache, name); err != nil {
 			return err
 		}
+		httpCache := filepath.Join(o.repoCache, helmpath.CacheHTTPDir())
+		if err := getter.RemoveCachedURLs(httpCache, entry.URL); err != nil {
+			return fmt.Errorf("can't remove the cached files of %s: %w", name, err)
+		}
 		fmt.Fprintf(out, "%q has been removed from your repositories\n", name)
 	}
 
@@ -87,7 +102,13 @@ func removeRepoCache(root, name string) error {
 		os.Remove(idx)
 	}
 
+	sharded := filepath.Join(root, helmpath.CacheShardedIndexDir(name))
+	if err := os.RemoveAll(sharded); err != nil {
+		return fmt.Errorf("can't remove sharded index %s: %w", sharded, err)
+	}
+
 	idx = filepath.Join(root, helmpath.CacheIndexFile(name))
+	os.Remove(getter.CacheHeadersFile(idx))
 	if _, err := os.Stat(idx); errors.Is(err, fs.ErrNotExist) {
 		return nil
 	} else if err != nil {
@@ -24,6 +24,7 @@ import (
 	"strings"
 	"testing"
 
+	"helm.sh/helm/v4/pkg/getter"
 	"helm.sh/helm/v4/pkg/helmpath"
 	"helm.sh/helm/v4/pkg/repo"
 	"helm.sh/helm/v4/pkg/repo/repotest"
@@ -64,6 +65,19 @@ func TestRepoRemove(t *testing.T) {
 
 	cacheIndexFile, cacheChartsFile := createCacheFiles(rootDir, testRepoName)
 
+	// A chart archive of the repository cached by the HTTP getter.
+	cachedChart := filepath.Join(rootDir, helmpath.CacheHTTPDir(), "cached-chart")
+	if err := os.MkdirAll(filepath.Dir(cachedChart), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cachedChart, []byte("chart"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	headers := fmt.Sprintf(`{"url": %q, "etag": "\"v1\""}`, ts.URL()+"/test-chart-0.1.0.tgz")
+	if err := os.WriteFile(getter.CacheHeadersFile(cachedChart), []byte(headers), 0644); err != nil {
+		t.Fatal(err)
+	}
+
 	// Reset the buffer before running repo remove
 	b.Reset()
 
@@ -75,6 +89,9 @@ func TestRepoRemove(t *testing.T) {
 	}
 
 	testCacheFiles(t, cacheIndexFile, cacheChartsFile, testRepoName)
+	if _, err := os.Stat(cachedChart); !os.IsNotExist(err) {
+		t.Errorf("expected the cached chart archive of %s to be removed, got %v", testRepoName, err)
+	}
 
 	f, err := repo.LoadFile(repoFile)
 	if err != nil {
@@ -0,0 +1,216 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/internal/reposerver"
+	"helm.sh/helm/v4/internal/tlsutil"
+	"helm.sh/helm/v4/pkg/cmd/require"
+)
+
+const repoServeDesc = `
+Serve a directory of packaged charts as a chart repository.
+
+The index of the repository is generated when the server starts, and
+regenerated when the charts in the directory change. Only the index, the chart
+archives and their provenance files are served; other files in the directory,
+such as dotfiles, and directory listings are not. The server is meant for local
+development and testing, e.g. of dependencies, and not for production use.
+
+	$ helm repo serve ./charts
+	$ helm repo add local http://127.0.0.1:8879
+
+To also upload and delete charts through an API compatible with the one of
+ChartMuseum, use the '--allow-upload' flag. The API can be protected with basic
+authentication with the '--username' flag, and a password read from the
+'--password-stdin' flag, the HELM_REPO_SERVE_PASSWORD environment variable or
+the '--password' flag. Authentication is required to allow uploads on an
+address other than a loopback address.
+
+	$ curl --data-binary "@mychart-0.1.0.tgz" http://127.0.0.1:8879/api/charts
+	$ echo "$PASSWORD" | helm repo serve ./charts --address :8879 --allow-upload --username helm --password-stdin
+
+To serve the repository over HTTPS, use the '--cert-file' and '--key-file'
+flags.
+`
+
+// repoServePasswordEnvVar is the environment variable of the password required
+// to upload and delete charts.
+const repoServePasswordEnvVar = "HELM_REPO_SERVE_PASSWORD"
+
+type repoServeOptions struct {
+	dir                  string
+	address              string
+	url                  string
+	allowUpload          bool
+	username             string
+	password             string
+	passwordFromStdinOpt bool
+	certFile             string
+	keyFile              string
+	sharded              bool
+}
+
+func newRepoServeCmd(out io.Writer) *cobra.Command {
+	o := &repoServeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "serve [DIR]",
+		Short: "serve a directory of packaged charts as a chart repository",
+		Long:  repoServeDesc,
+		Args:  require.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				// Allow file completion when completing the argument for the directory
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+			// No more completions, so disable file completion
+			return noMoreArgsComp()
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.dir = args[0]
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return o.run(ctx, out)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&o.address, "address", "127.0.0.1:8879", "address to listen on")
+	f.StringVar(&o.url, "url", "", "url of the chart repository in the index, instead of URLs relative to the repository")
+	f.BoolVar(&o.allowUpload, "allow-upload", false, "allow uploading and deleting charts through the API")
+	f.StringVar(&o.username, "username", "", "username required to upload and delete charts")
+	f.StringVar(&o.password, "password", "", "password required to upload and delete charts")
+	f.BoolVar(&o.passwordFromStdinOpt, "password-stdin", false, "read the password required to upload and delete charts from stdin")
+	f.StringVar(&o.certFile, "cert-file", "", "serve over HTTPS with this SSL certificate file")
+	f.StringVar(&o.keyFile, "key-file", "", "serve over HTTPS with this SSL key file")
+	f.BoolVar(&o.sharded, "sharded", false, "also serve a sharded index")
+
+	return cmd
+}
+
+func (o *repoServeOptions) run(ctx context.Context, out io.Writer) error {
+	if (o.certFile == "") != (o.keyFile == "") {
+		return errors.New("both --cert-file and --key-file are required to serve over HTTPS")
+	}
+	if o.passwordFromStdinOpt {
+		if o.password != "" {
+			return errors.New("--password and --password-stdin are mutually exclusive")
+		}
+		password, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		o.password = strings.TrimSuffix(strings.TrimSuffix(string(password), "\n"), "\r")
+	}
+	if (o.username != "" || o.password != "") && !o.allowUpload {
+		return errors.New("--username and --password require --allow-upload")
+	}
+	if o.password != "" && o.username == "" {
+		return errors.New("--password requires --username")
+	}
+	if o.username != "" && o.password == "" {
+		o.password = os.Getenv(repoServePasswordEnvVar)
+		if o.password == "" {
+			return fmt.Errorf("--username requires a password from --password-stdin, $%s or --password", repoServePasswordEnvVar)
+		}
+	}
+	if o.allowUpload && o.username == "" && !isLoopbackAddress(o.address) {
+		return fmt.Errorf("--allow-upload requires --username and a password to listen on %q, which is not a loopback address", o.address)
+	}
+
+	var options []reposerver.Option
+	if o.url != "" {
+		options = append(options, reposerver.WithURL(o.url))
+	}
+	if o.allowUpload {
+		options = append(options, reposerver.WithUploads(o.username, o.password))
+	}
+	if o.sharded {
+		options = append(options, reposerver.WithShardedIndex())
+	}
+	s, err := reposerver.New(o.dir, options...)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	scheme := "http"
+	if o.certFile != "" {
+		tlsConf, err := tlsutil.NewTLSConfig(tlsutil.WithCertKeyPairFiles(o.certFile, o.keyFile))
+		if err != nil {
+			return fmt.Errorf("can't create TLS config: %w", err)
+		}
+		srv.TLSConfig = tlsConf
+		scheme = "https"
+	}
+
+	ln, err := net.Listen("tcp", o.address)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Serving %s at %s://%s\n", o.dir, scheme, ln.Addr())
+
+	errc := make(chan error, 1)
+	go func() {
+		if srv.TLSConfig != nil {
+			errc <- srv.ServeTLS(ln, "", "")
+			return
+		}
+		errc <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// isLoopbackAddress returns whether a listen address only accepts connections
+// from the local host. An address without a host listens on every interface.
+func isLoopbackAddress(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
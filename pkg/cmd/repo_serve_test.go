@@ -0,0 +1,95 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRepoServeCmd(t *testing.T) {
+	dir := t.TempDir()
+	if err := linkOrCopy("testdata/testcharts/compressedchart-0.1.0.tgz", filepath.Join(dir, "compressedchart-0.1.0.tgz")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The server stops right away, since its context is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out := bytes.NewBuffer(nil)
+	o := &repoServeOptions{dir: dir, address: "127.0.0.1:0", sharded: true}
+	if err := o.run(ctx, out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "Serving "+dir+" at http://127.0.0.1:") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+	for _, f := range []string{"index.yaml", "index.d/index.yaml"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			t.Errorf("expected %s to be generated: %s", f, err)
+		}
+	}
+
+	for _, o := range []*repoServeOptions{
+		{dir: dir, certFile: "cert.pem"},
+		{dir: dir, username: "helm"},
+		{dir: dir, allowUpload: true, password: "secret"},
+		{dir: dir, allowUpload: true, username: "helm"},
+		{dir: dir, allowUpload: true, username: "helm", password: "secret", passwordFromStdinOpt: true},
+		{dir: dir, address: ":0", allowUpload: true},
+		{dir: dir, address: "0.0.0.0:0", allowUpload: true},
+		{dir: filepath.Join(dir, "missing"), address: "127.0.0.1:0"},
+	} {
+		if err := o.run(ctx, out); err == nil {
+			t.Errorf("expected an error serving with %+v", o)
+		}
+	}
+}
+
+func TestRepoServeUploadCredentials(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Uploads without credentials are only allowed on a loopback address.
+	for _, address := range []string{"127.0.0.1:0", "[::1]:0", "localhost:0"} {
+		o := &repoServeOptions{dir: dir, address: address, allowUpload: true}
+		if err := o.run(ctx, io.Discard); err != nil && !strings.Contains(err.Error(), "listen") {
+			t.Errorf("expected uploads to be allowed on %s: %s", address, err)
+		}
+	}
+
+	// The password is read from the environment.
+	t.Setenv(repoServePasswordEnvVar, "secret")
+	o := &repoServeOptions{dir: dir, address: ":0", allowUpload: true, username: "helm"}
+	if err := o.run(ctx, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if o.password != "secret" {
+		t.Errorf("expected the password of the environment, got %q", o.password)
+	}
+}
+
+func TestRepoServeFileCompletion(t *testing.T) {
+	checkFileCompletion(t, "repo serve", true)
+	checkFileCompletion(t, "repo serve mydir", false)
+}
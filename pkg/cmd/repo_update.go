@@ -123,7 +123,7 @@ func updateCharts(repos []*repo.ChartRepository, out io.Writer) error {
 		wg.Add(1)
 		go func(re *repo.ChartRepository) {
 			defer wg.Done()
-			if _, err := re.DownloadIndexFile(); err != nil {
+			if err := re.DownloadIndex(); err != nil {
 				writeMutex.Lock()
 				defer writeMutex.Unlock()
 				fmt.Fprintf(out, "...Unable to get an update from the %q chart repository (%s):\n\t%s\n", re.Config.Name, re.Config.URL, err)
@@ -67,6 +67,7 @@ Environment variables:
 | $HELM_NO_PLUGINS                   | disable plugins. Set HELM_NO_PLUGINS=1 to disable plugins.                                                 |
 | $HELM_PLUGINS                      | set the path to the plugins directory                                                                      |
 | $HELM_REGISTRY_CONFIG              | set the path to the registry config file.                                                                  |
+| $HELM_REGISTRY_MIRRORS_CONFIG      | set the path to the registry mirrors file.                                                                 |
 | $HELM_REPOSITORY_CACHE             | set the path to the repository cache directory                                                             |
 | $HELM_REPOSITORY_CONFIG            | set the path to the repositories file.                                                                     |
 | $KUBECONFIG                        | set an alternative Kubernetes configuration file (default "~/.kube/config")                                |
@@ -217,14 +218,18 @@ func newRootCmdWithConfig(actionConfig *action.Configuration, out io.Writer, arg
 	// Add subcommands
 	cmd.AddCommand(
 		// chart commands
-		newCreateCmd(out),
+		newBundleCmd(actionConfig, out),
+		newChartCmd(actionConfig, out),
+		newCreateCmd(actionConfig, out),
 		newDependencyCmd(actionConfig, out),
 		newPullCmd(actionConfig, out),
 		newShowCmd(actionConfig, out),
 		newLintCmd(out),
 		newPackageCmd(out),
 		newRepoCmd(out),
+		newSchemaCmd(out),
 		newSearchCmd(out),
+		newSignCmd(actionConfig, out),
 		newVerifyCmd(out),
 
 		// release commands
@@ -239,6 +244,7 @@ func newRootCmdWithConfig(actionConfig *action.Configuration, out io.Writer, arg
 		newUninstallCmd(actionConfig, out),
 		newUpgradeCmd(actionConfig, out),
 
+		newCapabilitiesCmd(actionConfig, out),
 		newCompletionCmd(out),
 		newEnvCmd(out),
 		newPluginCmd(out),
@@ -376,6 +382,7 @@ func newDefaultRegistryClient(plainHTTP bool, username, password string) (*regis
 		registry.ClientOptEnableCache(true),
 		registry.ClientOptWriter(os.Stderr),
 		registry.ClientOptCredentialsFile(settings.RegistryConfig),
+		registry.ClientOptMirrorsFile(settings.RegistryMirrorsConfig),
 		registry.ClientOptBasicAuth(username, password),
 	}
 	if plainHTTP {
@@ -409,6 +416,7 @@ func newRegistryClientWithTLS(
 		registry.ClientOptEnableCache(true),
 		registry.ClientOptWriter(os.Stderr),
 		registry.ClientOptCredentialsFile(settings.RegistryConfig),
+		registry.ClientOptMirrorsFile(settings.RegistryMirrorsConfig),
 		registry.ClientOptHTTPClient(&http.Client{
 			Transport: &http.Transport{
 				TLSClientConfig: tlsConf,
@@ -0,0 +1,106 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/cmd/require"
+)
+
+const schemaHelp = `
+This command consists of multiple subcommands to work with the values schema
+of a chart.
+`
+
+const schemaGenerateDesc = `
+Generate a JSON Schema for the values of a chart from its values.yaml.
+
+The type of every value is inferred from values.yaml, including the values of
+the chart's dependencies. A value can be marked as required by annotating its
+key with a comment:
+
+    # @schema required
+    image: nginx
+
+By default the schema is printed to stdout. Use '--write' to save it as
+values.schema.json in the chart directory.
+`
+
+func newSchemaCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "work with the values schema of a chart",
+		Long:  schemaHelp,
+		Args:  require.NoArgs,
+	}
+
+	cmd.AddCommand(newSchemaGenerateCmd(out))
+
+	return cmd
+}
+
+func newSchemaGenerateCmd(out io.Writer) *cobra.Command {
+	var write bool
+
+	cmd := &cobra.Command{
+		Use:   "generate [CHART]",
+		Short: "generate a values schema from values.yaml",
+		Long:  schemaGenerateDesc,
+		Args:  require.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			chartpath := "."
+			if len(args) > 0 {
+				chartpath = filepath.Clean(args[0])
+			}
+			c, err := loader.Load(chartpath)
+			if err != nil {
+				return err
+			}
+			schema, err := chartutil.GenerateValuesSchema(c)
+			if err != nil {
+				return err
+			}
+			schema = append(schema, '\n')
+
+			if !write {
+				_, err = out.Write(schema)
+				return err
+			}
+			if fi, err := os.Stat(chartpath); err != nil || !fi.IsDir() {
+				return fmt.Errorf("cannot write the schema of %s: not a chart directory", chartpath)
+			}
+			dest := filepath.Join(chartpath, "values.schema.json")
+			if err := os.WriteFile(dest, schema, 0644); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Wrote %s\n", dest)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&write, "write", false, "write the schema to values.schema.json in the chart directory")
+
+	return cmd
+}
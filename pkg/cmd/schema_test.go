@@ -0,0 +1,65 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"helm.sh/helm/v4/internal/test"
+)
+
+func TestSchemaGenerateCmd(t *testing.T) {
+	tests := []cmdTestCase{{
+		name:   "generate schema from annotated values",
+		cmd:    "schema generate testdata/testcharts/chart-with-schema-annotations",
+		golden: "output/schema-generate.txt",
+	}, {
+		name:   "generate schema for chart with dependencies",
+		cmd:    "schema generate testdata/testcharts/chart-with-schema-and-subchart",
+		golden: "output/schema-generate-subchart.txt",
+	}, {
+		name:      "generate schema for missing chart",
+		cmd:       "schema generate testdata/testcharts/does-not-exist",
+		wantError: true,
+	}}
+	runTestCmd(t, tests)
+}
+
+func TestSchemaGenerateCmdWrite(t *testing.T) {
+	dir := t.TempDir()
+	chartDir := filepath.Join(dir, "chart")
+	if err := os.CopyFS(chartDir, os.DirFS("testdata/testcharts/chart-with-schema-annotations")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, out, err := executeActionCommand(fmt.Sprintf("schema generate %s --write", chartDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(chartDir, "values.schema.json")
+	if out != fmt.Sprintf("Wrote %s\n", dest) {
+		t.Errorf("unexpected output %q", out)
+	}
+	data, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.AssertGoldenString(t, string(data), "output/schema-generate.txt")
+}
@@ -0,0 +1,253 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/cmd/require"
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+const searchOCIDesc = `
+Search lists the charts in an OCI registry.
+
+The repositories of the registry are listed with the catalog API of the
+registry, and only the repositories below the path of the reference are
+searched. Registries that do not support the catalog API can only be searched
+for a single chart:
+
+    $ helm search oci oci://registry.example.com/charts/mychart
+
+It will display the latest stable versions of the charts found, with the
+metadata of the charts. If you specify the --devel flag, the output will
+include pre-release versions. If you want to search using a version
+constraint, use --version.
+
+Examples:
+
+    # List the charts below a path of a registry
+    $ helm search oci oci://registry.example.com/charts
+
+    # List all the versions of the charts with a major version of 1
+    $ helm search oci oci://registry.example.com/charts --versions --version ^1.0.0
+`
+
+type searchOCIOptions struct {
+	versions       bool
+	devel          bool
+	version        string
+	maxColWidth    uint
+	outputFormat   output.Format
+	failOnNoResult bool
+}
+
+func newSearchOCICmd(out io.Writer) *cobra.Command {
+	o := &searchOCIOptions{}
+	r := &registryClientOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "oci [REGISTRY]",
+		Short: "search an OCI registry for charts",
+		Long:  searchOCIDesc,
+		Args:  require.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return []string{"oci://"}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+			}
+			return noMoreArgsComp()
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			registryClient, err := newManagedRegistryClient(args[0], r)
+			if err != nil {
+				return err
+			}
+			return o.run(out, registryClient, args[0])
+		},
+	}
+
+	f := cmd.Flags()
+	f.BoolVarP(&o.versions, "versions", "l", false, "show the long listing, with each version of each chart on its own line")
+	f.BoolVar(&o.devel, "devel", false, "use development versions (alpha, beta, and release candidate releases), too. Equivalent to version '>0.0.0-0'. If --version is set, this is ignored")
+	f.StringVar(&o.version, "version", "", "search using semantic versioning constraints")
+	f.UintVar(&o.maxColWidth, "max-col-width", 50, "maximum column width for output table")
+	f.BoolVar(&o.failOnNoResult, "fail-on-no-result", false, "search fails if no results are found")
+	addRegistryClientFlags(f, r)
+
+	bindOutputFlag(cmd, &o.outputFormat)
+
+	return cmd
+}
+
+func (o *searchOCIOptions) run(out io.Writer, client *registry.Client, ref string) error {
+	version := o.version
+	if version == "" {
+		version = ">0.0.0"
+		if o.devel {
+			version = ">0.0.0-0"
+		}
+	}
+	constraint, err := semver.NewConstraint(version)
+	if err != nil {
+		return fmt.Errorf("an invalid version/constraint format: %w", err)
+	}
+
+	host, prefix, _ := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(ref, registry.OCIScheme+"://"), "/"), "/")
+	repositories, err := client.Repositories(host, prefix)
+	if err != nil {
+		if prefix == "" {
+			return err
+		}
+		slog.Debug("searching a single repository", "repository", prefix, slog.Any("error", err))
+		repositories = []string{prefix}
+	}
+
+	var results []ociChartElement
+	for _, repository := range repositories {
+		name := host + "/" + repository
+		charts, err := o.searchRepository(client, name, constraint)
+		if err != nil {
+			if len(repositories) == 1 {
+				return err
+			}
+			slog.Warn("could not search the repository", "repository", name, slog.Any("error", err))
+			continue
+		}
+		results = append(results, charts...)
+	}
+
+	return o.outputFormat.Write(out, &ociSearchWriter{results, o.maxColWidth, o.failOnNoResult})
+}
+
+// searchRepository returns the versions of the chart in a repository of a
+// registry that match the constraint, newest first. Repositories that do not
+// hold charts have none.
+//
+// Only the newest version is returned unless all versions are requested, and
+// the metadata of a version is only fetched if it is returned.
+func (o *searchOCIOptions) searchRepository(client *registry.Client, name string, constraint *semver.Constraints) ([]ociChartElement, error) {
+	tags, err := client.Tags(name)
+	if err != nil {
+		return nil, err
+	}
+	var matching []string
+	for _, tag := range tags {
+		v, err := semver.NewVersion(tag)
+		if err != nil || !constraint.Check(v) {
+			continue
+		}
+		matching = append(matching, tag)
+		if !o.versions {
+			break
+		}
+	}
+
+	charts := make([]ociChartElement, 0, len(matching))
+	for _, tag := range matching {
+		meta, err := client.ChartMetadata(name + ":" + tag)
+		if errors.Is(err, registry.ErrNotChart) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		charts = append(charts, ociChartElement{
+			Name:        registry.OCIScheme + "://" + name,
+			Version:     meta.Version,
+			AppVersion:  meta.AppVersion,
+			Description: meta.Description,
+			Deprecated:  meta.Deprecated,
+		})
+	}
+	return charts, nil
+}
+
+type ociChartElement struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	AppVersion  string `json:"app_version"`
+	Description string `json:"description"`
+	Deprecated  bool   `json:"deprecated"`
+}
+
+type ociSearchWriter struct {
+	results        []ociChartElement
+	columnWidth    uint
+	failOnNoResult bool
+}
+
+func (r *ociSearchWriter) WriteTable(out io.Writer) error {
+	if len(r.results) == 0 {
+		// Fail if no results found and --fail-on-no-result is enabled
+		if r.failOnNoResult {
+			return fmt.Errorf("no results found")
+		}
+
+		_, err := out.Write([]byte("No results found\n"))
+		if err != nil {
+			return fmt.Errorf("unable to write results: %s", err)
+		}
+		return nil
+	}
+	table := uitable.New()
+	table.MaxColWidth = r.columnWidth
+	table.AddRow("NAME", "CHART VERSION", "APP VERSION", "DEPRECATED", "DESCRIPTION")
+	for _, c := range r.results {
+		table.AddRow(c.Name, c.Version, c.AppVersion, strconv.FormatBool(c.Deprecated), c.Description)
+	}
+	return output.EncodeTable(out, table)
+}
+
+func (r *ociSearchWriter) WriteJSON(out io.Writer) error {
+	return r.encodeByFormat(out, output.JSON)
+}
+
+func (r *ociSearchWriter) WriteYAML(out io.Writer) error {
+	return r.encodeByFormat(out, output.YAML)
+}
+
+func (r *ociSearchWriter) encodeByFormat(out io.Writer, format output.Format) error {
+	// Fail if no results found and --fail-on-no-result is enabled
+	if len(r.results) == 0 && r.failOnNoResult {
+		return fmt.Errorf("no results found")
+	}
+
+	// Initialize the array so no results returns an empty array instead of null
+	chartList := make([]ociChartElement, 0, len(r.results))
+	chartList = append(chartList, r.results...)
+
+	switch format {
+	case output.JSON:
+		return output.EncodeJSON(out, chartList)
+	case output.YAML:
+		return output.EncodeYAML(out, chartList)
+	}
+
+	// Because this is a non-exported function and only called internally by
+	// WriteJSON and WriteYAML, we shouldn't get invalid types
+	return nil
+}
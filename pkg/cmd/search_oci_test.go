@@ -0,0 +1,113 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/repo/repotest"
+)
+
+func TestSearchOCICmd(t *testing.T) {
+	srv := repotest.NewTempServer(
+		t,
+		repotest.WithChartSourceGlob("testdata/testcharts/*.tgz*"),
+	)
+	defer srv.Stop()
+
+	ociSrv, err := repotest.NewOCIServer(t, srv.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ociSrv.Run(t)
+
+	for _, chart := range []string{"compressedchart-0.1.0", "compressedchart-0.2.0", "compressedchart-0.3.0", "pre-release-chart-0.1.0-alpha"} {
+		data, err := os.ReadFile(filepath.Join("testdata/testcharts", chart+".tgz"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		i := strings.LastIndex(chart[:strings.Index(chart, ".")], "-")
+		ref := fmt.Sprintf("%s/charts/%s:%s", ociSrv.RegistryURL, chart[:i], chart[i+1:])
+		if _, err := ociSrv.Client.Push(data, ref); err != nil {
+			t.Fatal(err)
+		}
+	}
+	flags := fmt.Sprintf("--registry-config %s --plain-http", filepath.Join(srv.Root(), "config.json"))
+	charts := fmt.Sprintf("oci://%s/charts", ociSrv.RegistryURL)
+
+	search := func(t *testing.T, args string) string {
+		t.Helper()
+		_, out, err := executeActionCommand(fmt.Sprintf("search oci %s %s", args, flags))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	t.Run("latest versions", func(t *testing.T) {
+		out := search(t, charts)
+		if !strings.Contains(out, charts+"/compressedchart\t0.3.0") {
+			t.Errorf("expected the latest version of compressedchart, got %q", out)
+		}
+		if strings.Contains(out, "0.2.0") || strings.Contains(out, "pre-release-chart") || strings.Contains(out, "oci-dependent-chart") {
+			t.Errorf("unexpected charts in %q", out)
+		}
+	})
+
+	t.Run("all versions with a constraint", func(t *testing.T) {
+		out := search(t, charts+" --versions --version '>=0.2.0' --output json")
+		var results []ociChartElement
+		if err := json.Unmarshal([]byte(out), &results); err != nil {
+			t.Fatal(err)
+		}
+		var versions []string
+		for _, r := range results {
+			versions = append(versions, r.Version)
+		}
+		if got := strings.Join(versions, ","); got != "0.3.0,0.2.0" {
+			t.Errorf("expected versions 0.3.0,0.2.0, got %s", got)
+		}
+	})
+
+	t.Run("devel", func(t *testing.T) {
+		out := search(t, charts+" --devel")
+		if !strings.Contains(out, charts+"/pre-release-chart\t0.1.0-alpha") {
+			t.Errorf("expected the pre-release chart, got %q", out)
+		}
+	})
+
+	t.Run("whole registry", func(t *testing.T) {
+		out := search(t, "oci://"+ociSrv.RegistryURL+" --max-col-width 100")
+		for _, name := range []string{"compressedchart", "u/ocitestuser/oci-dependent-chart"} {
+			if !strings.Contains(out, name) {
+				t.Errorf("expected %s, got %q", name, out)
+			}
+		}
+	})
+
+	t.Run("no results", func(t *testing.T) {
+		_, _, err := executeActionCommand(fmt.Sprintf("search oci %s --version '>=1.0.0' --fail-on-no-result %s", charts, flags))
+		if err == nil {
+			t.Error("expected an error with no results")
+		}
+	})
+}
@@ -33,6 +33,7 @@ import (
 
 	"helm.sh/helm/v4/pkg/cli/output"
 	"helm.sh/helm/v4/pkg/cmd/search"
+	"helm.sh/helm/v4/pkg/getter"
 	"helm.sh/helm/v4/pkg/helmpath"
 	"helm.sh/helm/v4/pkg/repo"
 )
@@ -184,21 +185,41 @@ func (o *searchRepoOptions) buildIndex() (*search.Index, error) {
 		return nil, errors.New("no repositories configured")
 	}
 
+	all := o.versions || len(o.version) > 0
+	getters := getter.All(settings)
 	i := search.NewIndex()
 	for _, re := range rf.Repositories {
 		n := re.Name
-		f := filepath.Join(o.repoCacheDir, helmpath.CacheIndexFile(n))
-		ind, err := repo.LoadIndexFile(f)
+		ind, err := o.loadIndex(re, getters, all)
 		if err != nil {
 			slog.Warn("repo is corrupt or missing", "repo", n, slog.Any("error", err))
 			continue
 		}
 
-		i.AddRepo(n, ind, o.versions || len(o.version) > 0)
+		i.AddRepo(n, ind, all)
 	}
 	return i, nil
 }
 
+// loadIndex loads the cached index of a repository. Only the most recent
+// version of each chart is loaded unless all the versions are searched, so
+// that the versions of the charts in a sharded index are not downloaded.
+func (o *searchRepoOptions) loadIndex(re *repo.Entry, getters getter.Providers, all bool) (*repo.IndexFile, error) {
+	r, err := repo.NewChartRepository(re, getters)
+	if err != nil {
+		return nil, err
+	}
+	r.CachePath = o.repoCacheDir
+	index, err := r.LoadIndex()
+	if err != nil {
+		return nil, err
+	}
+	if !all {
+		return index.Latest(), nil
+	}
+	return index.IndexFile()
+}
+
 type repoChartElement struct {
 	Name        string `json:"name"`
 	Version     string `json:"version"`
@@ -22,10 +22,14 @@ import (
 	"log"
 	"log/slog"
 
+	"github.com/gosuri/uitable"
 	"github.com/spf13/cobra"
 
 	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/cli/values"
 	"helm.sh/helm/v4/pkg/cmd/require"
+	"helm.sh/helm/v4/pkg/registry"
 )
 
 const showDesc = `
@@ -52,6 +56,22 @@ This command inspects a chart (directory, file, or URL) and displays the content
 of the README file
 `
 
+const showImagesDesc = `
+This command renders a chart (directory, file, or URL) as 'helm template' does,
+and lists the container images referenced by the rendered manifests and hooks.
+
+Images are collected from the pod specs of all resources, including init and
+ephemeral containers. Values can be set as for 'helm template'.
+`
+
+const showAttachmentsDesc = `
+This command lists the files attached to a chart in an OCI registry, such as
+example values files, documentation or test reports, without downloading them.
+
+Files are attached to a chart with 'helm push --attach', and downloaded with
+'helm pull --with-attachments'.
+`
+
 const showCRDsDesc = `
 This command inspects a chart (directory, file, or URL) and displays the contents
 of the CustomResourceDefinition files
@@ -181,11 +201,59 @@ func newShowCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 		},
 	}
 
-	cmds := []*cobra.Command{all, readmeSubCmd, valuesSubCmd, chartSubCmd, crdsSubCmd}
+	var outfmt output.Format
+	valueOpts := &values.Options{}
+	imagesSubCmd := &cobra.Command{
+		Use:               "images [CHART]",
+		Short:             "show the container images of the chart",
+		Long:              showImagesDesc,
+		Args:              require.ExactArgs(1),
+		ValidArgsFunction: validArgsFunc,
+		RunE: func(_ *cobra.Command, args []string) error {
+			cfg.Renderers = pluginRenderers()
+			err := addRegistryClient(client)
+			if err != nil {
+				return err
+			}
+			images, err := runShowImages(args, client, valueOpts)
+			if err != nil {
+				return err
+			}
+			return outfmt.Write(out, imagesWriter(images))
+		},
+	}
+
+	var attachmentsOutfmt output.Format
+	attachmentsSubCmd := &cobra.Command{
+		Use:               "attachments [CHART]",
+		Short:             "show the files attached to a chart in a registry",
+		Long:              showAttachmentsDesc,
+		Args:              require.ExactArgs(1),
+		ValidArgsFunction: validArgsFunc,
+		RunE: func(_ *cobra.Command, args []string) error {
+			err := addRegistryClient(client)
+			if err != nil {
+				return err
+			}
+			if client.Version == "" && client.Devel {
+				client.Version = ">0.0.0-0"
+			}
+			attachments, err := client.Attachments(args[0])
+			if err != nil {
+				return err
+			}
+			return attachmentsOutfmt.Write(out, attachmentsWriter(attachments))
+		},
+	}
+
+	cmds := []*cobra.Command{all, readmeSubCmd, valuesSubCmd, chartSubCmd, crdsSubCmd, imagesSubCmd, attachmentsSubCmd}
 	for _, subCmd := range cmds {
 		addShowFlags(subCmd, client)
 		showCommand.AddCommand(subCmd)
 	}
+	addValueOptionsFlags(imagesSubCmd.Flags(), valueOpts)
+	bindOutputFlag(imagesSubCmd, &outfmt)
+	bindOutputFlag(attachmentsSubCmd, &attachmentsOutfmt)
 
 	return showCommand
 }
@@ -225,6 +293,52 @@ func runShow(args []string, client *action.Show) (string, error) {
 	return client.Run(cp)
 }
 
+func runShowImages(args []string, client *action.Show, valueOpts *values.Options) ([]action.Image, error) {
+	slog.Debug("original chart version", "version", client.Version)
+	if client.Version == "" && client.Devel {
+		slog.Debug("setting version to >0.0.0-0")
+		client.Version = ">0.0.0-0"
+	}
+
+	cp, err := client.LocateChart(args[0], settings)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := valueOpts.MergeValues(valuesGetters())
+	if err != nil {
+		return nil, err
+	}
+	return client.Images(cp, vals)
+}
+
+type attachmentsWriter []*registry.Attachment
+
+func (w attachmentsWriter) WriteTable(out io.Writer) error {
+	if len(w) == 0 {
+		_, err := fmt.Fprintln(out, "No attachments found")
+		return err
+	}
+	tbl := uitable.New()
+	tbl.AddRow("NAME", "MEDIA TYPE", "SIZE", "DIGEST")
+	for _, a := range w {
+		tbl.AddRow(a.Name, a.MediaType, a.Size, a.Digest)
+	}
+	return output.EncodeTable(out, tbl)
+}
+
+func (w attachmentsWriter) WriteJSON(out io.Writer) error {
+	return output.EncodeJSON(out, w.list())
+}
+
+func (w attachmentsWriter) WriteYAML(out io.Writer) error {
+	return output.EncodeYAML(out, w.list())
+}
+
+// list returns the attachments as a list that is never null.
+func (w attachmentsWriter) list() []*registry.Attachment {
+	return append([]*registry.Attachment{}, w...)
+}
+
 func addRegistryClient(client *action.Show) error {
 	registryClient, err := newRegistryClient(client.CertFile, client.KeyFile, client.CaFile,
 		client.InsecureSkipTLSverify, client.PlainHTTP, client.Username, client.Password)
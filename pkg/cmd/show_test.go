@@ -17,11 +17,14 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"helm.sh/helm/v4/pkg/registry"
 	"helm.sh/helm/v4/pkg/repo/repotest"
 )
 
@@ -153,3 +156,97 @@ func TestShowValuesFileCompletion(t *testing.T) {
 func TestShowCRDsFileCompletion(t *testing.T) {
 	checkFileCompletion(t, "show crds", true)
 }
+
+func TestShowImages(t *testing.T) {
+	tests := []cmdTestCase{{
+		name:   "show images",
+		cmd:    "show images testdata/testcharts/chart-with-images",
+		golden: "output/show-images.txt",
+	}, {
+		name:   "show images with values",
+		cmd:    "show images testdata/testcharts/chart-with-images --set image.tag=2.0.0 -o json",
+		golden: "output/show-images-with-values.json",
+	}}
+	runTestCmd(t, tests)
+}
+
+func TestShowAttachments(t *testing.T) {
+	srv := repotest.NewTempServer(
+		t,
+		repotest.WithChartSourceGlob("testdata/testcharts/*.tgz*"),
+	)
+	defer srv.Stop()
+
+	ociSrv, err := repotest.NewOCIServer(t, srv.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ociSrv.Run(t)
+
+	dir := t.TempDir()
+	valuesFile := filepath.Join(dir, "values-prod.yaml")
+	if err := os.WriteFile(valuesFile, []byte("replicaCount: 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reportFile := filepath.Join(dir, "report.xml")
+	if err := os.WriteFile(reportFile, []byte("<testsuites/>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	flags := fmt.Sprintf("--registry-config %s --plain-http", filepath.Join(srv.Root(), "config.json"))
+	charts := fmt.Sprintf("oci://%s/charts", ociSrv.RegistryURL)
+
+	_, _, err = executeActionCommand(fmt.Sprintf("push testdata/testcharts/compressedchart-0.1.0.tgz %s --attach %s:application/yaml --attach %s %s",
+		charts, valuesFile, reportFile, flags))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, out, err := executeActionCommand(fmt.Sprintf("show attachments %s/compressedchart --version 0.1.0 %s", charts, flags))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{"report.xml", registry.DefaultAttachmentMediaType, "values-prod.yaml", "application/yaml"} {
+		if !strings.Contains(out, expect) {
+			t.Errorf("expected %q in %q", expect, out)
+		}
+	}
+
+	_, out, err = executeActionCommand(fmt.Sprintf("show attachments %s/compressedchart:0.1.0 --output json %s", charts, flags))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attachments []registry.Attachment
+	if err := json.Unmarshal([]byte(out), &attachments); err != nil {
+		t.Fatal(err)
+	}
+	if len(attachments) != 2 || attachments[1].Name != "values-prod.yaml" {
+		t.Errorf("unexpected attachments %v", attachments)
+	}
+
+	dest := t.TempDir()
+	_, out, err = executeActionCommand(fmt.Sprintf("pull %s/compressedchart --version 0.1.0 --with-attachments -d %s %s", charts, dest, flags))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Attachment: "+filepath.Join(dest, "values-prod.yaml")) {
+		t.Errorf("expected the attachment to be downloaded, got %q", out)
+	}
+	data, err := os.ReadFile(filepath.Join(dest, "values-prod.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "replicaCount: 3\n" {
+		t.Errorf("unexpected attachment %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "compressedchart-0.1.0.tgz")); err != nil {
+		t.Error(err)
+	}
+
+	if _, _, err := executeActionCommand("show attachments testdata/testcharts/compressedchart-0.1.0.tgz"); err == nil {
+		t.Error("expected an error for a chart that is not in a registry")
+	}
+}
+
+func TestShowAttachmentsFileCompletion(t *testing.T) {
+	checkFileCompletion(t, "show attachments", true)
+}
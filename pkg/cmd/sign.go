@@ -0,0 +1,102 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cmd/require"
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+const signDesc = `
+Sign a chart archive or a chart in an OCI registry with a PGP private key.
+
+A chart archive is signed by a provenance file that is written next to it, as
+'helm package --sign' does:
+
+  $ helm sign mychart-0.1.0.tgz --key mykey --keyring ~/.gnupg/secring.gpg
+
+A chart in an OCI registry is signed by a detached signature that is attached
+to the chart through the OCI referrers API, or through the referrers tag schema
+when the registry does not support it. The chart is not pushed again, so a
+chart can be signed after it has been published:
+
+  $ helm sign oci://registry.example.com/charts/mychart:0.1.0 --key mykey
+
+Detached signatures are verified by 'helm verify' and by the '--verify' flag of
+the commands that download charts.
+`
+
+func newSignCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
+	client := action.NewSign(cfg)
+	o := &registryClientOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "sign [CHART]",
+		Short: "sign a chart archive or a chart in a registry",
+		Long:  signDesc,
+		Args:  require.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				// Allow file completion when completing the argument for the path
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+			// No more completions, so disable file completion
+			return noMoreArgsComp()
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			if client.Key == "" {
+				return errors.New("--key is required for signing a chart")
+			}
+			if client.Keyring == "" {
+				return errors.New("--keyring is required for signing a chart")
+			}
+			if registry.IsOCI(args[0]) {
+				registryClient, err := newManagedRegistryClient(args[0], o)
+				if err != nil {
+					return err
+				}
+				cfg.RegistryClient = registryClient
+			}
+
+			signature, err := client.Run(args[0])
+			if err != nil {
+				return err
+			}
+			if registry.IsOCI(args[0]) {
+				fmt.Fprintf(out, "Successfully signed %s with the signature %s\n", args[0], signature)
+			} else {
+				fmt.Fprintf(out, "Successfully signed %s and saved the provenance file to: %s\n", args[0], signature)
+			}
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&client.Key, "key", "", "name of the key to use when signing")
+	f.StringVar(&client.Keyring, "keyring", defaultKeyring(), "location of a public keyring")
+	f.StringVar(&client.PassphraseFile, "passphrase-file", "", `location of a file which contains the passphrase for the signing key. Use "-" in order to read from stdin.`)
+	addRegistryClientFlags(f, o)
+
+	return cmd
+}
@@ -0,0 +1,133 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/repo/repotest"
+)
+
+const helmTestSignedBy = "Signed by: Helm Testing (This key should only be used for testing. DO NOT TRUST.) <helm-testing@helm.sh>"
+
+func TestSignLocalChart(t *testing.T) {
+	data, err := os.ReadFile("testdata/testcharts/signtest-0.1.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chart := filepath.Join(t.TempDir(), "signtest-0.1.0.tgz")
+	if err := os.WriteFile(chart, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := executeActionCommand("sign " + chart + " --keyring testdata/helm-test-key.secret"); err == nil {
+		t.Error("expected an error without a key")
+	}
+	_, out, err := executeActionCommand("sign " + chart + " --key helm-test --keyring testdata/helm-test-key.secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := fmt.Sprintf("Successfully signed %s and saved the provenance file to: %s.prov\n", chart, chart)
+	if out != expect {
+		t.Errorf("expected %q, got %q", expect, out)
+	}
+
+	_, out, err = executeActionCommand("verify " + chart + " --keyring testdata/helm-test-key.pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, helmTestSignedBy) {
+		t.Errorf("expected the chart to be verified, got %q", out)
+	}
+}
+
+func TestSignOCIChart(t *testing.T) {
+	srv := repotest.NewTempServer(
+		t,
+		repotest.WithChartSourceGlob("testdata/testcharts/*.tgz*"),
+	)
+	defer srv.Stop()
+
+	ociSrv, err := repotest.NewOCIServer(t, srv.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ociSrv.Run(t)
+
+	// The chart is pushed without its provenance file.
+	data, err := os.ReadFile("testdata/testcharts/signtest-0.1.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := fmt.Sprintf("oci://%s/u/ocitestuser/signtest:0.1.0", ociSrv.RegistryURL)
+	result, err := ociSrv.Client.Push(data, strings.TrimPrefix(ref, "oci://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registryFlags := fmt.Sprintf("--registry-config %s --plain-http", filepath.Join(srv.Root(), "config.json"))
+
+	if _, _, err := executeActionCommand(fmt.Sprintf("verify %s --keyring testdata/helm-test-key.pub %s", ref, registryFlags)); err == nil {
+		t.Error("expected an error verifying an unsigned chart")
+	}
+
+	_, out, err := executeActionCommand(fmt.Sprintf("sign %s --key helm-test --keyring testdata/helm-test-key.secret %s", ref, registryFlags))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, fmt.Sprintf("Successfully signed %s with the signature sha256:", ref)) {
+		t.Errorf("unexpected output %q", out)
+	}
+
+	// The chart is not pushed again.
+	pulled, err := ociSrv.Client.Pull(strings.TrimPrefix(ref, "oci://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pulled.Manifest.Digest != result.Manifest.Digest {
+		t.Errorf("expected the manifest %s, got %s", result.Manifest.Digest, pulled.Manifest.Digest)
+	}
+
+	_, out, err = executeActionCommand(fmt.Sprintf("verify %s --keyring testdata/helm-test-key.pub %s", ref, registryFlags))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, helmTestSignedBy) {
+		t.Errorf("expected the chart to be verified, got %q", out)
+	}
+
+	// Downloads verify the chart with its detached signature.
+	dest := t.TempDir()
+	_, out, err = executeActionCommand(fmt.Sprintf("pull %s --verify --keyring testdata/helm-test-key.pub -d %s %s", ref, dest, registryFlags))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, helmTestSignedBy) {
+		t.Errorf("expected the pulled chart to be verified, got %q", out)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "signtest-0.1.0.tgz.prov")); err != nil {
+		t.Error(err)
+	}
+
+	// A signature by another key does not verify the chart.
+	if _, _, err := executeActionCommand(fmt.Sprintf("verify %s --keyring testdata/helm-test-key.secret.missing %s", ref, registryFlags)); err == nil {
+		t.Error("expected an error verifying with a missing keyring")
+	}
+}
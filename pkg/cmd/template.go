@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
@@ -58,6 +59,7 @@ func newTemplateCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 	var kubeVersion string
 	var extraAPIs []string
 	var showFiles []string
+	var showValuesOrigin bool
 
 	cmd := &cobra.Command{
 		Use:   "template [NAME] [CHART]",
@@ -76,6 +78,8 @@ func newTemplateCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 				client.KubeVersion = parsedKubeVersion
 			}
 
+			cfg.Renderers = pluginRenderers()
+
 			registryClient, err := newRegistryClient(client.CertFile, client.KeyFile, client.CaFile,
 				client.InsecureSkipTLSverify, client.PlainHTTP, client.Username, client.Password)
 			if err != nil {
@@ -95,6 +99,7 @@ func newTemplateCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 			client.ClientOnly = !validate
 			client.APIVersions = chartutil.VersionSet(extraAPIs)
 			client.IncludeCRDs = includeCrds
+			client.RecordValueOrigins = showValuesOrigin
 			rel, err := runInstall(args, client, valueOpts, out)
 
 			if err != nil && !settings.Debug {
@@ -104,6 +109,18 @@ func newTemplateCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 				return err
 			}
 
+			if showValuesOrigin && rel != nil {
+				origins := maps.Clone(client.ValueOrigins)
+				if origins == nil {
+					origins = make(chartutil.ValueOrigins)
+				}
+				vals, err := chartutil.CoalesceValuesWithOrigins(rel.Chart, rel.Config, origins)
+				if err != nil {
+					return err
+				}
+				return valuesOriginsWriter{vals, origins}.WriteTable(out)
+			}
+
 			// We ignore a potential error here because, when the --debug flag was specified,
 			// we always want to print the YAML, even if it is not valid. The error is still returned afterwards.
 			if rel != nil {
@@ -203,7 +220,10 @@ func newTemplateCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 	f.StringVar(&kubeVersion, "kube-version", "", "Kubernetes version used for Capabilities.KubeVersion")
 	f.StringSliceVarP(&extraAPIs, "api-versions", "a", []string{}, "Kubernetes api versions used for Capabilities.APIVersions (multiple can be specified)")
 	f.BoolVar(&client.UseReleaseName, "release-name", false, "use release name in the output-dir path.")
+	f.BoolVar(&showValuesOrigin, "show-values-origin", false, "print the computed values along with the source of each of them instead of the rendered templates")
 	bindPostRenderFlag(cmd, &client.PostRenderer)
+	bindLookupFixturesFlag(cmd, &cfg.LookupClientProvider)
+	bindCapabilitiesFileFlag(cmd, &client.Capabilities)
 
 	return cmd
 }
@@ -121,6 +121,41 @@ func TestTemplateCmd(t *testing.T) {
 			// don't accidentally get the expected result.
 			repeat: 10,
 		},
+		{
+			name:   "template with lookup fixtures",
+			cmd:    fmt.Sprintf("template '%s' --lookup-fixtures '%s'", "testdata/testcharts/chart-with-lookup", "testdata/lookup-fixtures.yaml"),
+			golden: "output/template-with-lookup-fixtures.txt",
+		},
+		{
+			name:   "template without lookup fixtures",
+			cmd:    fmt.Sprintf("template '%s'", "testdata/testcharts/chart-with-lookup"),
+			golden: "output/template-without-lookup-fixtures.txt",
+		},
+		{
+			name:   "check capabilities file",
+			cmd:    fmt.Sprintf("template --capabilities-file testdata/capabilities.yaml '%s'", chartPath),
+			golden: "output/template-with-capabilities-file.txt",
+		},
+		{
+			name:   "check capabilities file with kube version and api versions",
+			cmd:    fmt.Sprintf("template --capabilities-file testdata/capabilities.yaml --kube-version 1.30.0 --api-versions helm.k8s.io/test2 '%s'", chartPath),
+			golden: "output/template-with-capabilities-file-and-overrides.txt",
+		},
+		{
+			name:      "check missing capabilities file",
+			cmd:       fmt.Sprintf("template --capabilities-file testdata/does-not-exist.yaml '%s'", chartPath),
+			wantError: true,
+		},
+		{
+			name:   "template with values origin",
+			cmd:    fmt.Sprintf("template '%s' --show-values-origin --set service.name=apache --set global.foo=bar", chartPath),
+			golden: "output/template-show-values-origin.txt",
+		},
+		{
+			name:      "template with missing lookup fixtures",
+			cmd:       fmt.Sprintf("template '%s' --lookup-fixtures '%s'", "testdata/testcharts/chart-with-lookup", "testdata/does-not-exist.yaml"),
+			wantError: true,
+		},
 		{
 			name:      "chart with template with invalid yaml",
 			cmd:       fmt.Sprintf("template '%s'", "testdata/testcharts/chart-with-template-with-invalid-yaml"),
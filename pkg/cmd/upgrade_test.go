@@ -187,6 +187,13 @@ func TestUpgradeCmd(t *testing.T) {
 			golden: "output/upgrade-uninstalled-with-keep-history.txt",
 			rels:   []*release.Release{relWithStatusMock("funny-bunny", 2, ch, release.StatusUninstalled)},
 		},
+		{
+			name:      "upgrade with lookup fixtures without client dry-run",
+			cmd:       fmt.Sprintf("upgrade funny-bunny '%s' --lookup-fixtures testdata/lookup-fixtures.yaml", chartPath),
+			golden:    "output/upgrade-lookup-fixtures-without-dry-run.txt",
+			wantError: true,
+			rels:      []*release.Release{relMock("funny-bunny", 2, ch)},
+		},
 	}
 	runTestCmd(t, tests)
 }
@@ -23,6 +23,7 @@ import (
 
 	"helm.sh/helm/v4/pkg/action"
 	"helm.sh/helm/v4/pkg/cmd/require"
+	"helm.sh/helm/v4/pkg/registry"
 )
 
 const verifyDesc = `
@@ -34,10 +35,17 @@ tampered with, and was packaged by a trusted provider.
 This command can be used to verify a local chart. Several other commands provide
 '--verify' flags that run the same validation. To generate a signed package, use
 the 'helm package --sign' command.
+
+A chart in an OCI registry is verified by the provenance it was pushed with, or
+by the detached signatures attached to it with 'helm sign'. The chart is valid
+when one of them verifies it:
+
+  $ helm verify oci://registry.example.com/charts/mychart:0.1.0
 `
 
 func newVerifyCmd(out io.Writer) *cobra.Command {
 	client := action.NewVerify()
+	o := &registryClientOptions{}
 
 	cmd := &cobra.Command{
 		Use:   "verify PATH",
@@ -53,6 +61,14 @@ func newVerifyCmd(out io.Writer) *cobra.Command {
 			return noMoreArgsComp()
 		},
 		RunE: func(_ *cobra.Command, args []string) error {
+			if registry.IsOCI(args[0]) {
+				registryClient, err := newManagedRegistryClient(args[0], o)
+				if err != nil {
+					return err
+				}
+				client.SetRegistryClient(registryClient)
+			}
+
 			err := client.Run(args[0])
 			if err != nil {
 				return err
@@ -64,7 +80,9 @@ func newVerifyCmd(out io.Writer) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&client.Keyring, "keyring", defaultKeyring(), "keyring containing public keys")
+	f := cmd.Flags()
+	f.StringVar(&client.Keyring, "keyring", defaultKeyring(), "keyring containing public keys")
+	addRegistryClientFlags(f, o)
 
 	return cmd
 }
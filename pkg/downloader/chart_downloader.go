@@ -16,17 +16,20 @@ limitations under the License.
 package downloader
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"helm.sh/helm/v4/internal/fileutil"
 	"helm.sh/helm/v4/internal/urlutil"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
 	"helm.sh/helm/v4/pkg/getter"
 	"helm.sh/helm/v4/pkg/helmpath"
 	"helm.sh/helm/v4/pkg/provenance"
@@ -97,6 +100,9 @@ func (c *ChartDownloader) DownloadTo(ref, version, dest string) (string, *proven
 	}
 
 	c.Options = append(c.Options, getter.WithAcceptHeader("application/gzip,application/octet-stream"))
+	if c.RepositoryCache != "" {
+		c.Options = append(c.Options, getter.WithCacheDir(filepath.Join(c.RepositoryCache, helmpath.CacheHTTPDir())))
+	}
 
 	data, err := g.Get(u.String(), c.Options...)
 	if err != nil {
@@ -104,9 +110,17 @@ func (c *ChartDownloader) DownloadTo(ref, version, dest string) (string, *proven
 	}
 
 	name := filepath.Base(u.Path)
-	if u.Scheme == registry.OCIScheme {
+	if u.Scheme == registry.OCIScheme && !strings.Contains(name, "@") {
 		idx := strings.LastIndexByte(name, ':')
 		name = fmt.Sprintf("%s-%s.tgz", name[:idx], name[idx+1:])
+	} else if u.Scheme == registry.OCIScheme || u.Scheme == registry.OCILayoutScheme {
+		// The path names a digest or the OCI image layout, not the version of
+		// the chart.
+		ch, err := loader.LoadArchive(bytes.NewReader(data.Bytes()))
+		if err != nil {
+			return "", nil, err
+		}
+		name = fmt.Sprintf("%s-%s.tgz", ch.Name(), ch.Metadata.Version)
 	}
 
 	destfile := filepath.Join(dest, name)
@@ -117,7 +131,7 @@ func (c *ChartDownloader) DownloadTo(ref, version, dest string) (string, *proven
 	// If provenance is requested, verify it.
 	ver := &provenance.Verification{}
 	if c.Verify > VerifyNever {
-		body, err := g.Get(u.String() + ".prov")
+		provs, err := c.provenance(g, u)
 		if err != nil {
 			if c.Verify == VerifyAlways {
 				return destfile, ver, fmt.Errorf("failed to fetch provenance %q", u.String()+".prov")
@@ -126,13 +140,18 @@ func (c *ChartDownloader) DownloadTo(ref, version, dest string) (string, *proven
 			return destfile, ver, nil
 		}
 		provfile := destfile + ".prov"
-		if err := fileutil.AtomicWriteFile(provfile, body, 0644); err != nil {
-			return destfile, nil, err
-		}
-
-		if c.Verify != VerifyLater {
+		for i, prov := range provs {
+			if err := fileutil.AtomicWriteFile(provfile, bytes.NewReader(prov), 0644); err != nil {
+				return destfile, nil, err
+			}
+			if c.Verify == VerifyLater {
+				break
+			}
 			ver, err = VerifyChart(destfile, c.Keyring)
-			if err != nil {
+			if err == nil {
+				break
+			}
+			if i == len(provs)-1 {
 				// Fail always in this case, since it means the verification step
 				// failed.
 				return destfile, ver, err
@@ -142,6 +161,81 @@ func (c *ChartDownloader) DownloadTo(ref, version, dest string) (string, *proven
 	return destfile, ver, nil
 }
 
+// provenance returns the provenance data of a chart.
+//
+// A chart in an OCI registry that was pushed without a provenance layer may
+// have detached signatures instead. Their provenance data is returned most
+// recent first, and the chart is verified by the first one that verifies it.
+func (c *ChartDownloader) provenance(g getter.Getter, u *url.URL) ([][]byte, error) {
+	body, err := g.Get(u.String() + ".prov")
+	if err == nil {
+		return [][]byte{body.Bytes()}, nil
+	}
+	if u.Scheme != registry.OCIScheme || c.RegistryClient == nil {
+		return nil, err
+	}
+	signatures, serr := c.RegistryClient.Signatures(strings.TrimPrefix(u.String(), registry.OCIScheme+"://"))
+	if serr != nil {
+		return nil, fmt.Errorf("%w, and the detached signatures could not be listed: %w", err, serr)
+	}
+	if len(signatures) == 0 {
+		return nil, err
+	}
+	provs := make([][]byte, 0, len(signatures))
+	for _, s := range signatures {
+		provs = append(provs, s.Prov)
+	}
+	return provs, nil
+}
+
+// Attachments returns the files attached to a chart in an OCI registry. Their
+// content is not fetched.
+func (c *ChartDownloader) Attachments(ref, version string) ([]*registry.Attachment, error) {
+	if !registry.IsOCI(ref) {
+		return nil, fmt.Errorf("%s is not an OCI reference, only charts in OCI registries have attachments", ref)
+	}
+	u, err := c.ResolveChartVersion(ref, version)
+	if err != nil {
+		return nil, err
+	}
+	return c.RegistryClient.Attachments(strings.TrimPrefix(u.String(), registry.OCIScheme+"://"))
+}
+
+// DownloadAttachmentsTo downloads the files attached to a chart in an OCI
+// registry to dest, and returns their paths.
+func (c *ChartDownloader) DownloadAttachmentsTo(ref, version, dest string) ([]string, error) {
+	if !registry.IsOCI(ref) {
+		return nil, fmt.Errorf("%s is not an OCI reference, only charts in OCI registries have attachments", ref)
+	}
+	u, err := c.ResolveChartVersion(ref, version)
+	if err != nil {
+		return nil, err
+	}
+	ociRef := strings.TrimPrefix(u.String(), registry.OCIScheme+"://")
+	attachments, err := c.RegistryClient.Attachments(ociRef)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(attachments))
+	for _, a := range attachments {
+		// The name is set by whoever pushed the chart, and must not escape
+		// the destination.
+		if a.Name != filepath.Base(a.Name) || a.Name == "." || a.Name == ".." {
+			return files, fmt.Errorf("invalid attachment name %q", a.Name)
+		}
+		if err := c.RegistryClient.FetchAttachment(ociRef, a); err != nil {
+			return files, err
+		}
+		file := filepath.Join(dest, a.Name)
+		if err := fileutil.AtomicWriteFile(file, bytes.NewReader(a.Data), 0644); err != nil {
+			return files, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
 // ResolveChartVersion resolves a chart reference to a URL.
 //
 // It returns the URL and sets the ChartDownloader's Options that can fetch
@@ -167,6 +261,10 @@ func (c *ChartDownloader) ResolveChartVersion(ref, version string) (*url.URL, er
 		return c.RegistryClient.ValidateReference(ref, version, u)
 	}
 
+	if u.Scheme == registry.OCILayoutScheme {
+		return u, nil
+	}
+
 	rf, err := loadRepoConfig(c.RepositoryConfig)
 	if err != nil {
 		return u, err
@@ -245,8 +343,8 @@ func (c *ChartDownloader) ResolveChartVersion(ref, version string) (*url.URL, er
 	}
 
 	// Next, we need to load the index, and actually look up the chart.
-	idxFile := filepath.Join(c.RepositoryCache, helmpath.CacheIndexFile(r.Config.Name))
-	i, err := repo.LoadIndexFile(idxFile)
+	r.CachePath = c.RepositoryCache
+	i, err := r.LoadIndex()
 	if err != nil {
 		return u, fmt.Errorf("no cached repo found. (try 'helm repo update'): %w", err)
 	}
@@ -337,20 +435,34 @@ func pickChartRepositoryConfigByName(name string, cfgs []*repo.Entry) (*repo.Ent
 func (c *ChartDownloader) scanReposForURL(u string, rf *repo.File) (*repo.Entry, error) {
 	// FIXME: This is far from optimal. Larger installations and index files will
 	// incur a performance hit for this type of scanning.
+	archive := u
+	if parsed, err := url.Parse(u); err == nil {
+		archive = path.Base(parsed.Path)
+	}
 	for _, rc := range rf.Repositories {
 		r, err := repo.NewChartRepository(rc, c.Getters)
 		if err != nil {
 			return nil, err
 		}
 
-		idxFile := filepath.Join(c.RepositoryCache, helmpath.CacheIndexFile(r.Config.Name))
-		i, err := repo.LoadIndexFile(idxFile)
+		r.CachePath = c.RepositoryCache
+		index, err := r.LoadIndex()
 		if err != nil {
 			return nil, fmt.Errorf("no cached repo found. (try 'helm repo update'): %w", err)
 		}
 
-		for _, entry := range i.Entries {
-			for _, ver := range entry {
+		for _, name := range index.ChartNames() {
+			// Only the versions of the charts that the archive could be are
+			// loaded, so that the versions of every chart of a sharded index
+			// are not downloaded.
+			if index.Sharded() && !strings.HasPrefix(archive, name+"-") {
+				continue
+			}
+			cvs, err := index.Versions(name)
+			if err != nil {
+				return nil, err
+			}
+			for _, ver := range cvs {
 				for _, dl := range ver.URLs {
 					if urlutil.Equal(u, dl) {
 						return rc, nil
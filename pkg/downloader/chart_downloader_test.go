@@ -16,8 +16,11 @@ limitations under the License.
 package downloader
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"helm.sh/helm/v4/internal/test/ensure"
@@ -27,12 +30,22 @@ import (
 	"helm.sh/helm/v4/pkg/repo/repotest"
 )
 
-const (
-	repoConfig = "testdata/repositories.yaml"
-	repoCache  = "testdata/repository"
-)
+const repoConfig = "testdata/repositories.yaml"
+
+// newRepoCache returns a repository cache with the indexes of the test
+// repositories. The files that the tests download are cached in it rather
+// than in testdata.
+func newRepoCache(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.CopyFS(dir, os.DirFS("testdata/repository")); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
 
 func TestResolveChartRef(t *testing.T) {
+	repoCache := newRepoCache(t)
 	tests := []struct {
 		name, ref, expect, version string
 		fail                       bool
@@ -86,6 +99,7 @@ func TestResolveChartRef(t *testing.T) {
 }
 
 func TestResolveChartOpts(t *testing.T) {
+	repoCache := newRepoCache(t)
 	tests := []struct {
 		name, ref, version string
 		expect             []getter.Option
@@ -176,6 +190,7 @@ func TestIsTar(t *testing.T) {
 }
 
 func TestDownloadTo(t *testing.T) {
+	repoCache := newRepoCache(t)
 	srv := repotest.NewTempServer(
 		t,
 		repotest.WithChartSourceGlob("testdata/*.tgz*"),
@@ -283,6 +298,7 @@ func TestDownloadTo_TLS(t *testing.T) {
 }
 
 func TestDownloadTo_VerifyLater(t *testing.T) {
+	repoCache := newRepoCache(t)
 	ensure.HelmHome(t)
 
 	dest := t.TempDir()
@@ -326,6 +342,7 @@ func TestDownloadTo_VerifyLater(t *testing.T) {
 }
 
 func TestScanReposForURL(t *testing.T) {
+	repoCache := newRepoCache(t)
 	c := ChartDownloader{
 		Out:              os.Stderr,
 		Verify:           VerifyLater,
@@ -358,3 +375,54 @@ func TestScanReposForURL(t *testing.T) {
 		t.Fatalf("expected ErrNoOwnerRepo, got %v", err)
 	}
 }
+
+func TestScanReposForURLSharded(t *testing.T) {
+	i, err := repo.LoadIndexFile("testdata/repository/testing-index.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvDir := t.TempDir()
+	if err := i.WriteShardedIndex(filepath.Join(srvDir, repo.ShardedIndexDir), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	requests := map[string]int{}
+	fs := http.FileServer(http.Dir(srvDir))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		fs.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	repoCache := t.TempDir()
+	getters := getter.All(&cli.EnvSettings{})
+	entry := &repo.Entry{Name: "sharded", URL: srv.URL}
+	r, err := repo.NewChartRepository(entry, getters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.CachePath = repoCache
+	if err := r.DownloadIndex(); err != nil {
+		t.Fatal(err)
+	}
+
+	rf := repo.NewFile()
+	rf.Add(entry)
+	c := ChartDownloader{
+		Out:             os.Stderr,
+		RepositoryCache: repoCache,
+		Getters:         getters,
+	}
+	found, err := c.scanReposForURL("http://example.com/alpine-0.2.0.tgz", rf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Name != "sharded" {
+		t.Errorf("unexpected repo %q", found.Name)
+	}
+	if requests["/index.d/charts/alpine.yaml"] != 1 || requests["/index.d/charts/foo.yaml"] != 0 {
+		t.Errorf("expected only the versions of alpine to be downloaded, got %v", requests)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 
@@ -40,7 +41,6 @@ import (
 	"helm.sh/helm/v4/pkg/chart/v2/loader"
 	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
 	"helm.sh/helm/v4/pkg/getter"
-	"helm.sh/helm/v4/pkg/helmpath"
 	"helm.sh/helm/v4/pkg/registry"
 	"helm.sh/helm/v4/pkg/repo"
 )
@@ -75,6 +75,19 @@ type Manager struct {
 	RegistryClient   *registry.Client
 	RepositoryConfig string
 	RepositoryCache  string
+	// Recursive first builds or updates the dependencies of dependencies
+	// from local paths ("file://"), starting with the leaves.
+	Recursive bool
+}
+
+// dependencyWalk tracks the charts visited by a recursive build or update.
+type dependencyWalk struct {
+	// done holds the paths of the charts whose dependencies were processed.
+	done map[string]bool
+	// stack holds the paths of the charts that are being processed.
+	stack []string
+	// reposUpdated is set once the repositories were updated.
+	reposUpdated bool
 }
 
 // Build rebuilds a local charts directory from a lockfile.
@@ -83,16 +96,24 @@ type Manager struct {
 //
 // If SkipUpdate is set, this will not update the repository.
 func (m *Manager) Build() error {
+	return m.build(&dependencyWalk{done: map[string]bool{}})
+}
+
+func (m *Manager) build(walk *dependencyWalk) error {
 	c, err := m.loadChartDir()
 	if err != nil {
 		return err
 	}
 
+	if err := m.walkLocalDependencies(c, walk, (*Manager).build); err != nil {
+		return err
+	}
+
 	// If a lock file is found, run a build from that. Otherwise, just do
 	// an update.
 	lock := c.Lock
 	if lock == nil {
-		return m.Update()
+		return m.update(walk)
 	}
 
 	// Check that all of the repos we're dependent on actually exist.
@@ -133,11 +154,12 @@ func (m *Manager) Build() error {
 		return err
 	}
 
-	if !m.SkipUpdate {
+	if !m.SkipUpdate && !walk.reposUpdated {
 		// For each repo in the file, update the cached copy of that repo
 		if err := m.UpdateRepositories(); err != nil {
 			return err
 		}
+		walk.reposUpdated = true
 	}
 
 	// Now we need to fetch every package here into charts/
@@ -150,11 +172,19 @@ func (m *Manager) Build() error {
 // negotiate versions based on that. It will download the versions
 // from remote chart repositories unless SkipUpdate is true.
 func (m *Manager) Update() error {
+	return m.update(&dependencyWalk{done: map[string]bool{}})
+}
+
+func (m *Manager) update(walk *dependencyWalk) error {
 	c, err := m.loadChartDir()
 	if err != nil {
 		return err
 	}
 
+	if err := m.walkLocalDependencies(c, walk, (*Manager).update); err != nil {
+		return err
+	}
+
 	// If no dependencies are found, we consider this a successful
 	// completion.
 	req := c.Metadata.Dependencies
@@ -183,10 +213,11 @@ func (m *Manager) Update() error {
 
 	// For each of the repositories Helm is configured to know about, update
 	// the index information locally.
-	if !m.SkipUpdate {
+	if !m.SkipUpdate && !walk.reposUpdated {
 		if err := m.UpdateRepositories(); err != nil {
 			return err
 		}
+		walk.reposUpdated = true
 	}
 
 	// Now we need to find out which version of a chart best satisfies the
@@ -218,6 +249,55 @@ func (m *Manager) Update() error {
 	return writeLock(m.ChartPath, lock, c.Metadata.APIVersion == chart.APIVersionV1)
 }
 
+// walkLocalDependencies runs fn for every dependency of the chart from a local
+// path before the chart itself is processed, if the manager is recursive.
+//
+// Charts are processed once, even if several charts depend on them. A
+// dependency cycle is an error.
+func (m *Manager) walkLocalDependencies(c *chart.Chart, walk *dependencyWalk, fn func(*Manager, *dependencyWalk) error) error {
+	if !m.Recursive {
+		return nil
+	}
+	self, err := filepath.Abs(m.ChartPath)
+	if err != nil {
+		return err
+	}
+	walk.stack = append(walk.stack, self)
+	defer func() { walk.stack = walk.stack[:len(walk.stack)-1] }()
+
+	for _, dep := range c.Metadata.Dependencies {
+		if !strings.HasPrefix(dep.Repository, "file://") {
+			continue
+		}
+		depPath, err := resolver.GetLocalPath(dep.Repository, m.ChartPath)
+		if err != nil {
+			return err
+		}
+		if depPath, err = filepath.Abs(depPath); err != nil {
+			return err
+		}
+		if i := slices.Index(walk.stack, depPath); i >= 0 {
+			var cycle []string
+			for _, p := range append(walk.stack[i:], depPath) {
+				cycle = append(cycle, filepath.Base(p))
+			}
+			return fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+		if walk.done[depPath] {
+			continue
+		}
+
+		fmt.Fprintf(m.Out, "Processing dependencies of %s\n", depPath)
+		sub := *m
+		sub.ChartPath = depPath
+		if err := fn(&sub, walk); err != nil {
+			return fmt.Errorf("%s: %w", dep.Name, err)
+		}
+		walk.done[depPath] = true
+	}
+	return nil
+}
+
 func (m *Manager) loadChartDir() (*chart.Chart, error) {
 	if fi, err := os.Stat(m.ChartPath); err != nil {
 		return nil, fmt.Errorf("could not find %s: %w", m.ChartPath, err)
@@ -232,6 +312,9 @@ func (m *Manager) loadChartDir() (*chart.Chart, error) {
 // This returns a lock file, which has all of the dependencies normalized to a specific version.
 func (m *Manager) resolve(req []*chart.Dependency, repoNames map[string]string) (*chart.Lock, error) {
 	res := resolver.New(m.ChartPath, m.RepositoryCache, m.RegistryClient)
+	if rf, err := loadRepoConfig(m.RepositoryConfig); err == nil {
+		res.SetRepositories(rf.Repositories, m.Getters)
+	}
 	return res.Resolve(req, repoNames)
 }
 
@@ -269,7 +352,7 @@ func (m *Manager) downloadAll(deps []*chart.Dependency) error {
 
 	fmt.Fprintf(m.Out, "Saving %d charts\n", len(deps))
 	var saveError error
-	churls := make(map[string]struct{})
+	churls := make(map[string]string)
 	for _, dep := range deps {
 		// No repository means the chart is in charts directory
 		if dep.Repository == "" {
@@ -309,6 +392,28 @@ func (m *Manager) downloadAll(deps []*chart.Dependency) error {
 			dep.Version = ver
 			continue
 		}
+		if resolver.IsGitRepository(dep.Repository) {
+			fmt.Fprintf(m.Out, "Checking out %s from repo %s\n", dep.Name, dep.Repository)
+			origPath, err := resolver.GitCheckout(dep, m.RepositoryCache)
+			if err != nil {
+				saveError = err
+				break
+			}
+			// Dependencies from Git repositories are locked to the commit
+			// that GitCheckout verifies, not to the digest of the archive
+			// built from it, which depends on the compressor.
+			ch, err := loadDirDependency(origPath, dep.Name, dep.Version)
+			if err != nil {
+				saveError = err
+				break
+			}
+			if _, err := chartutil.Save(ch, tmpPath); err != nil {
+				saveError = err
+				break
+			}
+			dep.Version = ch.Metadata.Version
+			continue
+		}
 
 		// Any failure to resolve/download a chart should fail:
 		// https://github.com/helm/helm/issues/1439
@@ -317,9 +422,16 @@ func (m *Manager) downloadAll(deps []*chart.Dependency) error {
 			saveError = fmt.Errorf("could not find %s: %w", churl, err)
 			break
 		}
+		if registry.IsOCI(dep.Repository) {
+			churl = resolver.OCIChartRef(dep)
+		}
 
-		if _, ok := churls[churl]; ok {
+		if filename, ok := churls[churl]; ok {
 			fmt.Fprintf(m.Out, "Already downloaded %s from repo %s\n", dep.Name, dep.Repository)
+			if err := checkDigest(dep, filename); err != nil {
+				saveError = err
+				break
+			}
 			continue
 		}
 
@@ -342,7 +454,10 @@ func (m *Manager) downloadAll(deps []*chart.Dependency) error {
 		}
 
 		version := ""
-		if registry.IsOCI(churl) {
+		if registry.IsOCI(churl) && strings.Contains(churl, "@") {
+			// The dependency is pulled by the digest of its manifest.
+			dl.Options = append(dl.Options, getter.WithRegistryClient(m.RegistryClient))
+		} else if registry.IsOCI(churl) {
 			churl, version, err = parseOCIRef(churl)
 			if err != nil {
 				return fmt.Errorf("could not parse OCI reference: %w", err)
@@ -352,12 +467,17 @@ func (m *Manager) downloadAll(deps []*chart.Dependency) error {
 				getter.WithTagName(version))
 		}
 
-		if _, _, err = dl.DownloadTo(churl, version, tmpPath); err != nil {
+		filename, _, err := dl.DownloadTo(churl, version, tmpPath)
+		if err != nil {
 			saveError = fmt.Errorf("could not download %s: %w", churl, err)
 			break
 		}
+		if err := checkDigest(dep, filename); err != nil {
+			saveError = err
+			break
+		}
 
-		churls[churl] = struct{}{}
+		churls[churl] = filename
 	}
 
 	// TODO: this should probably be refactored to be a []error, so we can capture and provide more information rather than "last error wins".
@@ -471,8 +591,8 @@ func (m *Manager) hasAllRepos(deps []*chart.Dependency) error {
 	missing := []string{}
 Loop:
 	for _, dd := range deps {
-		// If repo is from local path or OCI, continue
-		if strings.HasPrefix(dd.Repository, "file://") || registry.IsOCI(dd.Repository) {
+		// If repo is from local path, OCI or Git, continue
+		if strings.HasPrefix(dd.Repository, "file://") || registry.IsOCI(dd.Repository) || resolver.IsGitRepository(dd.Repository) {
 			continue
 		}
 
@@ -589,7 +709,7 @@ func (m *Manager) resolveRepoNames(deps []*chart.Dependency) (map[string]string,
 			continue
 		}
 
-		if registry.IsOCI(dd.Repository) {
+		if registry.IsOCI(dd.Repository) || resolver.IsGitRepository(dd.Repository) {
 			reposMap[dd.Name] = dd.Repository
 			continue
 		}
@@ -688,7 +808,7 @@ func (m *Manager) parallelRepoUpdate(repos []*repo.Entry) error {
 		r.CachePath = m.RepositoryCache
 		wg.Add(1)
 		go func(r *repo.ChartRepository) {
-			if _, err := r.DownloadIndexFile(); err != nil {
+			if err := r.DownloadIndex(); err != nil {
 				// For those dependencies that are not known to helm and using a
 				// generated key name we display the repo url.
 				if strings.HasPrefix(r.Config.Name, managerKeyPrefix) {
@@ -771,12 +891,15 @@ func (m *Manager) findChartURL(name, version, repoURL string, repos map[string]*
 //
 // It returns the ChartVersions for that entry.
 func findEntryByName(name string, cr *repo.ChartRepository) (repo.ChartVersions, error) {
-	for ename, entry := range cr.IndexFile.Entries {
-		if ename == name {
-			return entry, nil
-		}
+	index, err := cr.LoadIndex()
+	if err != nil {
+		return nil, err
+	}
+	entry, err := index.Versions(name)
+	if errors.Is(err, repo.ErrNoChartName) {
+		return nil, errors.New("entry not found")
 	}
-	return nil, errors.New("entry not found")
+	return entry, err
 }
 
 // findVersionedEntry takes a ChartVersions list and returns a single chart version that satisfies the version constraints.
@@ -824,16 +947,15 @@ func (m *Manager) loadChartRepositories() (map[string]*repo.ChartRepository, err
 
 	for _, re := range rf.Repositories {
 		lname := re.Name
-		idxFile := filepath.Join(m.RepositoryCache, helmpath.CacheIndexFile(lname))
-		index, err := repo.LoadIndexFile(idxFile)
+		cr, err := repo.NewChartRepository(re, m.Getters)
 		if err != nil {
-			return indices, err
+			// The getter is only needed to download the versions of the
+			// charts in a sharded index.
+			cr = &repo.ChartRepository{Config: re}
 		}
-
-		// TODO: use constructor
-		cr := &repo.ChartRepository{
-			Config:    re,
-			IndexFile: index,
+		cr.CachePath = m.RepositoryCache
+		if _, err := cr.LoadIndex(); err != nil {
+			return indices, err
 		}
 		indices[lname] = cr
 	}
@@ -879,27 +1001,53 @@ func tarFromLocalDir(chartpath, name, repo, version, destPath string) (string, e
 		return "", err
 	}
 
-	ch, err := loader.LoadDir(origPath)
+	ch, err := loadDirDependency(origPath, name, version)
 	if err != nil {
 		return "", err
 	}
+	_, err = chartutil.Save(ch, destPath)
+	return ch.Metadata.Version, err
+}
+
+// load a dep chart from a directory and check it against the version constraint
+func loadDirDependency(origPath, name, version string) (*chart.Chart, error) {
+	ch, err := loader.LoadDir(origPath)
+	if err != nil {
+		return nil, err
+	}
 
 	constraint, err := semver.NewConstraint(version)
 	if err != nil {
-		return "", fmt.Errorf("dependency %s has an invalid version/constraint format: %w", name, err)
+		return nil, fmt.Errorf("dependency %s has an invalid version/constraint format: %w", name, err)
 	}
 
 	v, err := semver.NewVersion(ch.Metadata.Version)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if constraint.Check(v) {
-		_, err = chartutil.Save(ch, destPath)
-		return ch.Metadata.Version, err
+	if !constraint.Check(v) {
+		return nil, fmt.Errorf("can't get a valid version for dependency %s", name)
 	}
+	return ch, nil
+}
 
-	return "", fmt.Errorf("can't get a valid version for dependency %s", name)
+// checkDigest verifies the archive of a dependency against the digest in the
+// lock file. If the dependency has no digest yet, the digest of the archive is
+// recorded.
+func checkDigest(dep *chart.Dependency, filename string) error {
+	digest, err := resolver.HashArchive(filename)
+	if err != nil {
+		return err
+	}
+	if dep.Digest == "" {
+		dep.Digest = digest
+		return nil
+	}
+	if dep.Digest != digest {
+		return fmt.Errorf("digest of %s %s does not match the lock file: expected %s, got %s", dep.Name, dep.Version, dep.Digest, digest)
+	}
+	return nil
 }
 
 // The prefix to use for cache keys created by the manager for repo names
@@ -22,16 +22,19 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"sigs.k8s.io/yaml"
 
+	"helm.sh/helm/v4/internal/test/ensure"
 	chart "helm.sh/helm/v4/pkg/chart/v2"
 	"helm.sh/helm/v4/pkg/chart/v2/loader"
 	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
 	"helm.sh/helm/v4/pkg/getter"
+	"helm.sh/helm/v4/pkg/provenance"
 	"helm.sh/helm/v4/pkg/repo"
 	"helm.sh/helm/v4/pkg/repo/repotest"
 )
@@ -56,6 +59,7 @@ func TestVersionEquals(t *testing.T) {
 }
 
 func TestFindChartURL(t *testing.T) {
+	repoCache := newRepoCache(t)
 	var b bytes.Buffer
 	m := &Manager{
 		Out:              &b,
@@ -144,6 +148,7 @@ func TestFindChartURL(t *testing.T) {
 }
 
 func TestGetRepoNames(t *testing.T) {
+	repoCache := newRepoCache(t)
 	b := bytes.NewBuffer(nil)
 	m := &Manager{
 		Out:              b,
@@ -229,6 +234,7 @@ func TestGetRepoNames(t *testing.T) {
 }
 
 func TestDownloadAll(t *testing.T) {
+	repoCache := newRepoCache(t)
 	chartPath := t.TempDir()
 	m := &Manager{
 		Out:              new(bytes.Buffer),
@@ -368,6 +374,164 @@ func TestUpdateBeforeBuild(t *testing.T) {
 	}
 }
 
+func TestBuildVerifiesDigests(t *testing.T) {
+	// Set up a fake repo
+	srv := repotest.NewTempServer(
+		t,
+		repotest.WithChartSourceGlob("testdata/*.tgz*"),
+	)
+	defer srv.Stop()
+	if err := srv.LinkIndices(); err != nil {
+		t.Fatal(err)
+	}
+	dir := func(p ...string) string {
+		return filepath.Join(append([]string{srv.Root()}, p...)...)
+	}
+
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name:       "with-dependency",
+			Version:    "0.1.0",
+			APIVersion: "v2",
+			Dependencies: []*chart.Dependency{{
+				Name:       "local-subchart",
+				Version:    "0.1.0",
+				Repository: srv.URL(),
+			}},
+		},
+	}
+	if err := chartutil.SaveDir(c, dir()); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Manager{
+		ChartPath:        dir(c.Metadata.Name),
+		Out:              new(bytes.Buffer),
+		Getters:          getter.Providers{getter.Provider{Schemes: []string{"http", "https"}, New: getter.NewHTTPGetter}},
+		RepositoryConfig: dir("repositories.yaml"),
+		RepositoryCache:  dir(),
+	}
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	ch, err := loader.LoadDir(m.ChartPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect, err := provenance.DigestFile("testdata/local-subchart-0.1.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest := ch.Lock.Dependencies[0].Digest; digest != "sha256:"+expect {
+		t.Fatalf("expected digest sha256:%s in the lock file, got %q", expect, digest)
+	}
+
+	if err := m.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Republish the same version with different contents
+	republished, err := loader.Load("testdata/local-subchart-0.1.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	republished.Metadata.Description = "republished"
+	if _, err := chartutil.Save(republished, dir()); err != nil {
+		t.Fatal(err)
+	}
+
+	err = m.Build()
+	if err == nil {
+		t.Fatal("expected Build to fail on a digest mismatch")
+	}
+	if !strings.Contains(err.Error(), "does not match the lock file") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestBuildRecursive(t *testing.T) {
+	dir := t.TempDir()
+	save := func(name string, deps ...string) {
+		t.Helper()
+		c := &chart.Chart{Metadata: &chart.Metadata{Name: name, Version: "0.1.0", APIVersion: chart.APIVersionV2}}
+		for _, d := range deps {
+			c.Metadata.Dependencies = append(c.Metadata.Dependencies, &chart.Dependency{
+				Name:       d,
+				Version:    "0.1.0",
+				Repository: "file://../" + d,
+			})
+		}
+		if err := chartutil.SaveDir(c, dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save("library")
+	save("service", "library")
+	save("umbrella", "service", "library")
+
+	b := new(bytes.Buffer)
+	m := &Manager{
+		ChartPath:        filepath.Join(dir, "umbrella"),
+		Out:              b,
+		RepositoryConfig: filepath.Join(dir, "repositories.yaml"),
+		RepositoryCache:  dir,
+		Recursive:        true,
+	}
+	if err := m.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The library is processed once, before the service.
+	if n := strings.Count(b.String(), "Processing dependencies of "+filepath.Join(dir, "library")); n != 1 {
+		t.Errorf("expected the library to be processed once, got %d times:\n%s", n, b)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "service", "charts", "library-0.1.0.tgz")); err != nil {
+		t.Error(err)
+	}
+	service, err := loader.Load(filepath.Join(dir, "umbrella", "charts", "service-0.1.0.tgz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deps := service.Dependencies(); len(deps) != 1 || deps[0].Name() != "library" {
+		t.Errorf("expected the packaged service to include the library, got %v", deps)
+	}
+}
+
+func TestBuildRecursiveCycle(t *testing.T) {
+	dir := t.TempDir()
+	for name, dep := range map[string]string{"ahab": "moby", "moby": "ahab"} {
+		c := &chart.Chart{Metadata: &chart.Metadata{
+			Name:       name,
+			Version:    "0.1.0",
+			APIVersion: chart.APIVersionV2,
+			Dependencies: []*chart.Dependency{{
+				Name:       dep,
+				Version:    "0.1.0",
+				Repository: "file://../" + dep,
+			}},
+		}}
+		if err := chartutil.SaveDir(c, dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := &Manager{
+		ChartPath:        filepath.Join(dir, "ahab"),
+		Out:              new(bytes.Buffer),
+		RepositoryConfig: filepath.Join(dir, "repositories.yaml"),
+		RepositoryCache:  dir,
+		Recursive:        true,
+	}
+	err := m.Build()
+	if err == nil {
+		t.Fatal("expected an error for a dependency cycle")
+	}
+	if !strings.Contains(err.Error(), "dependency cycle detected: ahab -> moby -> ahab") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
 // TestUpdateWithNoRepo is for the case of a dependency that has no repo listed.
 // This happens when the dependency is in the charts directory and does not need
 // to be fetched.
@@ -437,6 +601,60 @@ func TestUpdateWithNoRepo(t *testing.T) {
 	}
 }
 
+func TestUpdateWithGitDependency(t *testing.T) {
+	repoDir := ensure.GitRepo(t, "0.1.0", "0.2.0")
+	commit := ensure.GitRevParse(t, repoDir, "v0.1.0")
+	dir := t.TempDir()
+
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name:       "with-git-dependency",
+			Version:    "0.1.0",
+			APIVersion: chart.APIVersionV2,
+			Dependencies: []*chart.Dependency{{
+				Name:       "library",
+				Version:    "~0.1.0",
+				Repository: "git+file://" + filepath.ToSlash(repoDir) + "//charts/library",
+			}},
+		},
+	}
+	if err := chartutil.SaveDir(c, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Manager{
+		ChartPath:        filepath.Join(dir, c.Metadata.Name),
+		Out:              new(bytes.Buffer),
+		RepositoryConfig: filepath.Join(dir, "repositories.yaml"),
+		RepositoryCache:  filepath.Join(dir, "cache"),
+	}
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	ch, err := loader.LoadDir(m.ChartPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch.Lock == nil || len(ch.Lock.Dependencies) != 1 {
+		t.Fatalf("expected a lock file with one dependency, got %+v", ch.Lock)
+	}
+	if dep := ch.Lock.Dependencies[0]; dep.Version != "0.1.0" || dep.Commit != commit || dep.Digest != "" {
+		t.Errorf("expected library 0.1.0 locked to %s only, got %s locked to %s and %q", commit, dep.Version, dep.Commit, dep.Digest)
+	}
+
+	// Build checks out the locked commit.
+	if err := os.Remove(filepath.Join(m.ChartPath, "charts", "library-0.1.0.tgz")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(m.ChartPath, "charts", "library-0.1.0.tgz")); err != nil {
+		t.Error(err)
+	}
+}
+
 // This function is the skeleton test code of failing tests for #6416 and #6871 and bugs due to #5874.
 //
 // This function is used by below tests that ensures success of build operation
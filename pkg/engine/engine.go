@@ -55,10 +55,14 @@ type Engine struct {
 	LintMode bool
 	// optional provider of clients to talk to the Kubernetes API
 	clientProvider *ClientProvider
+	// lintLookup enables the lookup function in LintMode
+	lintLookup bool
 	// EnableDNS tells the engine to allow DNS lookups when rendering templates
 	EnableDNS bool
 	// CustomTemplateFuncs is defined by users to provide custom template funcs
 	CustomTemplateFuncs template.FuncMap
+	// Renderers render the templates that are not Go templates
+	Renderers RendererProviders
 }
 
 // New creates a new instance of Engine using the passed in rest config.
@@ -69,6 +73,18 @@ func New(config *rest.Config) Engine {
 	}
 }
 
+// NewWithClientProvider creates a new instance of Engine whose lookup function
+// is served by the passed in ClientProvider.
+//
+// Unlike New, the provider is also used in LintMode. This allows linting and
+// client-side rendering against offline fixtures (see LoadLookupFixtures).
+func NewWithClientProvider(clientProvider ClientProvider) Engine {
+	return Engine{
+		clientProvider: &clientProvider,
+		lintLookup:     true,
+	}
+}
+
 // Render takes a chart, optional values, and value overrides, and attempts to render the Go templates.
 //
 // Render can be called repeatedly on the same engine.
@@ -127,6 +143,8 @@ type renderable struct {
 	vals chartutil.Values
 	// namespace prefix to the templates of the current chart
 	basePath string
+	// renderer is the renderer selected by the chart, if any
+	renderer string
 }
 
 const warnStartDelim = "HELM_ERR_START"
@@ -248,8 +266,8 @@ func (e Engine) initFunMap(t *template.Template) {
 	}
 
 	// If we are not linting and have a cluster connection, provide a Kubernetes-backed
-	// implementation.
-	if !e.LintMode && e.clientProvider != nil {
+	// implementation. Providers that do not talk to a cluster may be used when linting.
+	if (!e.LintMode || e.lintLookup) && e.clientProvider != nil {
 		funcMap["lookup"] = newLookupFunction(*e.clientProvider)
 	}
 
@@ -296,8 +314,17 @@ func (e Engine) render(tpls map[string]renderable) (rendered map[string]string,
 	// higher-level (in file system) templates over deeply nested templates.
 	keys := sortTemplates(tpls)
 
+	renderers := make(map[string]Renderer)
 	for _, filename := range keys {
 		r := tpls[filename]
+		renderer, err := e.rendererFor(filename, r)
+		if err != nil {
+			return map[string]string{}, err
+		}
+		if renderer != nil {
+			renderers[filename] = renderer
+			continue
+		}
 		if _, err := t.New(filename).Parse(r.tpl); err != nil {
 			return map[string]string{}, cleanupParseError(filename, err)
 		}
@@ -313,6 +340,18 @@ func (e Engine) render(tpls map[string]renderable) (rendered map[string]string,
 		// At render time, add information about the template that is being rendered.
 		vals := tpls[filename].vals
 		vals["Template"] = chartutil.Values{"Name": filename, "BasePath": tpls[filename].basePath}
+		if renderer, ok := renderers[filename]; ok {
+			ctx, err := renderContext(vals)
+			if err != nil {
+				return map[string]string{}, fmt.Errorf("%s: %w", filename, err)
+			}
+			out, err := renderer.Render(filename, []byte(tpls[filename].tpl), ctx)
+			if err != nil {
+				return map[string]string{}, err
+			}
+			rendered[filename] = out
+			continue
+		}
 		var buf strings.Builder
 		if err := t.ExecuteTemplate(&buf, filename, vals); err != nil {
 			return map[string]string{}, reformatExecErrorMsg(filename, err)
@@ -505,6 +544,7 @@ func recAllTpls(c *chart.Chart, templates map[string]renderable, vals chartutil.
 			tpl:      string(t.Data),
 			vals:     next,
 			basePath: path.Join(newParentID, "templates"),
+			renderer: c.Metadata.Annotations[RendererAnnotation],
 		}
 	}
 
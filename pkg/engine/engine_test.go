@@ -420,6 +420,101 @@ func TestRenderWithClientProvider_error(t *testing.T) {
 	}
 }
 
+func TestRenderWithFixtureClientProvider(t *testing.T) {
+	fixtures := `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: default
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Pod
+  metadata:
+    name: pod1
+    namespace: default
+- apiVersion: v1
+  kind: Pod
+  metadata:
+    name: pod2
+    namespace: ns1
+`
+	objs, err := parseLookupFixtures([]byte(fixtures))
+	if err != nil {
+		t.Fatalf("Failed to parse fixtures: %s", err)
+	}
+	if len(objs) != 3 {
+		t.Fatalf("Expected 3 fixtures, got %d", len(objs))
+	}
+	provider := NewFixtureClientProvider(objs...)
+
+	cases := map[string]struct {
+		template string
+		output   string
+	}{
+		"ns-single":    {`{{ (lookup "v1" "Namespace" "" "default").metadata.name }}`, "default"},
+		"ns-list":      {`{{ (lookup "v1" "Namespace" "" "").items | len }}`, "1"},
+		"pod-single":   {`{{ (lookup "v1" "Pod" "ns1" "pod2").metadata.name }}`, "pod2"},
+		"pod-list":     {`{{ (lookup "v1" "Pod" "ns1" "").items | len }}`, "1"},
+		"pod-all":      {`{{ (lookup "v1" "Pod" "" "").items | len }}`, "2"},
+		"pod-missing":  {`{{ lookup "v1" "Pod" "default" "pod2" }}`, "map[]"},
+		"kind-missing": {`{{ (lookup "v1" "Secret" "default" "").items | len }}`, "0"},
+	}
+
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name:    "moby",
+			Version: "1.2.3",
+		},
+		Values: map[string]interface{}{},
+	}
+	for name, exp := range cases {
+		c.Templates = append(c.Templates, &chart.File{
+			Name: path.Join("templates", name),
+			Data: []byte(exp.template),
+		})
+	}
+
+	v, err := chartutil.CoalesceValues(c, map[string]interface{}{"Values": map[string]interface{}{}})
+	if err != nil {
+		t.Fatalf("Failed to coalesce values: %s", err)
+	}
+
+	// Fixtures are also served in lint mode.
+	e := NewWithClientProvider(provider)
+	e.LintMode = true
+	out, err := e.Render(c, v)
+	if err != nil {
+		t.Fatalf("Failed to render templates: %s", err)
+	}
+
+	for name, want := range cases {
+		t.Run(name, func(t *testing.T) {
+			key := path.Join("moby/templates", name)
+			if out[key] != want.output {
+				t.Errorf("Expected %q, got %q", want.output, out[key])
+			}
+		})
+	}
+}
+
+func TestParseLookupFixturesErrors(t *testing.T) {
+	cases := map[string]string{
+		"missing kind": "apiVersion: v1\nmetadata:\n  name: foo\n",
+		"missing name": "apiVersion: v1\nkind: Secret\n",
+		"invalid yaml": "apiVersion: [v1\n",
+	}
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseLookupFixtures([]byte(data)); err == nil {
+				t.Errorf("Expected an error parsing %q", data)
+			}
+		})
+	}
+}
+
 func TestParallelRenderInternals(t *testing.T) {
 	// Make sure that we can use one Engine to run parallel template renders.
 	e := new(Engine)
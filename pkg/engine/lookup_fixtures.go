@@ -0,0 +1,157 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+// fixtureClientProvider is a ClientProvider that serves objects from a fixed
+// set of Kubernetes objects instead of a live cluster.
+type fixtureClientProvider struct {
+	objects []runtime.Object
+	// namespaced records, per GroupVersionKind, whether any of the fixtures
+	// of that kind carry a namespace.
+	namespaced map[schema.GroupVersionKind]bool
+
+	mu sync.Mutex
+	// clients caches the fake client built for each resource.
+	clients map[schema.GroupVersionResource]dynamic.NamespaceableResourceInterface
+}
+
+// NewFixtureClientProvider returns a ClientProvider that answers lookup calls
+// from the given objects.
+//
+// A kind is treated as namespaced if any of its objects sets
+// metadata.namespace, and as cluster-scoped otherwise. Kinds that have no
+// objects at all behave like namespaced kinds with no instances.
+func NewFixtureClientProvider(objs ...*unstructured.Unstructured) ClientProvider {
+	p := &fixtureClientProvider{
+		namespaced: make(map[schema.GroupVersionKind]bool),
+		clients:    make(map[schema.GroupVersionResource]dynamic.NamespaceableResourceInterface),
+	}
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		p.namespaced[gvk] = p.namespaced[gvk] || obj.GetNamespace() != ""
+		p.objects = append(p.objects, obj.DeepCopy())
+	}
+	return p
+}
+
+func (p *fixtureClientProvider) GetClientFor(apiVersion, kind string) (dynamic.NamespaceableResourceInterface, bool, error) {
+	gvk := schema.FromAPIVersionAndKind(apiVersion, kind)
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+
+	namespaced, ok := p.namespaced[gvk]
+	if !ok {
+		namespaced = true
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	client, ok := p.clients[gvr]
+	if !ok {
+		// The fake client can only list resources it knows the list kind of,
+		// so register the requested one. This also lets lookups of kinds
+		// without fixtures return an empty list instead of failing.
+		listKinds := map[schema.GroupVersionResource]string{gvr: kind + "List"}
+		client = fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, p.objects...).Resource(gvr)
+		p.clients[gvr] = client
+	}
+	return client, namespaced, nil
+}
+
+// LoadLookupFixtures reads Kubernetes objects from the given YAML or JSON files
+// and returns a ClientProvider serving them to the lookup function.
+//
+// Each file may contain multiple YAML documents. Objects of kind "List" are
+// expanded into their items.
+func LoadLookupFixtures(paths ...string) (ClientProvider, error) {
+	var objs []*unstructured.Unstructured
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read lookup fixtures: %w", err)
+		}
+		fileObjs, err := parseLookupFixtures(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse lookup fixtures %s: %w", path, err)
+		}
+		objs = append(objs, fileObjs...)
+	}
+	return NewFixtureClientProvider(objs...), nil
+}
+
+func parseLookupFixtures(data []byte) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		var doc map[string]interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return nil, err
+		}
+		// Skip empty documents, e.g. a leading '---'.
+		if len(doc) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{Object: doc}
+		if obj.IsList() {
+			err := obj.EachListItem(func(item runtime.Object) error {
+				u, ok := item.(*unstructured.Unstructured)
+				if !ok {
+					return fmt.Errorf("unexpected list item of type %T", item)
+				}
+				return appendFixture(&objs, u)
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := appendFixture(&objs, obj); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func appendFixture(objs *[]*unstructured.Unstructured, obj *unstructured.Unstructured) error {
+	if obj.GetAPIVersion() == "" || obj.GetKind() == "" {
+		return errors.New("object is missing apiVersion or kind")
+	}
+	if obj.GetName() == "" {
+		return fmt.Errorf("%s object is missing metadata.name", obj.GetKind())
+	}
+	*objs = append(*objs, obj)
+	return nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/cli"
+	"helm.sh/helm/v4/pkg/plugin"
+)
+
+// GoTemplateRenderer is the name of the default renderer, which renders
+// templates with Go's text/template.
+const GoTemplateRenderer = "gotpl"
+
+// RendererAnnotation is the Chart.yaml annotation that selects the renderer
+// for the templates of a chart.
+//
+// Templates whose file extension is handled by a renderer are always rendered
+// by that renderer. Partials (files starting with "_") and NOTES.txt are
+// always rendered as Go templates.
+const RendererAnnotation = "helm.sh/renderer"
+
+// Renderer renders templates that are not written as Go templates.
+type Renderer interface {
+	// Render renders the template with the given name and contents, and
+	// returns the manifests.
+	//
+	// context is a JSON object holding the Values, Release, Capabilities,
+	// Chart and Template objects that a Go template would be rendered with.
+	Render(name string, template []byte, context []byte) (string, error)
+}
+
+// RendererProvider makes a Renderer available to the engine.
+type RendererProvider struct {
+	// Name selects the renderer through the helm.sh/renderer annotation.
+	Name string
+	// Extensions are the file extensions, including the leading dot, of the
+	// templates the renderer handles.
+	Extensions []string
+	Renderer   Renderer
+}
+
+// RendererProviders is a collection of RendererProvider objects.
+type RendererProviders []RendererProvider
+
+// ByName returns the renderer with the given name.
+func (p RendererProviders) ByName(name string) (Renderer, error) {
+	for _, rp := range p {
+		if rp.Name == name {
+			return rp.Renderer, nil
+		}
+	}
+	return nil, fmt.Errorf("renderer %q is not available", name)
+}
+
+// ByExtension returns the renderer that handles templates with the given file
+// extension.
+func (p RendererProviders) ByExtension(ext string) (Renderer, bool) {
+	if ext == "" {
+		return nil, false
+	}
+	for _, rp := range p {
+		if slices.Contains(rp.Extensions, ext) {
+			return rp.Renderer, true
+		}
+	}
+	return nil, false
+}
+
+// rendererFor returns the renderer for a template, or nil if the template is
+// a Go template.
+func (e Engine) rendererFor(filename string, r renderable) (Renderer, error) {
+	base := path.Base(filename)
+	if strings.HasPrefix(base, "_") || base == "NOTES.txt" {
+		return nil, nil
+	}
+	if rr, ok := e.Renderers.ByExtension(path.Ext(filename)); ok {
+		return rr, nil
+	}
+	if r.renderer == "" || r.renderer == GoTemplateRenderer {
+		return nil, nil
+	}
+	rr, err := e.Renderers.ByName(r.renderer)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return rr, nil
+}
+
+// renderContext encodes the objects that a template is rendered with.
+func renderContext(vals chartutil.Values) ([]byte, error) {
+	ctx := map[string]interface{}{}
+	for _, k := range []string{"Values", "Release", "Capabilities", "Chart", "Template"} {
+		ctx[k] = vals[k]
+	}
+	return json.Marshal(ctx)
+}
+
+type execRenderer struct {
+	command string
+	args    []string
+	// env is the environment of the command. If nil, it inherits the
+	// environment of the current process.
+	env []string
+}
+
+// NewExecRenderer returns a Renderer that runs the given command for every
+// template.
+//
+// The command receives a JSON object with the name of the template, its
+// contents and its context on stdin, and must print the manifests to stdout.
+func NewExecRenderer(command string, args ...string) Renderer {
+	return &execRenderer{command: command, args: args}
+}
+
+// execRendererInput is the JSON object written to the stdin of an exec
+// renderer.
+type execRendererInput struct {
+	Name     string          `json:"name"`
+	Template string          `json:"template"`
+	Context  json.RawMessage `json:"context"`
+}
+
+func (r *execRenderer) Render(name string, template []byte, context []byte) (string, error) {
+	input, err := json.Marshal(execRendererInput{Name: name, Template: string(template), Context: context})
+	if err != nil {
+		return "", err
+	}
+	cmd := exec.Command(r.command, r.args...)
+	cmd.Env = r.env
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("error while running renderer %s for %s. error output:\n%s: %w", r.command, name, stderr.String(), err)
+	}
+	return stdout.String(), nil
+}
+
+// CollectPluginRenderers returns the renderers provided by the installed
+// plugins.
+func CollectPluginRenderers(settings *cli.EnvSettings) (RendererProviders, error) {
+	plugins, err := plugin.FindPlugins(settings.PluginsDirectory)
+	if err != nil {
+		return nil, err
+	}
+	var result RendererProviders
+	for _, p := range plugins {
+		env := pluginEnv(settings, p.Metadata.Name, p.Dir)
+		for _, renderer := range p.Metadata.Renderers {
+			cmds := renderer.PlatformCommand
+			if len(cmds) == 0 {
+				cmds = []plugin.PlatformCommand{{Command: renderer.Command}}
+			}
+			main, args, err := plugin.PrepareCommandsWithEnv(cmds, true, nil, env)
+			if err != nil {
+				return nil, fmt.Errorf("renderer %q of plugin %q: %w", renderer.Name, p.Metadata.Name, err)
+			}
+			// Commands are relative to the plugin directory.
+			if !filepath.IsAbs(main) {
+				main = filepath.Join(p.Dir, main)
+			}
+			result = append(result, RendererProvider{
+				Name:       renderer.Name,
+				Extensions: renderer.Extensions,
+				Renderer:   &execRenderer{command: main, args: args, env: env},
+			})
+		}
+	}
+	return result, nil
+}
+
+// pluginEnv returns the environment of the current process with the
+// variables that Helm passes to plugins.
+func pluginEnv(settings *cli.EnvSettings, name, base string) []string {
+	env := os.Environ()
+	for k, v := range settings.EnvVars() {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	return append(env, "HELM_PLUGIN_NAME="+name, "HELM_PLUGIN_DIR="+base)
+}
@@ -0,0 +1,186 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/cli"
+)
+
+// upperRenderer renders a template by upper casing it and appending the
+// value of "name" from its context.
+type upperRenderer struct{}
+
+func (upperRenderer) Render(name string, tpl []byte, context []byte) (string, error) {
+	var ctx struct {
+		Values   map[string]interface{}
+		Release  map[string]interface{}
+		Template map[string]interface{}
+	}
+	if err := json.Unmarshal(context, &ctx); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %v %v %v", strings.ToUpper(string(tpl)), ctx.Values["name"], ctx.Release["Name"], ctx.Template["Name"] == name), nil
+}
+
+func TestRenderWithRenderers(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "moby", Version: "1.2.3"},
+		Templates: []*chart.File{
+			{Name: "templates/gotpl.yaml", Data: []byte("{{ .Values.name }}")},
+			{Name: "templates/upper.up", Data: []byte("upper {{ .Values.name }}")},
+			{Name: "templates/_helpers.up", Data: []byte("not rendered")},
+		},
+	}
+	vals := chartutil.Values{
+		"Values":  map[string]interface{}{"name": "Ishmael"},
+		"Release": map[string]interface{}{"Name": "pequod"},
+	}
+
+	e := Engine{Renderers: RendererProviders{{Name: "upper", Extensions: []string{".up"}, Renderer: upperRenderer{}}}}
+	out, err := e.Render(c, vals)
+	require.NoError(t, err)
+
+	expect := map[string]string{
+		"moby/templates/gotpl.yaml": "Ishmael",
+		"moby/templates/upper.up":   "UPPER {{ .VALUES.NAME }} Ishmael pequod true",
+	}
+	assert.Equal(t, expect, out)
+}
+
+func TestRenderWithChartRenderer(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name:        "moby",
+			Version:     "1.2.3",
+			Annotations: map[string]string{RendererAnnotation: "upper"},
+		},
+		Templates: []*chart.File{
+			{Name: "templates/upper.yaml", Data: []byte("upper")},
+			{Name: "templates/NOTES.txt", Data: []byte("{{ .Values.name }}")},
+			{Name: "templates/_helpers.tpl", Data: []byte(`{{ define "name" }}{{ .Values.name }}{{ end }}`)},
+		},
+	}
+	vals := chartutil.Values{
+		"Values":  map[string]interface{}{"name": "Ishmael"},
+		"Release": map[string]interface{}{"Name": "pequod"},
+	}
+
+	e := Engine{Renderers: RendererProviders{{Name: "upper", Renderer: upperRenderer{}}}}
+	out, err := e.Render(c, vals)
+	require.NoError(t, err)
+	assert.Equal(t, "UPPER Ishmael pequod true", out["moby/templates/upper.yaml"])
+	assert.Equal(t, "Ishmael", out["moby/templates/NOTES.txt"])
+
+	_, err = new(Engine).Render(c, vals)
+	assert.EqualError(t, err, `moby/templates/upper.yaml: renderer "upper" is not available`)
+}
+
+func TestRenderWithRendererOfPartials(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "moby", Version: "1.2.3"},
+		Templates: []*chart.File{
+			{Name: "templates/upper.tpl", Data: []byte(`upper`)},
+			{Name: "templates/NOTES.txt", Data: []byte(`{{ include "name" . }}`)},
+			{Name: "templates/_helpers.tpl", Data: []byte(`{{ define "name" }}{{ .Values.name }}{{ end }}`)},
+		},
+	}
+	vals := chartutil.Values{
+		"Values":  map[string]interface{}{"name": "Ishmael"},
+		"Release": map[string]interface{}{"Name": "pequod"},
+	}
+
+	// Partials and NOTES.txt are Go templates even if a renderer claims
+	// their extension.
+	e := Engine{Renderers: RendererProviders{{Name: "upper", Extensions: []string{".tpl", ".txt"}, Renderer: upperRenderer{}}}}
+	out, err := e.Render(c, vals)
+	require.NoError(t, err)
+	assert.Equal(t, "UPPER Ishmael pequod true", out["moby/templates/upper.tpl"])
+	assert.Equal(t, "Ishmael", out["moby/templates/NOTES.txt"])
+}
+
+func TestRenderWithGoTemplateRenderer(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name:        "moby",
+			Version:     "1.2.3",
+			Annotations: map[string]string{RendererAnnotation: GoTemplateRenderer},
+		},
+		Templates: []*chart.File{
+			{Name: "templates/gotpl.yaml", Data: []byte("{{ .Values.name }}")},
+		},
+	}
+	out, err := new(Engine).Render(c, chartutil.Values{"Values": map[string]interface{}{"name": "Ishmael"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Ishmael", out["moby/templates/gotpl.yaml"])
+}
+
+func TestCollectPluginRenderers(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test plugin is a shell script")
+	}
+	dir, err := filepath.Abs("testdata/plugins")
+	require.NoError(t, err)
+	settings := cli.New()
+	settings.PluginsDirectory = dir
+
+	renderers, err := CollectPluginRenderers(settings)
+	require.NoError(t, err)
+	require.Len(t, renderers, 1)
+	assert.Equal(t, "echo", renderers[0].Name)
+	assert.Equal(t, []string{".echo"}, renderers[0].Extensions)
+
+	r, ok := renderers.ByExtension(".echo")
+	require.True(t, ok)
+	out, err := r.Render("moby/templates/a.echo", []byte("data"), []byte(`{"Values":{"a":1}}`))
+	require.NoError(t, err)
+
+	lines := strings.SplitN(out, "\n", 2)
+	assert.Equal(t, "# renderer --flag renderer", lines[0])
+	// The environment of the plugin is only passed to the renderer.
+	_, ok = os.LookupEnv("HELM_PLUGIN_NAME")
+	assert.False(t, ok)
+	var input execRendererInput
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &input))
+	assert.Equal(t, "moby/templates/a.echo", input.Name)
+	assert.Equal(t, "data", input.Template)
+	assert.JSONEq(t, `{"Values":{"a":1}}`, string(input.Context))
+}
+
+func TestExecRendererError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test renderer is a shell script")
+	}
+	script := filepath.Join(t.TempDir(), "fail.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho broken >&2\nexit 1\n"), 0755))
+
+	_, err := NewExecRenderer(script).Render("moby/templates/a.echo", nil, []byte("{}"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broken")
+}
@@ -0,0 +1,3 @@
+#!/bin/sh
+echo "# $HELM_PLUGIN_NAME $*"
+cat
@@ -47,6 +47,8 @@ type options struct {
 	registryClient        *registry.Client
 	timeout               time.Duration
 	transport             *http.Transport
+	cacheFile             string
+	cacheDir              string
 }
 
 // Option allows specifying various settings configurable by the user for overriding the defaults
@@ -143,6 +145,26 @@ func WithTransport(transport *http.Transport) Option {
 	}
 }
 
+// WithCacheFile caches the content fetched by the HTTP getter in a file. The
+// file is reused when the server replies to a conditional request with its
+// ETag or Last-Modified headers that the content has not been modified.
+//
+// Since the file caches a single URL, the option only applies to the Get that
+// it is passed to.
+func WithCacheFile(file string) Option {
+	return func(opts *options) {
+		opts.cacheFile = file
+	}
+}
+
+// WithCacheDir caches the content fetched by the HTTP getter in a directory,
+// in a file for each URL, as WithCacheFile does.
+func WithCacheDir(dir string) Option {
+	return func(opts *options) {
+		opts.cacheDir = dir
+	}
+}
+
 // Getter is an interface to support GET to the specified URL.
 type Getter interface {
 	// Get file content by url string
@@ -209,6 +231,10 @@ func Getters(extraOpts ...Option) Providers {
 				return NewOCIGetter(options...)
 			},
 		},
+		Provider{
+			Schemes: []string{registry.OCILayoutScheme},
+			New:     NewOCILayoutGetter,
+		},
 	}
 }
 
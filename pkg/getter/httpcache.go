@@ -0,0 +1,194 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package getter
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v4/internal/fileutil"
+)
+
+// httpCacheHeaders are the validators of a file cached by the HTTP getter,
+// which are sent back in conditional requests for the file.
+type httpCacheHeaders struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// CacheMaxAge is how long a file cached in a cache directory by the HTTP
+// getter is kept without being used.
+const CacheMaxAge = 30 * 24 * time.Hour
+
+// CacheHeadersFile returns the path of the file that holds the ETag and
+// Last-Modified headers of a file cached by the HTTP getter.
+func CacheHeadersFile(cacheFile string) string {
+	return cacheFile + ".http.json"
+}
+
+// cacheFile returns the file that caches the content of a URL, if any.
+func (g *HTTPGetter) cacheFile(href string) string {
+	if g.opts.cacheFile != "" {
+		return g.opts.cacheFile
+	}
+	if g.opts.cacheDir != "" {
+		sum := sha256.Sum256([]byte(href))
+		return filepath.Join(g.opts.cacheDir, hex.EncodeToString(sum[:]))
+	}
+	return ""
+}
+
+// loadCacheHeaders returns the validators of the cached content of a URL. It
+// returns nil if the content of the URL is not cached.
+func loadCacheHeaders(cacheFile, href string) *httpCacheHeaders {
+	if _, err := os.Stat(cacheFile); err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(CacheHeadersFile(cacheFile))
+	if err != nil {
+		return nil
+	}
+	h := &httpCacheHeaders{}
+	if err := json.Unmarshal(data, h); err != nil || h.URL != href {
+		return nil
+	}
+	return h
+}
+
+// storeCache caches the content of a URL with the validators of the
+// response. Content without validators is not cached, since it could never
+// be reused.
+func storeCache(cacheFile, href string, header http.Header, data []byte) error {
+	h := &httpCacheHeaders{
+		URL:          href,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+	}
+	// A Last-Modified date less than a second before the response is weak, as
+	// the content could be modified again within the same second.
+	if h.LastModified != "" && !strongLastModified(header) {
+		h.LastModified = ""
+	}
+	if h.ETag == "" && h.LastModified == "" {
+		if err := os.Remove(CacheHeadersFile(cacheFile)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	headers, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0755); err != nil {
+		return err
+	}
+	// The content is written first, so that the validators never describe
+	// other content.
+	if err := fileutil.AtomicWriteFile(cacheFile, bytes.NewReader(data), 0644); err != nil {
+		return err
+	}
+	return fileutil.AtomicWriteFile(CacheHeadersFile(cacheFile), bytes.NewReader(headers), 0644)
+}
+
+// strongLastModified returns whether the Last-Modified date of a response is
+// at least a second before its Date, as described in RFC 7232, section 2.2.2.
+func strongLastModified(header http.Header) bool {
+	modified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		date = time.Now()
+	}
+	return date.Sub(modified) >= time.Second
+}
+
+// touchCache records that the cached content of a URL has been used, so that
+// it is not pruned.
+func touchCache(cacheFile string) {
+	now := time.Now()
+	if err := os.Chtimes(CacheHeadersFile(cacheFile), now, now); err != nil {
+		slog.Debug("failed to record the use of cached content", "file", cacheFile, slog.Any("error", err))
+	}
+}
+
+// pruneCacheDir removes the files cached in a cache directory that have not
+// been used for maxAge.
+func pruneCacheDir(dir string, maxAge time.Duration) error {
+	return removeCacheFiles(dir, func(cacheFile string, _ *httpCacheHeaders) bool {
+		used := cacheFile
+		if _, err := os.Stat(CacheHeadersFile(cacheFile)); err == nil {
+			used = CacheHeadersFile(cacheFile)
+		}
+		fi, err := os.Stat(used)
+		return err == nil && time.Since(fi.ModTime()) > maxAge
+	})
+}
+
+// RemoveCachedURLs removes the files cached in a cache directory by the HTTP
+// getter for the URLs below a base URL, e.g. the URL of a chart repository.
+func RemoveCachedURLs(dir, baseURL string) error {
+	prefix := strings.TrimSuffix(baseURL, "/") + "/"
+	return removeCacheFiles(dir, func(_ string, h *httpCacheHeaders) bool {
+		return h != nil && strings.HasPrefix(h.URL, prefix)
+	})
+}
+
+// removeCacheFiles removes the files cached in a cache directory for which
+// remove returns true. The validators of a file are nil if it has none.
+func removeCacheFiles(dir string, remove func(cacheFile string, h *httpCacheHeaders) bool) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || strings.HasSuffix(e.Name(), ".http.json") {
+			continue
+		}
+		cacheFile := filepath.Join(dir, e.Name())
+		var h *httpCacheHeaders
+		if data, err := os.ReadFile(CacheHeadersFile(cacheFile)); err == nil {
+			h = &httpCacheHeaders{}
+			if err := json.Unmarshal(data, h); err != nil {
+				h = nil
+			}
+		}
+		if !remove(cacheFile, h) {
+			continue
+		}
+		for _, f := range []string{CacheHeadersFile(cacheFile), cacheFile} {
+			if err := os.Remove(f); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	return nil
+}
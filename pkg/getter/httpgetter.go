@@ -20,8 +20,10 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"os"
 	"sync"
 
 	"helm.sh/helm/v4/internal/tlsutil"
@@ -40,6 +42,8 @@ func (g *HTTPGetter) Get(href string, options ...Option) (*bytes.Buffer, error)
 	for _, opt := range options {
 		opt(&g.opts)
 	}
+	// The cache file only applies to the URL it was given for.
+	defer func() { g.opts.cacheFile = "" }()
 	return g.get(href)
 }
 
@@ -80,6 +84,20 @@ func (g *HTTPGetter) get(href string) (*bytes.Buffer, error) {
 		}
 	}
 
+	cacheFile := g.cacheFile(href)
+	var cached *httpCacheHeaders
+	if cacheFile != "" {
+		cached = loadCacheHeaders(cacheFile, href)
+	}
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
 	client, err := g.httpClient()
 	if err != nil {
 		return nil, err
@@ -90,13 +108,37 @@ func (g *HTTPGetter) get(href string) (*bytes.Buffer, error) {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		slog.Debug("using cached content", "url", href, "file", cacheFile)
+		data, err := os.ReadFile(cacheFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the cached content of %s: %w", href, err)
+		}
+		touchCache(cacheFile)
+		return bytes.NewBuffer(data), nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("failed to fetch %s : %s", href, resp.Status)
 	}
 
 	buf := bytes.NewBuffer(nil)
-	_, err = io.Copy(buf, resp.Body)
-	return buf, err
+	if _, err = io.Copy(buf, resp.Body); err != nil {
+		return buf, err
+	}
+	if cacheFile != "" {
+		// The content is still returned when it cannot be cached.
+		if err := storeCache(cacheFile, href, resp.Header, buf.Bytes()); err != nil {
+			slog.Warn("failed to cache content", "url", href, "file", cacheFile, slog.Any("error", err))
+		}
+	}
+	if g.opts.cacheFile == "" && g.opts.cacheDir != "" {
+		// Files that are no longer used are removed from the cache directory
+		// as new ones are added, so that it does not grow without bound.
+		if err := pruneCacheDir(g.opts.cacheDir, CacheMaxAge); err != nil {
+			slog.Warn("failed to prune the cache", "dir", g.opts.cacheDir, slog.Any("error", err))
+		}
+	}
+	return buf, nil
 }
 
 // NewHTTPGetter constructs a valid http/https client as a Getter
@@ -678,3 +678,114 @@ func TestHTTPTransportOption(t *testing.T) {
 		t.Fatal("transport.TLSClientConfig should not be set")
 	}
 }
+
+func TestHTTPGetterCache(t *testing.T) {
+	const etag = `"v1"`
+	content := "content v1"
+	var requests, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag && content == "content v1" {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if content == "content v1" {
+			w.Header().Set("ETag", etag)
+		}
+		fmt.Fprint(w, content)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	g := &HTTPGetter{}
+	g.opts.url = srv.URL
+	g.opts.cacheDir = dir
+
+	get := func(expected string) {
+		t.Helper()
+		got, err := g.Get(srv.URL + "/values.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != expected {
+			t.Fatalf("expected %q, got %q", expected, got.String())
+		}
+	}
+
+	get("content v1")
+	get("content v1")
+	if requests != 2 || notModified != 1 {
+		t.Fatalf("expected 2 requests with 1 not modified, got %d with %d", requests, notModified)
+	}
+
+	// Modified content without validators replaces the cached content, and
+	// is no longer reused.
+	content = "content v2"
+	get("content v2")
+	get("content v2")
+	if notModified != 1 {
+		t.Fatalf("expected the modified content not to be reused, got %d not modified", notModified)
+	}
+
+	// A cache file only applies to the Get it is passed to.
+	content = "content v1"
+	file := filepath.Join(t.TempDir(), "index.yaml")
+	if _, err := g.Get(srv.URL+"/index.yaml", WithCacheFile(file)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(CacheHeadersFile(file)); err != nil {
+		t.Fatalf("expected the headers to be cached: %s", err)
+	}
+	if g.opts.cacheFile != "" {
+		t.Fatalf("expected the cache file to be reset, got %s", g.opts.cacheFile)
+	}
+	if g.cacheFile(srv.URL+"/other.yaml") == file {
+		t.Fatal("expected another URL not to use the cache file")
+	}
+}
+
+func TestHTTPGetterCachePruning(t *testing.T) {
+	dir := t.TempDir()
+	header := http.Header{"Etag": []string{`"v1"`}}
+	for _, href := range []string{"https://example.com/charts/a.tgz", "https://example.com/charts2/b.tgz", "https://other.example.com/c.tgz"} {
+		g := &HTTPGetter{}
+		g.opts.cacheDir = dir
+		if err := storeCache(g.cacheFile(href), href, header, []byte(href)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cached := func() int {
+		t.Helper()
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(entries) / 2
+	}
+
+	// The files of a repository are removed with it.
+	if err := RemoveCachedURLs(dir, "https://example.com/charts"); err != nil {
+		t.Fatal(err)
+	}
+	if n := cached(); n != 2 {
+		t.Fatalf("expected 2 cached files, got %d", n)
+	}
+
+	// Files that have not been used for the maximum age are pruned.
+	g := &HTTPGetter{}
+	g.opts.cacheDir = dir
+	old := time.Now().Add(-2 * CacheMaxAge)
+	if err := os.Chtimes(CacheHeadersFile(g.cacheFile("https://other.example.com/c.tgz")), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := pruneCacheDir(dir, CacheMaxAge); err != nil {
+		t.Fatal(err)
+	}
+	if n := cached(); n != 1 {
+		t.Fatalf("expected 1 cached file, got %d", n)
+	}
+	if h := loadCacheHeaders(g.cacheFile("https://example.com/charts2/b.tgz"), "https://example.com/charts2/b.tgz"); h == nil {
+		t.Error("expected the recently used file to be kept")
+	}
+}
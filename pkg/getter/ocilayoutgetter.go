@@ -0,0 +1,84 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package getter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+// OCILayoutGetter is the backend handler for charts stored in an OCI image
+// layout on disk, such as a bundle created by 'helm bundle create'.
+//
+// It handles references of the form
+//
+//	oci-layout://path/to/bundle.tar
+//	oci-layout://path/to/bundle.tar#mychart:1.0.0
+//
+// where the path is either an OCI image layout tarball or directory. Without
+// a tag, the chart the bundle was created for is returned.
+type OCILayoutGetter struct {
+	opts options
+}
+
+// Get performs a Get from repo.Getter and returns the body.
+func (g *OCILayoutGetter) Get(href string, options ...Option) (*bytes.Buffer, error) {
+	// The options of a call only apply to that call.
+	opts := g.opts
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return g.get(href, opts)
+}
+
+func (g *OCILayoutGetter) get(href string, _ options) (*bytes.Buffer, error) {
+	ref := strings.TrimPrefix(href, fmt.Sprintf("%s://", registry.OCILayoutScheme))
+	requestingProv := strings.HasSuffix(ref, ".prov")
+	ref = strings.TrimSuffix(ref, ".prov")
+	layoutPath, tag, _ := strings.Cut(ref, "#")
+
+	b, err := registry.LoadBundle(layoutPath)
+	if err != nil {
+		return nil, err
+	}
+	c, err := b.Chart(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if requestingProv {
+		if c.Prov == nil {
+			return nil, fmt.Errorf("%s: no provenance file in the OCI image layout", href)
+		}
+		return bytes.NewBuffer(c.Prov), nil
+	}
+	return bytes.NewBuffer(c.Data), nil
+}
+
+// NewOCILayoutGetter constructs a valid oci-layout getter
+func NewOCILayoutGetter(options ...Option) (Getter, error) {
+	var client OCILayoutGetter
+
+	for _, opt := range options {
+		opt(&client.opts)
+	}
+
+	return &client, nil
+}
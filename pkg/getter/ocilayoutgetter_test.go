@@ -0,0 +1,85 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package getter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/registry"
+)
+
+func TestOCILayoutGetter(t *testing.T) {
+	signed, err := os.ReadFile("../downloader/testdata/signtest-0.1.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prov, err := os.ReadFile("../downloader/testdata/signtest-0.1.0.tgz.prov")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dep, err := os.ReadFile("../downloader/testdata/local-subchart-0.1.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bundle bytes.Buffer
+	if _, err := registry.WriteBundle(&bundle, []registry.BundleChart{{Data: signed, Prov: prov}, {Data: dep}}, nil, "2020-05-04T03:02:01Z"); err != nil {
+		t.Fatal(err)
+	}
+	bundlePath := filepath.Join(t.TempDir(), "signtest-0.1.0.bundle.tar")
+	if err := os.WriteFile(bundlePath, bundle.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := Getters().ByScheme(registry.OCILayoutScheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	href := "oci-layout://" + bundlePath
+
+	tests := []struct {
+		href   string
+		expect []byte
+		err    bool
+	}{
+		{href: href, expect: signed},
+		{href: href + ".prov", expect: prov},
+		{href: href + "#local-subchart:0.1.0", expect: dep},
+		{href: href + "#local-subchart:0.1.0.prov", err: true},
+		{href: href + "#missing:0.1.0", err: true},
+		{href: "oci-layout://" + filepath.Join(t.TempDir(), "missing.tar"), err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.href, func(t *testing.T) {
+			got, err := g.Get(tt.href)
+			if tt.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got.Bytes(), tt.expect) {
+				t.Errorf("unexpected content for %s", tt.href)
+			}
+		})
+	}
+}
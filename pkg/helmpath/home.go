@@ -34,6 +34,15 @@ func CacheIndexFile(name string) string {
 	return name + "index.yaml"
 }
 
+// CacheShardedIndexDir returns the path to the directory of the sharded index
+// for the given named repository.
+func CacheShardedIndexDir(name string) string {
+	if name != "" {
+		name += "-"
+	}
+	return name + "index.d"
+}
+
 // CacheChartsFile returns the path to a text file listing all the charts
 // within the given named repository.
 func CacheChartsFile(name string) string {
@@ -42,3 +51,11 @@ func CacheChartsFile(name string) string {
 	}
 	return name + "charts.txt"
 }
+
+// CacheHTTPDir returns the path to the directory of the files that are cached
+// by the HTTP getter, such as chart archives and values files. It is relative
+// to the repository cache. Files that have not been used for a while are
+// pruned, and the files of a repository are removed with it.
+func CacheHTTPDir() string {
+	return "http"
+}
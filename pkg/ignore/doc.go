@@ -19,24 +19,36 @@ Package ignore provides tools for writing ignore files (a la .gitignore).
 
 This provides both an ignore parser and a file-aware processor.
 
-The format of ignore files closely follows, but does not exactly match, the
-format for .gitignore files (https://git-scm.com/docs/gitignore).
+The format of ignore files follows the format for .gitignore files
+(https://git-scm.com/docs/gitignore).
 
 The formatting rules are as follows:
 
   - Parsing is line-by-line
   - Empty lines are ignored
   - Lines that begin with # (comments) will be ignored
-  - Leading and trailing spaces are always ignored
+  - Leading spaces are ignored, and so are trailing spaces unless they are
+    escaped with a backslash
   - Inline comments are NOT supported ('foo* # Any foo' does not contain a comment)
   - There is no support for multi-line patterns
-  - Shell glob patterns are supported. See Go's "path/filepath".Match
-  - If a pattern begins with a leading !, the match will be negated.
-  - If a pattern begins with a leading /, only paths relatively rooted will match.
+  - '*' matches anything but a slash, '?' matches any character but a slash and
+    '[a-z]' and '[!a-z]' match a character in or not in a range
+  - A backslash escapes the character after it, e.g. '\#' or '\!' at the start
+    of a pattern
+  - If a pattern begins with a leading !, the paths it matches are re-included.
+    The last pattern that matches a path decides whether it is ignored, but a
+    path in an ignored directory cannot be re-included.
+  - If a pattern begins with or contains a /, only paths relatively rooted will match.
   - If the pattern ends with a trailing /, only directories will match
   - If a pattern contains no slashes, file basenames are tested (not paths)
-  - The pattern sequence "**", while legal in a glob, will cause an error here
-    (to indicate incompatibility with .gitignore).
+  - A pattern that matches a directory matches everything in it
+  - The pattern sequence "**" matches any number of directories when it is
+    a whole path segment, at the start, in the middle or at the end of a
+    pattern, e.g. 'foo/**' matches everything in foo
+
+Ignore files in subdirectories are added with Rules.AddFile. Their patterns
+match paths relative to their directory, and take precedence over the patterns
+of the ignore files above them.
 
 Example:
 
@@ -58,11 +70,11 @@ Example:
 	# Match any file named ab.txt, ac.txt, or ad.txt
 	a[b-d].txt
 
-Notable differences from .gitignore:
-  - The '**' syntax is not supported.
-  - The globbing library is Go's 'filepath.Match', not fnmatch(3)
-  - Trailing spaces are always ignored (there is no supported escape sequence)
-  - The evaluation of escape sequences has not been tested for compatibility
-  - There is no support for '\!' as a special leading sequence.
+	# Match everything in the top-level directory named build
+	/build/**
+
+	# Match any text file but notes.txt
+	*.txt
+	!notes.txt
 */
 package ignore // import "helm.sh/helm/v4/pkg/ignore"
@@ -20,10 +20,12 @@ import (
 	"bufio"
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -63,7 +65,24 @@ func ParseFile(file string) (*Rules, error) {
 // Parse parses a rules file
 func Parse(file io.Reader) (*Rules, error) {
 	r := &Rules{patterns: []*pattern{}}
+	return r, r.parse(file, "")
+}
+
+// AddFile parses the ignore file at path and adds its rules for dir, a
+// directory relative to the root of the rules, like the rules of a .gitignore
+// file in a subdirectory. The rules match the paths in dir relative to it, and
+// take precedence over the rules added before.
+func (r *Rules) AddFile(dir, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return r.parse(f, strings.Trim(filepath.ToSlash(dir), "/"))
+}
 
+// parse parses the rules in file for the directory base.
+func (r *Rules) parse(file io.Reader, base string) error {
 	s := bufio.NewScanner(file)
 	currentLine := 0
 	utf8bom := []byte{0xEF, 0xBB, 0xBF}
@@ -76,17 +95,22 @@ func Parse(file io.Reader) (*Rules, error) {
 		line := string(scannedBytes)
 		currentLine++
 
+		n := len(r.patterns)
 		if err := r.parseRule(line); err != nil {
-			return r, err
+			return err
+		}
+		if len(r.patterns) > n {
+			r.patterns[n].base = base
 		}
 	}
-	return r, s.Err()
+	return s.Err()
 }
 
 // Ignore evaluates the file at the given path, and returns true if it should be ignored.
 //
-// Ignore evaluates path against the rules in order. Evaluation stops when a match
-// is found. Matching a negative rule will stop evaluation.
+// Like in .gitignore files, the last rule that matches a path decides whether
+// it is ignored: a negative rule that matches re-includes a path that a rule
+// before it ignored. A path in an ignored directory is always ignored.
 func (r *Rules) Ignore(path string, fi os.FileInfo) bool {
 	// Don't match on empty dirs.
 	if path == "" {
@@ -99,39 +123,62 @@ func (r *Rules) Ignore(path string, fi os.FileInfo) bool {
 	if path == "." || path == "./" {
 		return false
 	}
+	path = strings.TrimSuffix(strings.TrimPrefix(path, "./"), "/")
+
+	// Files cannot be re-included when one of their parent directories is
+	// ignored.
+	for i := strings.Index(path, "/"); i >= 0; i = nextSlash(path, i) {
+		if r.ignore(path[:i], true) {
+			return true
+		}
+	}
+	return r.ignore(path, fi.IsDir())
+}
+
+// nextSlash returns the index of the slash after the one at i in path, or -1.
+func nextSlash(path string, i int) int {
+	j := strings.Index(path[i+1:], "/")
+	if j < 0 {
+		return -1
+	}
+	return i + 1 + j
+}
+
+// ignore returns whether the rules ignore path, not considering its parent
+// directories.
+func (r *Rules) ignore(path string, isDir bool) bool {
+	ignored := false
 	for _, p := range r.patterns {
 		if p.match == nil {
 			slog.Info("this will be ignored no matcher supplied", "patterns", p.raw)
-			return false
-		}
-
-		// For negative rules, we need to capture and return non-matches,
-		// and continue for matches.
-		if p.negate {
-			if p.mustDir && !fi.IsDir() {
-				return true
-			}
-			if !p.match(path, fi) {
-				return true
-			}
 			continue
 		}
 
 		// If the rule is looking for directories, and this is not a directory,
 		// skip it.
-		if p.mustDir && !fi.IsDir() {
+		if p.mustDir && !isDir {
 			continue
 		}
-		if p.match(path, fi) {
-			return true
+
+		// Rules of ignore files in subdirectories match the paths in them,
+		// relative to them.
+		n := path
+		if p.base != "" {
+			var ok bool
+			if n, ok = strings.CutPrefix(path, p.base+"/"); !ok {
+				continue
+			}
+		}
+		if p.match(n, nil) {
+			ignored = !p.negate
 		}
 	}
-	return false
+	return ignored
 }
 
 // parseRule parses a rule string and creates a pattern, which is then stored in the Rules object.
 func (r *Rules) parseRule(rule string) error {
-	rule = strings.TrimSpace(rule)
+	rule = trimRule(rule)
 
 	// Ignore blank lines
 	if rule == "" {
@@ -142,17 +189,6 @@ func (r *Rules) parseRule(rule string) error {
 		return nil
 	}
 
-	// Fail any rules that contain **
-	if strings.Contains(rule, "**") {
-		return errors.New("double-star (**) syntax is not supported")
-	}
-
-	// Fail any patterns that can't compile. A non-empty string must be
-	// given to Match() to avoid optimization that skips rule evaluation.
-	if _, err := filepath.Match(rule, "abc"); err != nil {
-		return err
-	}
-
 	p := &pattern{raw: rule}
 
 	// Negation is handled at a higher level, so strip the leading ! from the
@@ -170,43 +206,126 @@ func (r *Rules) parseRule(rule string) error {
 		rule = strings.TrimSuffix(rule, "/")
 	}
 
-	if after, ok := strings.CutPrefix(rule, "/"); ok {
-		// Require path matches the root path.
-		p.match = func(n string, _ os.FileInfo) bool {
-			rule = after
-			ok, err := filepath.Match(rule, n)
-			if err != nil {
-				slog.Error("failed to compile", "rule", rule, slog.Any("error", err))
-				return false
-			}
-			return ok
+	// A rule with a slash at its beginning or in its middle matches paths
+	// relative to the root. Other rules match a name at any level.
+	anchored := strings.Contains(rule, "/")
+	rule = strings.TrimPrefix(rule, "/")
+
+	expr, err := globToRegexp(rule)
+	if err != nil {
+		return fmt.Errorf("invalid rule %q: %w", p.raw, err)
+	}
+	if !anchored {
+		expr = "(?:.*/)?" + expr
+	}
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return fmt.Errorf("invalid rule %q: %w", p.raw, err)
+	}
+	p.match = func(n string, _ os.FileInfo) bool {
+		return re.MatchString(n)
+	}
+
+	r.patterns = append(r.patterns, p)
+	return nil
+}
+
+// trimRule removes the leading spaces and the trailing spaces of a rule,
+// except for trailing spaces that are escaped with a backslash.
+func trimRule(rule string) string {
+	rule = strings.TrimLeft(rule, " \t")
+	for strings.HasSuffix(rule, " ") || strings.HasSuffix(rule, "\t") {
+		trimmed := rule[:len(rule)-1]
+		if strings.HasSuffix(trimmed, `\`) && !strings.HasSuffix(trimmed, `\\`) {
+			break
 		}
-	} else if strings.Contains(rule, "/") {
-		// require structural match.
-		p.match = func(n string, _ os.FileInfo) bool {
-			ok, err := filepath.Match(rule, n)
-			if err != nil {
-				slog.Error("failed to compile", "rule", rule, slog.Any("error", err))
-				return false
+		rule = trimmed
+	}
+	return rule
+}
+
+// globToRegexp translates a gitignore glob to a regular expression.
+//
+// '*' matches anything but a slash, '?' matches any character but a slash and
+// '[...]' matches a character of a range. A backslash escapes the character
+// after it. '**' matches anything, slashes included, when it is a path
+// segment of its own: '**/foo' matches foo in any directory, 'foo/**'
+// matches everything in foo and 'foo/**/bar' matches bar in foo and in any
+// directory below it. Other consecutive asterisks are single asterisks.
+func globToRegexp(glob string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '\\':
+			i++
+			if i == len(glob) {
+				return "", errors.New("trailing backslash")
 			}
-			return ok
-		}
-	} else {
-		p.match = func(n string, _ os.FileInfo) bool {
-			// When there is no slash in the pattern, we evaluate ONLY the
-			// filename.
-			n = filepath.Base(n)
-			ok, err := filepath.Match(rule, n)
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case '*':
+			j := i
+			for j < len(glob) && glob[j] == '*' {
+				j++
+			}
+			segment := (i == 0 || glob[i-1] == '/') && (j == len(glob) || glob[j] == '/')
+			switch {
+			case j-i == 2 && segment && j == len(glob):
+				b.WriteString(".*")
+			case j-i == 2 && segment:
+				// The slash after the asterisks is part of the match.
+				b.WriteString("(?:.*/)?")
+				j++
+			default:
+				b.WriteString("[^/]*")
+			}
+			i = j - 1
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			class, n, err := charClass(glob[i:])
 			if err != nil {
-				slog.Error("failed to compile", "rule", rule, slog.Any("error", err))
-				return false
+				return "", err
 			}
-			return ok
+			b.WriteString(class)
+			i += n - 1
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
 		}
 	}
+	return b.String(), nil
+}
 
-	r.patterns = append(r.patterns, p)
-	return nil
+// charClass translates the range at the start of glob to a character class of
+// a regular expression, and returns the length of the range.
+func charClass(glob string) (string, int, error) {
+	var b strings.Builder
+	b.WriteString("[")
+	i := 1
+	if i < len(glob) && (glob[i] == '!' || glob[i] == '^') {
+		// Negated ranges do not match slashes either.
+		b.WriteString("^/")
+		i++
+	}
+	for first := true; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == ']' && !first:
+			b.WriteString("]")
+			return b.String(), i + 1, nil
+		case c == '\\':
+			i++
+			if i == len(glob) {
+				return "", 0, errors.New("trailing backslash")
+			}
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case c == '-':
+			b.WriteByte(c)
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+		first = false
+	}
+	return "", 0, errors.New("unterminated range")
 }
 
 // matcher is a function capable of computing a match.
@@ -224,4 +343,7 @@ type pattern struct {
 	negate bool
 	// mustDir indicates that the matched file must be a directory.
 	mustDir bool
+	// base is the directory of the ignore file of the rule, relative to the
+	// root. It is empty for the rules of the root.
+	base string
 }
@@ -56,7 +56,7 @@ one/more
 }
 
 func TestParseFail(t *testing.T) {
-	shouldFail := []string{"foo/**/bar", "[z-"}
+	shouldFail := []string{"[z-", "foo\\", "[a-c"}
 	for _, fail := range shouldFail {
 		_, err := parseString(fail)
 		if err == nil {
@@ -114,9 +114,41 @@ func TestIgnore(t *testing.T) {
 
 		// Negation tests
 		{`!helm.txt`, "helm.txt", false},
-		{`!helm.txt`, "tiller.txt", true},
-		{`!*.txt`, "cargo", true},
-		{`!cargo/`, "mast/", true},
+		{`!helm.txt`, "tiller.txt", false},
+		{`!*.txt`, "cargo", false},
+		{`!cargo/`, "mast/", false},
+		{"*.txt\n!helm.txt", "helm.txt", false},
+		{"*.txt\n!helm.txt", "tiller.txt", true},
+		{"!helm.txt\n*.txt", "helm.txt", true},
+		{"cargo/\n!cargo/a.txt", "cargo/a.txt", true},
+		{"cargo/*\n!cargo/a.txt", "cargo/a.txt", false},
+		{"cargo/*\n!cargo/a.txt", "cargo/b.txt", true},
+
+		// Double-star tests
+		{`**/a.txt`, "a.txt", true},
+		{`**/a.txt`, "cargo/a.txt", true},
+		{`**/cargo`, "cargo", true},
+		{`cargo/**`, "cargo/a.txt", true},
+		{`cargo/**`, "cargo", false},
+		{`cargo/**/a.txt`, "cargo/a.txt", true},
+		{`cargo/**/a.txt`, "mast/a.txt", false},
+		{`**.txt`, "cargo/a.txt", true},
+		{`cargo**`, "cargo", true},
+
+		// Directory contents tests
+		{`cargo`, "cargo/a.txt", true},
+		{`cargo/`, "cargo/a.txt", true},
+		{`/cargo`, "cargo/a.txt", true},
+		{`mast/`, "cargo/a.txt", false},
+
+		// Escape tests
+		{`\#hash.txt`, "#hash.txt", true},
+		{`\!bang.txt`, "!bang.txt", true},
+		{`helm\.txt`, "helm.txt", true},
+		{`helm.txt\ `, "helm.txt", false},
+		{`helm.txt   `, "helm.txt", true},
+		{`[!a]*.txt`, "a.txt", false},
+		{`[!a]*.txt`, "helm.txt", true},
 
 		// Absolute path tests
 		{`/a.txt`, "a.txt", true},
@@ -140,6 +172,42 @@ func TestIgnore(t *testing.T) {
 	}
 }
 
+func TestAddFile(t *testing.T) {
+	r, err := parseString("*.txt\n!mast/c.txt\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ifile := filepath.Join(t.TempDir(), HelmIgnore)
+	if err := os.WriteFile(ifile, []byte("# mast\n!b.txt\n/c.txt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddFile("mast", ifile); err != nil {
+		t.Fatal(err)
+	}
+
+	// The rules of the nested file match the paths in its directory, and
+	// take precedence over the rules of the root.
+	tests := []struct {
+		name   string
+		expect bool
+	}{
+		{"mast/a.txt", true},
+		{"mast/b.txt", false},
+		{"mast/c.txt", true},
+		{"cargo/b.txt", true},
+		{"helm.txt", true},
+	}
+	for _, test := range tests {
+		fi, err := os.Stat(filepath.Join(testdata, test.name))
+		if err != nil {
+			t.Fatalf("Fixture missing: %s", err)
+		}
+		if r.Ignore(test.name, fi) != test.expect {
+			t.Errorf("Expected %q to be %v", test.name, test.expect)
+		}
+	}
+}
+
 func TestAddDefaults(t *testing.T) {
 	r := Rules{}
 	r.AddDefaults()
@@ -20,6 +20,7 @@ import (
 	"path/filepath"
 
 	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/engine"
 	"helm.sh/helm/v4/pkg/lint/rules"
 	"helm.sh/helm/v4/pkg/lint/support"
 )
@@ -27,6 +28,9 @@ import (
 type linterOptions struct {
 	KubeVersion          *chartutil.KubeVersion
 	SkipSchemaValidation bool
+	LookupClientProvider engine.ClientProvider
+	Renderers            engine.RendererProviders
+	Capabilities         *chartutil.Capabilities
 }
 
 type LinterOption func(lo *linterOptions)
@@ -43,6 +47,24 @@ func WithSkipSchemaValidation(skipSchemaValidation bool) LinterOption {
 	}
 }
 
+func WithLookupClientProvider(clientProvider engine.ClientProvider) LinterOption {
+	return func(lo *linterOptions) {
+		lo.LookupClientProvider = clientProvider
+	}
+}
+
+func WithRenderers(renderers engine.RendererProviders) LinterOption {
+	return func(lo *linterOptions) {
+		lo.Renderers = renderers
+	}
+}
+
+func WithCapabilities(capabilities *chartutil.Capabilities) LinterOption {
+	return func(lo *linterOptions) {
+		lo.Capabilities = capabilities
+	}
+}
+
 func RunAll(baseDir string, values map[string]interface{}, namespace string, options ...LinterOption) support.Linter {
 
 	chartDir, _ := filepath.Abs(baseDir)
@@ -56,9 +78,23 @@ func RunAll(baseDir string, values map[string]interface{}, namespace string, opt
 		ChartDir: chartDir,
 	}
 
+	caps := lo.Capabilities
+	if lo.KubeVersion != nil {
+		if caps == nil {
+			caps = chartutil.DefaultCapabilities
+		}
+		caps = caps.Copy()
+		caps.KubeVersion = *lo.KubeVersion
+	}
+
 	rules.Chartfile(&result)
 	rules.ValuesWithOverrides(&result, values)
-	rules.TemplatesWithSkipSchemaValidation(&result, values, namespace, lo.KubeVersion, lo.SkipSchemaValidation)
+	rules.TemplatesWithOptions(&result, values, namespace, rules.TemplatesOptions{
+		Capabilities:         caps,
+		SkipSchemaValidation: lo.SkipSchemaValidation,
+		LookupClientProvider: lo.LookupClientProvider,
+		Renderers:            lo.Renderers,
+	})
 	rules.Dependencies(&result)
 
 	return result
@@ -17,6 +17,8 @@ limitations under the License.
 package lint
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -194,6 +196,30 @@ func TestHelmCreateChart_CheckDeprecatedWarnings(t *testing.T) {
 	}
 }
 
+func TestRunAllWithCapabilities(t *testing.T) {
+	createdChart, err := chartutil.Create("withcapabilities", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The template renders invalid YAML unless the API version is available.
+	tpl := `{{ if not (.Capabilities.APIVersions.Has "example.com/v1") }}example.com/v1: [{{ end }}`
+	if err := os.WriteFile(filepath.Join(createdChart, "templates", "check.yaml"), []byte(tpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := RunAll(createdChart, values, namespace).Messages
+	if len(m) != 2 || m[1].Severity != support.ErrorSev {
+		t.Fatalf("expected the template to fail without capabilities, got %v", m)
+	}
+
+	caps := chartutil.DefaultCapabilities.Copy()
+	caps.APIVersions = append(chartutil.VersionSet{"example.com/v1"}, caps.APIVersions...)
+	m = RunAll(createdChart, values, namespace, WithCapabilities(caps), WithKubeVersion(&chartutil.KubeVersion{Version: "v1.30.0", Major: "1", Minor: "30"})).Messages
+	if len(m) != 1 {
+		t.Fatalf("expected only the icon message with capabilities, got %v", m)
+	}
+}
+
 // lint ignores import-values
 // See https://github.com/helm/helm/issues/9658
 func TestSubChartValuesChart(t *testing.T) {
@@ -51,6 +51,38 @@ func TemplatesWithKubeVersion(linter *support.Linter, values map[string]interfac
 
 // TemplatesWithSkipSchemaValidation lints the templates in the Linter, allowing to specify the kubernetes version and if schema validation is enabled or not.
 func TemplatesWithSkipSchemaValidation(linter *support.Linter, values map[string]interface{}, namespace string, kubeVersion *chartutil.KubeVersion, skipSchemaValidation bool) {
+	opts := TemplatesOptions{SkipSchemaValidation: skipSchemaValidation}
+	if kubeVersion != nil {
+		opts.Capabilities = chartutil.DefaultCapabilities.Copy()
+		opts.Capabilities.KubeVersion = *kubeVersion
+	}
+	TemplatesWithOptions(linter, values, namespace, opts)
+}
+
+// TemplatesOptions are the options of TemplatesWithOptions.
+type TemplatesOptions struct {
+	// Capabilities are the capabilities of the cluster the chart is rendered
+	// for. If nil, chartutil.DefaultCapabilities are used.
+	Capabilities *chartutil.Capabilities
+	// SkipSchemaValidation disables the validation of the values against the
+	// schema of the chart.
+	SkipSchemaValidation bool
+	// LookupClientProvider serves the lookup function, if not nil.
+	LookupClientProvider engine.ClientProvider
+	// Renderers render the templates that are not Go templates.
+	Renderers engine.RendererProviders
+}
+
+// TemplatesWithOptions lints the templates in the Linter with the given options.
+func TemplatesWithOptions(linter *support.Linter, values map[string]interface{}, namespace string, opts TemplatesOptions) {
+	caps := opts.Capabilities
+	var kubeVersion *chartutil.KubeVersion
+	if caps != nil {
+		kubeVersion = &caps.KubeVersion
+	} else {
+		caps = chartutil.DefaultCapabilities.Copy()
+	}
+
 	fpath := "templates/"
 	templatesPath := filepath.Join(linter.ChartDir, fpath)
 
@@ -75,11 +107,6 @@ func TemplatesWithSkipSchemaValidation(linter *support.Linter, values map[string
 		Namespace: namespace,
 	}
 
-	caps := chartutil.DefaultCapabilities.Copy()
-	if kubeVersion != nil {
-		caps.KubeVersion = *kubeVersion
-	}
-
 	// lint ignores import-values
 	// See https://github.com/helm/helm/issues/9658
 	if err := chartutil.ProcessDependencies(chart, values); err != nil {
@@ -91,13 +118,17 @@ func TemplatesWithSkipSchemaValidation(linter *support.Linter, values map[string
 		return
 	}
 
-	valuesToRender, err := chartutil.ToRenderValuesWithSchemaValidation(chart, cvals, options, caps, skipSchemaValidation)
+	valuesToRender, err := chartutil.ToRenderValuesWithSchemaValidation(chart, cvals, options, caps, opts.SkipSchemaValidation)
 	if err != nil {
 		linter.RunLinterRule(support.ErrorSev, fpath, err)
 		return
 	}
 	var e engine.Engine
+	if opts.LookupClientProvider != nil {
+		e = engine.NewWithClientProvider(opts.LookupClientProvider)
+	}
 	e.LintMode = true
+	e.Renderers = opts.Renderers
 	renderedContentMap, err := e.Render(chart, valuesToRender)
 
 	renderOk := linter.RunLinterRule(support.ErrorSev, fpath, err)
@@ -17,9 +17,13 @@ limitations under the License.
 package rules
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
 	"helm.sh/helm/v4/pkg/lint/support"
@@ -41,6 +45,7 @@ func ValuesWithOverrides(linter *support.Linter, valueOverrides map[string]inter
 	}
 
 	linter.RunLinterRule(support.ErrorSev, file, validateValuesFile(vf, valueOverrides))
+	linter.RunLinterRule(support.WarningSev, "values.schema.json", validateSchemaMatchesValues(vf))
 }
 
 func validateValuesFileExistence(valuesPath string) error {
@@ -76,3 +81,110 @@ func validateValuesFile(valuesPath string, overrides map[string]interface{}) err
 	}
 	return chartutil.ValidateAgainstSingleSchema(coalescedValues, schema)
 }
+
+// validateSchemaMatchesValues checks that the schema of the chart describes the
+// values in values.yaml with the same types.
+func validateSchemaMatchesValues(valuesPath string) error {
+	ext := filepath.Ext(valuesPath)
+	schemaPath := valuesPath[:len(valuesPath)-len(ext)] + ".schema.json"
+	schemaData, err := os.ReadFile(schemaPath)
+	if err != nil || len(schemaData) == 0 {
+		return nil
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(schemaData, &schema); err != nil {
+		return fmt.Errorf("unable to parse JSON: %w", err)
+	}
+
+	data, err := os.ReadFile(valuesPath)
+	if err != nil {
+		return err
+	}
+	generated, err := chartutil.GenerateSchema(data)
+	if err != nil {
+		// values.yaml errors are reported by validateValuesFile.
+		return nil
+	}
+
+	var errs []error
+	compareSchemas(generated, schema, "", &errs)
+	return errors.Join(errs...)
+}
+
+// compareSchemas reports where declared disagrees with the schema generated
+// from the values.
+func compareSchemas(generated, declared map[string]interface{}, path string, errs *[]error) {
+	if _, ok := declared["$ref"]; ok {
+		return
+	}
+	name := path
+	if name == "" {
+		name = "values"
+	}
+	if t, ok := generated["type"].(string); ok {
+		if types := schemaTypes(declared["type"]); len(types) > 0 && !typeAllowed(t, types) {
+			*errs = append(*errs, fmt.Errorf("%s is %s in values.yaml but the schema expects %s", name, withArticle(t), strings.Join(types, " or ")))
+			return
+		}
+	}
+
+	if gp, ok := generated["properties"].(map[string]interface{}); ok {
+		dp, hasProps := declared["properties"].(map[string]interface{})
+		_, hasPatterns := declared["patternProperties"]
+		keys := make([]string, 0, len(gp))
+		for k := range gp {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			key := k
+			if path != "" {
+				key = path + "." + k
+			}
+			ds, ok := dp[k].(map[string]interface{})
+			if !ok {
+				if hasProps && !hasPatterns {
+					*errs = append(*errs, fmt.Errorf("%s is set in values.yaml but not described in the schema", key))
+				}
+				continue
+			}
+			if gs, ok := gp[k].(map[string]interface{}); ok {
+				compareSchemas(gs, ds, key, errs)
+			}
+		}
+	}
+
+	gi, gok := generated["items"].(map[string]interface{})
+	di, dok := declared["items"].(map[string]interface{})
+	if gok && dok {
+		compareSchemas(gi, di, path+"[]", errs)
+	}
+}
+
+func schemaTypes(t interface{}) []string {
+	switch t := t.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var types []string
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func typeAllowed(t string, types []string) bool {
+	return slices.Contains(types, t) || (t == "integer" && slices.Contains(types, "number"))
+}
+
+func withArticle(t string) string {
+	switch t {
+	case "array", "object", "integer":
+		return "an " + t
+	}
+	return "a " + t
+}
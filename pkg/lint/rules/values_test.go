@@ -159,6 +159,60 @@ func TestValidateValuesFile(t *testing.T) {
 	}
 }
 
+func TestValidateSchemaMatchesValues(t *testing.T) {
+	tests := []struct {
+		name          string
+		yaml          string
+		errorMessages []string
+	}{
+		{
+			name: "values match schema",
+			yaml: "username: admin\npassword: swordfish",
+		},
+		{
+			name: "null values are not reported",
+			yaml: "username: admin\npassword:",
+		},
+		{
+			name:          "type mismatch",
+			yaml:          "username: 1234\npassword: swordfish",
+			errorMessages: []string{"username is an integer in values.yaml but the schema expects string"},
+		},
+		{
+			name: "undescribed values",
+			yaml: "username: admin\npassword: swordfish\nemail: admin@example.com\nport: 80",
+			errorMessages: []string{
+				"email is set in values.yaml but not described in the schema",
+				"port is set in values.yaml but not described in the schema",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpdir := ensure.TempFile(t, "values.yaml", []byte(tt.yaml))
+			createTestingSchema(t, tmpdir)
+
+			err := validateSchemaMatchesValues(filepath.Join(tmpdir, "values.yaml"))
+			if len(tt.errorMessages) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			for _, msg := range tt.errorMessages {
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+}
+
+func TestValidateSchemaMatchesValuesWithoutSchema(t *testing.T) {
+	tmpdir := ensure.TempFile(t, "values.yaml", []byte("username: 1234"))
+	if err := validateSchemaMatchesValues(filepath.Join(tmpdir, "values.yaml")); err != nil {
+		t.Errorf("expected no warnings without a schema, got %s", err)
+	}
+}
+
 func createTestingSchema(t *testing.T, dir string) string {
 	t.Helper()
 	schemafile := filepath.Join(dir, "values.schema.json")
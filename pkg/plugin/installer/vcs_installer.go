@@ -21,12 +21,12 @@ import (
 	stdfs "io/fs"
 	"log/slog"
 	"os"
-	"sort"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/Masterminds/vcs"
 
 	"helm.sh/helm/v4/internal/third_party/dep/fs"
+	"helm.sh/helm/v4/internal/vcsutil"
 	"helm.sh/helm/v4/pkg/helmpath"
 	"helm.sh/helm/v4/pkg/plugin/cache"
 )
@@ -126,25 +126,13 @@ func (i *VCSInstaller) solveVersion(repo vcs.Repo) (string, error) {
 		return "", err
 	}
 
-	// Get the tags
-	refs, err := repo.Tags()
+	ver, err := vcsutil.SolveTag(repo, constraint)
 	if err != nil {
 		return "", err
 	}
-	slog.Debug("found refs", "refs", refs)
-
-	// Convert and filter the list to semver.Version instances
-	semvers := getSemVers(refs)
-
-	// Sort semver list
-	sort.Sort(sort.Reverse(semver.Collection(semvers)))
-	for _, v := range semvers {
-		if constraint.Check(v) {
-			// If the constraint passes get the original reference
-			ver := v.Original()
-			slog.Debug("setting to version", "version", ver)
-			return ver, nil
-		}
+	if ver != "" {
+		slog.Debug("setting to version", "version", ver)
+		return ver, nil
 	}
 
 	return "", fmt.Errorf("requested version %q does not exist for plugin %q", i.Version, i.Repo.Remote())
@@ -165,15 +153,3 @@ func (i *VCSInstaller) sync(repo vcs.Repo) error {
 	slog.Debug("updating", "source", repo.Remote(), "destination", repo.LocalPath())
 	return repo.Update()
 }
-
-// Filter a list of versions to only included semantic versions. The response
-// is a mapping of the original version to the semantic version.
-func getSemVers(refs []string) []*semver.Version {
-	var sv []*semver.Version
-	for _, r := range refs {
-		if v, err := semver.NewVersion(r); err == nil {
-			sv = append(sv, v)
-		}
-	}
-	return sv
-}
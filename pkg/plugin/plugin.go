@@ -41,6 +41,22 @@ type Downloaders struct {
 	Command string `json:"command"`
 }
 
+// Renderers represents the plugins capability if it can render chart
+// templates that are not Go templates
+type Renderers struct {
+	// Name is the name that charts use to select the renderer.
+	Name string `json:"name"`
+	// Extensions are the file extensions of the templates the renderer
+	// handles, e.g. ".jsonnet".
+	Extensions []string `json:"extensions"`
+	// Command is the executable path with which the plugin renders a
+	// template
+	Command string `json:"command"`
+	// PlatformCommand is the plugin command to render a template on a
+	// specific platform. If set, it takes precedence over Command.
+	PlatformCommand []PlatformCommand `json:"platformCommand"`
+}
+
 // PlatformCommand represents a command for a particular operating system and architecture
 type PlatformCommand struct {
 	OperatingSystem string   `json:"os"`
@@ -133,6 +149,10 @@ type Metadata struct {
 	// for special protocols.
 	Downloaders []Downloaders `json:"downloaders"`
 
+	// Renderers field is used if the plugin supplies renderers for chart
+	// templates that are not Go templates.
+	Renderers []Renderers `json:"renderers"`
+
 	// UseTunnelDeprecated indicates that this command needs a tunnel.
 	// Setting this will cause a number of side effects, such as the
 	// automatic setting of HELM_HOST.
@@ -196,17 +216,34 @@ func getPlatformCommand(cmds []PlatformCommand) ([]string, []string) {
 //
 // The result is suitable to pass to exec.Command.
 func PrepareCommands(cmds []PlatformCommand, expandArgs bool, extraArgs []string) (string, []string, error) {
+	return prepareCommands(cmds, expandArgs, extraArgs, os.Getenv)
+}
+
+// PrepareCommandsWithEnv is like PrepareCommands, but expands environment
+// variables from env, a list of "key=value" strings in the form returned by
+// os.Environ, instead of the environment of the current process.
+func PrepareCommandsWithEnv(cmds []PlatformCommand, expandArgs bool, extraArgs []string, env []string) (string, []string, error) {
+	vars := make(map[string]string, len(env))
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			vars[k] = v
+		}
+	}
+	return prepareCommands(cmds, expandArgs, extraArgs, func(k string) string { return vars[k] })
+}
+
+func prepareCommands(cmds []PlatformCommand, expandArgs bool, extraArgs []string, getenv func(string) string) (string, []string, error) {
 	cmdParts, args := getPlatformCommand(cmds)
 	if len(cmdParts) == 0 || cmdParts[0] == "" {
 		return "", nil, fmt.Errorf("no plugin command is applicable")
 	}
 
-	main := os.ExpandEnv(cmdParts[0])
+	main := os.Expand(cmdParts[0], getenv)
 	baseArgs := []string{}
 	if len(cmdParts) > 1 {
 		for _, cmdPart := range cmdParts[1:] {
 			if expandArgs {
-				baseArgs = append(baseArgs, os.ExpandEnv(cmdPart))
+				baseArgs = append(baseArgs, os.Expand(cmdPart, getenv))
 			} else {
 				baseArgs = append(baseArgs, cmdPart)
 			}
@@ -215,7 +252,7 @@ func PrepareCommands(cmds []PlatformCommand, expandArgs bool, extraArgs []string
 
 	for _, arg := range args {
 		if expandArgs {
-			baseArgs = append(baseArgs, os.ExpandEnv(arg))
+			baseArgs = append(baseArgs, os.Expand(arg, getenv))
 		} else {
 			baseArgs = append(baseArgs, arg)
 		}
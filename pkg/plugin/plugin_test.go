@@ -276,6 +276,27 @@ func TestPrepareCommandsNoExpand(t *testing.T) {
 	}
 }
 
+func TestPrepareCommandsWithEnv(t *testing.T) {
+	t.Setenv("TEST", "process")
+	cmds := []PlatformCommand{
+		{OperatingSystem: "", Architecture: "", Command: "$DIR/sh", Args: []string{"-c", "echo \"${TEST}\""}},
+	}
+	env := []string{"DIR=/plugin", "TEST=env"}
+
+	expectedArgs := []string{"-c", "echo \"env\""}
+
+	cmd, args, err := PrepareCommandsWithEnv(cmds, true, []string{}, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd != "/plugin/sh" {
+		t.Fatalf("Expected %q, got %q", "/plugin/sh", cmd)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Fatalf("Expected %v, got %v", expectedArgs, args)
+	}
+}
+
 func TestLoadDir(t *testing.T) {
 	dirname := "testdata/plugdir/good/hello"
 	plug, err := LoadDir(dirname)
@@ -16,6 +16,7 @@ limitations under the License.
 package pusher
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -28,7 +29,9 @@ import (
 
 	"helm.sh/helm/v4/internal/tlsutil"
 	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	"helm.sh/helm/v4/pkg/provenance"
 	"helm.sh/helm/v4/pkg/registry"
+	"helm.sh/helm/v4/pkg/sbom"
 	"helm.sh/helm/v4/pkg/time/ctime"
 )
 
@@ -85,6 +88,16 @@ func (pusher *OCIPusher) push(chartRef, href string) error {
 		}
 		pushOpts = append(pushOpts, registry.PushOptProvData(provBytes))
 	}
+	if pusher.opts.sbom {
+		opt, err := sbomPushOpt(chartRef, chartBytes)
+		if err != nil {
+			return err
+		}
+		pushOpts = append(pushOpts, opt)
+	}
+	if len(pusher.opts.attachments) > 0 {
+		pushOpts = append(pushOpts, registry.PushOptAttachments(pusher.opts.attachments...))
+	}
 
 	ref := fmt.Sprintf("%s:%s",
 		path.Join(strings.TrimPrefix(href, fmt.Sprintf("%s://", registry.OCIScheme)), meta.Metadata.Name),
@@ -98,6 +111,27 @@ func (pusher *OCIPusher) push(chartRef, href string) error {
 	return err
 }
 
+// sbomPushOpt returns the option that attaches the SBOM stored next to a chart
+// archive, after checking that the SBOM describes the archive.
+func sbomPushOpt(chartRef string, chartBytes []byte) (registry.PushOption, error) {
+	sbomRef, format, ok := sbom.Find(chartRef)
+	if !ok {
+		return nil, fmt.Errorf("%s: no SBOM found next to the chart archive", chartRef)
+	}
+	sbomBytes, err := os.ReadFile(sbomRef)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := provenance.Digest(bytes.NewReader(chartBytes))
+	if err != nil {
+		return nil, err
+	}
+	if err := sbom.Verify(sbomBytes, format, "sha256:"+digest); err != nil {
+		return nil, fmt.Errorf("%s: %w", sbomRef, err)
+	}
+	return registry.PushOptSBOM(sbomBytes, format.MediaType()), nil
+}
+
 // NewOCIPusher constructs a valid OCI client as a Pusher
 func NewOCIPusher(ops ...Option) (Pusher, error) {
 	var client OCIPusher
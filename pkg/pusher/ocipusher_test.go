@@ -18,13 +18,17 @@ limitations under the License.
 package pusher
 
 import (
+	"bytes"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/provenance"
 	"helm.sh/helm/v4/pkg/registry"
+	"helm.sh/helm/v4/pkg/sbom"
 )
 
 func TestNewOCIPusher(t *testing.T) {
@@ -426,3 +430,40 @@ func TestOCIPusher_Push_MultipleOptions(t *testing.T) {
 		t.Error("Expected insecureSkipTLSverify option to be applied")
 	}
 }
+
+func TestSBOMPushOpt(t *testing.T) {
+	dir := t.TempDir()
+	chartRef := filepath.Join(dir, "test-0.1.0.tgz")
+	chartBytes := []byte("chart archive")
+
+	if _, err := sbomPushOpt(chartRef, chartBytes); err == nil || !strings.Contains(err.Error(), "no SBOM found") {
+		t.Errorf("expected an error without an SBOM, got %v", err)
+	}
+
+	digest, err := provenance.Digest(bytes.NewReader(chartBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeSBOM := func(digest string) {
+		t.Helper()
+		ch := &chart.Chart{Metadata: &chart.Metadata{Name: "test", Version: "0.1.0"}}
+		data, err := sbom.Generate(sbom.SPDX, sbom.Input{Chart: ch, Digest: digest})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(sbom.Filename(chartRef, sbom.SPDX), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// An SBOM of another archive is not attached.
+	writeSBOM("sha256:0123456789")
+	if _, err := sbomPushOpt(chartRef, chartBytes); err == nil || !strings.Contains(err.Error(), "not sha256:"+digest) {
+		t.Errorf("expected an error for an SBOM of another archive, got %v", err)
+	}
+
+	writeSBOM("sha256:" + digest)
+	if _, err := sbomPushOpt(chartRef, chartBytes); err != nil {
+		t.Error(err)
+	}
+}
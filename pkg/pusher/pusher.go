@@ -34,6 +34,8 @@ type options struct {
 	caFile                string
 	insecureSkipTLSverify bool
 	plainHTTP             bool
+	attachments           []*registry.Attachment
+	sbom                  bool
 }
 
 // Option allows specifying various settings configurable by the user for overriding the defaults
@@ -69,6 +71,21 @@ func WithPlainHTTP(plainHTTP bool) Option {
 	}
 }
 
+// WithAttachments sets the files that are attached to the pushed chart.
+func WithAttachments(attachments ...*registry.Attachment) Option {
+	return func(opts *options) {
+		opts.attachments = attachments
+	}
+}
+
+// WithSBOM attaches the SBOM stored next to the chart archive to the pushed
+// chart. The SBOM must describe the archive.
+func WithSBOM(sbom bool) Option {
+	return func(opts *options) {
+		opts.sbom = sbom
+	}
+}
+
 // Pusher is an interface to support upload to the specified URL.
 type Pusher interface {
 	// Push file content by url string
@@ -0,0 +1,185 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry // import "helm.sh/helm/v4/pkg/registry"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// Attachment is a file attached to a chart in a registry, such as example
+// values, documentation or a test report.
+//
+// Each attachment is an artifact of its own that refers to the manifest of the
+// chart, and is found through the referrers API of the registry, or through
+// the referrers tag schema when the registry does not support it. Its artifact
+// type is the media type of the file.
+type Attachment struct {
+	// Name is the file name of the attachment.
+	Name string `json:"name"`
+	// MediaType is the media type of the file.
+	MediaType string `json:"mediaType"`
+	// Digest is the digest of the file.
+	Digest string `json:"digest,omitempty"`
+	// Size is the size of the file.
+	Size int64 `json:"size"`
+	// Data is the content of the file. It is only set when the attachment is
+	// pushed or fetched.
+	Data []byte `json:"-"`
+}
+
+// descriptor returns the descriptor of the file of the attachment.
+func (a *Attachment) descriptor() ocispec.Descriptor {
+	return ocispec.Descriptor{
+		MediaType: a.MediaType,
+		Digest:    digest.Digest(a.Digest),
+		Size:      a.Size,
+	}
+}
+
+// PushOptAttachments returns a function that sets the files that are attached
+// to the pushed chart
+func PushOptAttachments(attachments ...*Attachment) PushOption {
+	return func(operation *pushOperation) {
+		operation.attachments = append(operation.attachments, attachments...)
+	}
+}
+
+// packAttachments packs the artifacts of the attachments of a chart, which
+// refer to the manifest of the chart, in a memory store.
+func packAttachments(ctx context.Context, store *memory.Store, subject ocispec.Descriptor, attachments []*Attachment, creationTime string) error {
+	for _, a := range attachments {
+		if a.MediaType == "" {
+			a.MediaType = DefaultAttachmentMediaType
+		}
+		desc := content.NewDescriptorFromBytes(a.MediaType, a.Data)
+		desc.Annotations = map[string]string{ocispec.AnnotationTitle: a.Name}
+		if err := store.Push(ctx, desc, bytes.NewReader(a.Data)); err != nil {
+			return err
+		}
+		annotations := map[string]string{AttachmentAnnotation: a.Name}
+		if creationTime != "" {
+			annotations[ocispec.AnnotationCreated] = creationTime
+		}
+		_, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, a.MediaType, oras.PackManifestOptions{
+			Subject:             &subject,
+			Layers:              []ocispec.Descriptor{desc},
+			ManifestAnnotations: annotations,
+		})
+		if err != nil {
+			return fmt.Errorf("could not attach %s: %w", a.Name, err)
+		}
+		a.Digest = desc.Digest.String()
+		a.Size = desc.Size
+	}
+	return nil
+}
+
+// Attachments returns the files attached to a chart in a registry, sorted by
+// name. Their content is not fetched.
+func (c *Client) Attachments(ref string) ([]*Attachment, error) {
+	parsedRef, err := newReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	var attachments []*Attachment
+	err = c.withMirrors(parsedRef, func(repository *remote.Repository, parsedRef reference) error {
+		attachments, err = attachmentsOf(ctx, repository, parsedRef)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(attachments, func(i, j int) bool {
+		return attachments[i].Name < attachments[j].Name
+	})
+	return attachments, nil
+}
+
+// attachmentsOf returns the files attached to a chart in a repository.
+func attachmentsOf(ctx context.Context, repository *remote.Repository, parsedRef reference) ([]*Attachment, error) {
+	subject, err := repository.Resolve(ctx, parsedRef.String())
+	if err != nil {
+		return nil, err
+	}
+	var referrers []ocispec.Descriptor
+	err = repository.Referrers(ctx, subject, "", func(descs []ocispec.Descriptor) error {
+		referrers = append(referrers, descs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var attachments []*Attachment
+	for _, desc := range referrers {
+		if desc.ArtifactType == SignatureArtifactType {
+			continue
+		}
+		data, err := content.FetchAll(ctx, repository, desc)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve attachment %s: %w", desc.Digest, err)
+		}
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("invalid attachment %s: %w", desc.Digest, err)
+		}
+		name, ok := manifest.Annotations[AttachmentAnnotation]
+		if !ok || len(manifest.Layers) != 1 {
+			continue
+		}
+		layer := manifest.Layers[0]
+		attachments = append(attachments, &Attachment{
+			Name:      name,
+			MediaType: layer.MediaType,
+			Digest:    layer.Digest.String(),
+			Size:      layer.Size,
+		})
+	}
+	return attachments, nil
+}
+
+// FetchAttachment fetches the content of a file attached to a chart in a
+// registry, and sets the Data of the attachment.
+func (c *Client) FetchAttachment(ref string, attachment *Attachment) error {
+	parsedRef, err := newReference(ref)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	return c.withMirrors(parsedRef, func(repository *remote.Repository, _ reference) error {
+		data, err := content.FetchAll(ctx, repository.Blobs(), attachment.descriptor())
+		if err != nil {
+			return fmt.Errorf("unable to retrieve attachment %s: %w", attachment.Name, err)
+		}
+		attachment.Data = data
+		return nil
+	})
+}
@@ -0,0 +1,399 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry // import "helm.sh/helm/v4/pkg/registry"
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// bundleMetadataTag is the tag of the metadata of a bundle in its OCI image
+// layout.
+const bundleMetadataTag = "helm-bundle"
+
+// BundleChart is a chart archive stored in a bundle.
+type BundleChart struct {
+	// Data is the chart archive.
+	Data []byte
+	// Prov is the provenance file of the chart archive, if there is one.
+	Prov []byte
+}
+
+// BundleMetadata describes the content of a bundle.
+type BundleMetadata struct {
+	// Chart is the tag of the chart the bundle was created for.
+	Chart string `json:"chart"`
+	// Charts are the tags of the charts in the bundle, the chart and its
+	// dependencies.
+	Charts []string `json:"charts"`
+	// Images are the container images referenced by the chart.
+	Images []string `json:"images,omitempty"`
+	// Created is the time the bundle was created.
+	Created string `json:"created,omitempty"`
+}
+
+// Bundle is a set of charts stored in an OCI image layout.
+//
+// Every chart is stored as a Helm chart manifest that is tagged with the name
+// and the version of the chart, e.g. "mychart:1.0.0", just as it is stored in
+// a registry. A bundle written by WriteBundle also holds metadata that names
+// the chart it was created for.
+type Bundle struct {
+	Metadata BundleMetadata
+	store    *oci.ReadOnlyStore
+}
+
+// BundleTag returns the tag of a chart in a bundle.
+func BundleTag(name, version string) string {
+	return name + ":" + version
+}
+
+// WriteBundle writes a bundle as an OCI image layout tarball.
+//
+// The first chart is the chart the bundle is created for, the others are its
+// dependencies. Charts with the same name and version are only stored once.
+func WriteBundle(w io.Writer, charts []BundleChart, images []string, creationTime string) (*BundleMetadata, error) {
+	if len(charts) == 0 {
+		return nil, errors.New("a bundle needs at least one chart")
+	}
+	dir, err := os.MkdirTemp("", "helm-bundle-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	store, err := oci.NewWithContext(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &BundleMetadata{Images: images, Created: creationTime}
+	for _, c := range charts {
+		meta, err := extractChartMeta(c.Data)
+		if err != nil {
+			return nil, err
+		}
+		tag := BundleTag(meta.Name, meta.Version)
+		if metadata.Chart == "" {
+			metadata.Chart = tag
+		}
+		if slices.Contains(metadata.Charts, tag) {
+			continue
+		}
+		metadata.Charts = append(metadata.Charts, tag)
+
+		chartDescriptor, err := oras.PushBytes(ctx, store, ChartLayerMediaType, c.Data)
+		if err != nil {
+			return nil, err
+		}
+		configData, err := json.Marshal(meta)
+		if err != nil {
+			return nil, err
+		}
+		configDescriptor, err := oras.PushBytes(ctx, store, ConfigMediaType, configData)
+		if err != nil {
+			return nil, err
+		}
+		layers := []ocispec.Descriptor{chartDescriptor}
+		if c.Prov != nil {
+			provDescriptor, err := oras.PushBytes(ctx, store, ProvLayerMediaType, c.Prov)
+			if err != nil {
+				return nil, err
+			}
+			layers = append(layers, provDescriptor)
+		}
+		sort.Slice(layers, func(i, j int) bool {
+			return layers[i].Digest < layers[j].Digest
+		})
+
+		manifestData, err := json.Marshal(ocispec.Manifest{
+			Versioned:   specs.Versioned{SchemaVersion: 2},
+			MediaType:   ocispec.MediaTypeImageManifest,
+			Config:      configDescriptor,
+			Layers:      layers,
+			Annotations: generateOCIAnnotations(meta, creationTime),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := oras.TagBytes(ctx, store, ocispec.MediaTypeImageManifest, manifestData, tag); err != nil {
+			return nil, err
+		}
+	}
+
+	metadataData, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	metadataDescriptor, err := oras.PushBytes(ctx, store, BundleMetadataMediaType, metadataData)
+	if err != nil {
+		return nil, err
+	}
+	manifestDescriptor, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, BundleArtifactType, oras.PackManifestOptions{
+		Layers:              []ocispec.Descriptor{metadataDescriptor},
+		ManifestAnnotations: map[string]string{ocispec.AnnotationCreated: creationTime},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Tag(ctx, manifestDescriptor, bundleMetadataTag); err != nil {
+		return nil, err
+	}
+
+	if err := sortIndex(dir); err != nil {
+		return nil, err
+	}
+	return metadata, tarDir(w, dir)
+}
+
+// sortIndex sorts the manifests of the index of an OCI image layout by their
+// tags. The store writes them in no particular order.
+func sortIndex(dir string) error {
+	indexPath := filepath.Join(dir, ocispec.ImageIndexFile)
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		return err
+	}
+	var index ocispec.Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return err
+	}
+	sort.SliceStable(index.Manifests, func(i, j int) bool {
+		return index.Manifests[i].Annotations[ocispec.AnnotationRefName] < index.Manifests[j].Annotations[ocispec.AnnotationRefName]
+	})
+	data, err = json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(indexPath, data, 0644)
+}
+
+// tarDir writes the OCI image layout in a directory to a tarball. The tarball
+// only depends on the names and the contents of the files.
+func tarDir(w io.Writer, dir string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() && rel == "ingest" {
+			// The store stages blobs in this directory while they are written.
+			return filepath.SkipDir
+		}
+		if d.IsDir() {
+			return tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeDir,
+				Name:     rel + "/",
+				Mode:     0755,
+				ModTime:  time.Unix(0, 0),
+			})
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     rel,
+			Mode:     0644,
+			Size:     int64(len(data)),
+			ModTime:  time.Unix(0, 0),
+		}); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// LoadBundle loads a bundle from an OCI image layout, which is either a
+// directory or a tarball.
+//
+// An OCI image layout without bundle metadata is read as a bundle of the
+// charts it holds. If it holds a single chart, that chart is the chart of the
+// bundle.
+func LoadBundle(layoutPath string) (*Bundle, error) {
+	fi, err := os.Stat(layoutPath)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	var store *oci.ReadOnlyStore
+	if fi.IsDir() {
+		store, err = oci.NewFromFS(ctx, os.DirFS(layoutPath))
+	} else {
+		store, err = oci.NewFromTar(ctx, layoutPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not load OCI image layout %s: %w", layoutPath, err)
+	}
+	b := &Bundle{store: store}
+	var tags []string
+	err = store.Tags(ctx, "", func(t []string) error {
+		tags = append(tags, t...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(tags, bundleMetadataTag) {
+		b.Metadata.Charts = tags
+		if len(b.Metadata.Charts) == 1 {
+			b.Metadata.Chart = b.Metadata.Charts[0]
+		}
+		return b, nil
+	}
+
+	manifest, err := b.fetchManifest(ctx, bundleMetadataTag)
+	if err != nil {
+		return nil, err
+	}
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != BundleMetadataMediaType {
+			continue
+		}
+		data, err := content.FetchAll(ctx, b.store, layer)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &b.Metadata); err != nil {
+			return nil, fmt.Errorf("invalid bundle metadata: %w", err)
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("bundle metadata does not contain a layer with mediatype %s", BundleMetadataMediaType)
+}
+
+func (b *Bundle) fetchManifest(ctx context.Context, tag string) (*ocispec.Manifest, error) {
+	desc, err := b.store.Resolve(ctx, tag)
+	if err != nil {
+		if errors.Is(err, errdef.ErrNotFound) {
+			return nil, fmt.Errorf("%s: not found in bundle", tag)
+		}
+		return nil, err
+	}
+	data, err := content.FetchAll(ctx, b.store, desc)
+	if err != nil {
+		return nil, err
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", tag, err)
+	}
+	return &manifest, nil
+}
+
+// Chart returns a chart of the bundle by its tag. An empty tag returns the
+// chart the bundle was created for.
+func (b *Bundle) Chart(tag string) (*BundleChart, error) {
+	if tag == "" {
+		tag = b.Metadata.Chart
+	}
+	if tag == "" {
+		return nil, fmt.Errorf("the bundle holds %d charts, a chart must be selected", len(b.Metadata.Charts))
+	}
+	ctx := context.Background()
+	manifest, err := b.fetchManifest(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+	c := &BundleChart{}
+	for _, layer := range manifest.Layers {
+		switch layer.MediaType {
+		case ChartLayerMediaType, LegacyChartLayerMediaType:
+			if c.Data, err = content.FetchAll(ctx, b.store, layer); err != nil {
+				return nil, err
+			}
+		case ProvLayerMediaType:
+			if c.Prov, err = content.FetchAll(ctx, b.store, layer); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if c.Data == nil {
+		return nil, fmt.Errorf("%s: manifest does not contain a layer with mediatype %s", tag, ChartLayerMediaType)
+	}
+	return c, nil
+}
+
+// PushBundle uploads the charts of a bundle to a registry.
+//
+// Every chart is pushed to a repository named after the chart below the
+// remote, e.g. oci://registry.example.com/charts/mychart:1.0.0. The manifests
+// are copied unchanged, so the charts keep the digests they have in the
+// bundle. It returns the references of the pushed charts.
+func (c *Client) PushBundle(b *Bundle, remoteRef string) ([]string, error) {
+	ctx := context.Background()
+	base := strings.TrimSuffix(strings.TrimPrefix(remoteRef, fmt.Sprintf("%s://", OCIScheme)), "/")
+	var refs []string
+	for _, tag := range b.Metadata.Charts {
+		name, version, ok := strings.Cut(tag, ":")
+		if !ok {
+			return refs, fmt.Errorf("invalid chart tag %q in bundle", tag)
+		}
+		parsedRef, err := newReference(fmt.Sprintf("%s:%s", path.Join(base, name), version))
+		if err != nil {
+			return refs, err
+		}
+		repository, err := remote.NewRepository(parsedRef.String())
+		if err != nil {
+			return refs, err
+		}
+		repository.PlainHTTP = c.plainHTTP
+		repository.Client = c.authorizer
+
+		desc, err := oras.Copy(ctx, b.store, tag, repository, parsedRef.Tag, oras.DefaultCopyOptions)
+		if err != nil {
+			return refs, fmt.Errorf("could not push %s: %w", tag, err)
+		}
+		fmt.Fprintf(c.out, "Pushed: %s\n", parsedRef.String())
+		fmt.Fprintf(c.out, "Digest: %s\n", desc.Digest)
+		refs = append(refs, parsedRef.String())
+	}
+	return refs, nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestBundle(t *testing.T) (string, []BundleChart) {
+	t.Helper()
+	signed, err := os.ReadFile("../downloader/testdata/signtest-0.1.0.tgz")
+	require.NoError(t, err)
+	prov, err := os.ReadFile("../downloader/testdata/signtest-0.1.0.tgz.prov")
+	require.NoError(t, err)
+	dep, err := os.ReadFile("../downloader/testdata/local-subchart-0.1.0.tgz")
+	require.NoError(t, err)
+	charts := []BundleChart{{Data: signed, Prov: prov}, {Data: dep}, {Data: dep}}
+
+	var buf bytes.Buffer
+	metadata, err := WriteBundle(&buf, charts, []string{"alpine:3.20"}, "2020-05-04T03:02:01Z")
+	require.NoError(t, err)
+	assert.Equal(t, &BundleMetadata{
+		Chart:   "signtest:0.1.0",
+		Charts:  []string{"signtest:0.1.0", "local-subchart:0.1.0"},
+		Images:  []string{"alpine:3.20"},
+		Created: "2020-05-04T03:02:01Z",
+	}, metadata)
+
+	bundlePath := filepath.Join(t.TempDir(), "signtest-0.1.0.bundle.tar")
+	require.NoError(t, os.WriteFile(bundlePath, buf.Bytes(), 0644))
+	return bundlePath, charts
+}
+
+func TestWriteBundle(t *testing.T) {
+	bundlePath, charts := writeTestBundle(t)
+
+	b, err := LoadBundle(bundlePath)
+	require.NoError(t, err)
+	assert.Equal(t, "signtest:0.1.0", b.Metadata.Chart)
+	assert.Equal(t, []string{"alpine:3.20"}, b.Metadata.Images)
+
+	root, err := b.Chart("")
+	require.NoError(t, err)
+	assert.Equal(t, charts[0], *root)
+
+	dep, err := b.Chart("local-subchart:0.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, charts[1].Data, dep.Data)
+	assert.Nil(t, dep.Prov)
+
+	_, err = b.Chart("missing:1.0.0")
+	assert.EqualError(t, err, "missing:1.0.0: not found in bundle")
+}
+
+func TestWriteBundleIsReproducible(t *testing.T) {
+	first, _ := writeTestBundle(t)
+	second, _ := writeTestBundle(t)
+	a, err := os.ReadFile(first)
+	require.NoError(t, err)
+	b, err := os.ReadFile(second)
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+}
+
+func TestLoadBundleDirectory(t *testing.T) {
+	bundlePath, charts := writeTestBundle(t)
+
+	// Extract the tarball to read the bundle as an OCI image layout directory.
+	dir := t.TempDir()
+	f, err := os.Open(bundlePath)
+	require.NoError(t, err)
+	defer f.Close()
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		target := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if hdr.Typeflag == tar.TypeDir {
+			require.NoError(t, os.MkdirAll(target, 0755))
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(target, data, 0644))
+	}
+
+	b, err := LoadBundle(dir)
+	require.NoError(t, err)
+	root, err := b.Chart("")
+	require.NoError(t, err)
+	assert.Equal(t, charts[0].Data, root.Data)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry // import "helm.sh/helm/v4/pkg/registry"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+// ErrNotChart is returned for an artifact in a registry that is not a chart.
+var ErrNotChart = errors.New("not a helm chart")
+
+// Repositories lists the repositories of a registry with the catalog API, in
+// lexical order. Only the repositories below the prefix are listed, if it is
+// not empty. Many registries do not support the catalog API, or restrict it
+// to administrators.
+func (c *Client) Repositories(host, prefix string) ([]string, error) {
+	reg, err := remote.NewRegistry(host)
+	if err != nil {
+		return nil, err
+	}
+	reg.PlainHTTP = c.plainHTTP
+	reg.Client = c.authorizer
+
+	prefix = strings.Trim(prefix, "/")
+	var repositories []string
+	err = reg.Repositories(context.Background(), "", func(repos []string) error {
+		for _, repo := range repos {
+			if prefix == "" || repo == prefix || strings.HasPrefix(repo, prefix+"/") {
+				repositories = append(repositories, repo)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list the repositories of %s: %w", host, err)
+	}
+	return repositories, nil
+}
+
+// ChartMetadata returns the metadata of a chart in a registry from the config
+// of its manifest, without downloading the chart. It returns ErrNotChart if
+// the reference is not a chart.
+func (c *Client) ChartMetadata(ref string) (*chart.Metadata, error) {
+	parsedRef, err := newReference(ref)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	tagOrDigest := parsedRef.Tag
+	if tagOrDigest == "" {
+		tagOrDigest = parsedRef.Digest
+	}
+	var configData []byte
+	err = c.withMirrors(parsedRef, func(repository *remote.Repository, _ reference) error {
+		desc, manifestData, err := oras.FetchBytes(ctx, repository, tagOrDigest, oras.DefaultFetchBytesOptions)
+		if err != nil {
+			return err
+		}
+		if desc.MediaType != ocispec.MediaTypeImageManifest {
+			return ErrNotChart
+		}
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(manifestData, &manifest); err != nil {
+			return err
+		}
+		if manifest.Config.MediaType != ConfigMediaType {
+			return ErrNotChart
+		}
+		configData, err = content.FetchAll(ctx, repository, manifest.Config)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	meta := &chart.Metadata{}
+	if err := json.Unmarshal(configData, meta); err != nil {
+		return nil, fmt.Errorf("could not read the config of %s: %w", ref, err)
+	}
+	return meta, nil
+}
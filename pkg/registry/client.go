@@ -24,6 +24,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
@@ -37,7 +38,6 @@ import (
 	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content"
 	"oras.land/oras-go/v2/content/memory"
-	"oras.land/oras-go/v2/registry"
 	"oras.land/oras-go/v2/registry/remote"
 	"oras.land/oras-go/v2/registry/remote/auth"
 	"oras.land/oras-go/v2/registry/remote/credentials"
@@ -68,7 +68,10 @@ type (
 		debug       bool
 		enableCache bool
 		// path to repository config file e.g. ~/.docker/config.json
-		credentialsFile    string
+		credentialsFile string
+		// path to registry mirrors file e.g. ~/.config/helm/registry/mirrors.yaml
+		mirrorsFile        string
+		mirrors            *MirrorsFile
 		username           string
 		password           string
 		out                io.Writer
@@ -137,6 +140,12 @@ func NewClient(options ...ClientOption) (*Client, error) {
 		client.authorizer = &authorizer
 	}
 
+	// A malformed mirrors file must not make every registry unusable, so the
+	// mirrors are ignored instead.
+	if err := client.setupMirrors(); err != nil {
+		slog.Warn("ignoring the registry mirrors", "file", client.mirrorsFile, slog.Any("error", err))
+	}
+
 	return client, nil
 }
 
@@ -196,6 +205,13 @@ func ClientOptCredentialsFile(credentialsFile string) ClientOption {
 	}
 }
 
+// ClientOptMirrorsFile returns a function that sets the mirrorsFile setting on a client options set
+func ClientOptMirrorsFile(mirrorsFile string) ClientOption {
+	return func(client *Client) {
+		client.mirrorsFile = mirrorsFile
+	}
+}
+
 // ClientOptHTTPClient returns a function that sets the httpClient setting on a client options set
 func ClientOptHTTPClient(httpClient *http.Client) ClientOption {
 	return func(client *Client) {
@@ -433,32 +449,30 @@ func (c *Client) Pull(ref string, options ...PullOption) (*PullResult, error) {
 
 	var descriptors, layers []ocispec.Descriptor
 
-	repository, err := remote.NewRepository(parsedRef.String())
-	if err != nil {
-		return nil, err
-	}
-	repository.PlainHTTP = c.plainHTTP
-	repository.Client = c.authorizer
-
 	ctx := context.Background()
 
 	sort.Strings(allowedMediaTypes)
 
 	var mu sync.Mutex
-	manifest, err := oras.Copy(ctx, repository, parsedRef.String(), memoryStore, "", oras.CopyOptions{
-		CopyGraphOptions: oras.CopyGraphOptions{
-			PreCopy: func(_ context.Context, desc ocispec.Descriptor) error {
-				mediaType := desc.MediaType
-				if i := sort.SearchStrings(allowedMediaTypes, mediaType); i >= len(allowedMediaTypes) || allowedMediaTypes[i] != mediaType {
-					return oras.SkipNode
-				}
-
-				mu.Lock()
-				layers = append(layers, desc)
-				mu.Unlock()
-				return nil
+	var manifest ocispec.Descriptor
+	err = c.withMirrors(parsedRef, func(repository *remote.Repository, parsedRef reference) (err error) {
+		layers = nil
+		manifest, err = oras.Copy(ctx, repository, parsedRef.String(), memoryStore, "", oras.CopyOptions{
+			CopyGraphOptions: oras.CopyGraphOptions{
+				PreCopy: func(_ context.Context, desc ocispec.Descriptor) error {
+					mediaType := desc.MediaType
+					if i := sort.SearchStrings(allowedMediaTypes, mediaType); i >= len(allowedMediaTypes) || allowedMediaTypes[i] != mediaType {
+						return oras.SkipNode
+					}
+
+					mu.Lock()
+					layers = append(layers, desc)
+					mu.Unlock()
+					return nil
+				},
 			},
-		},
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -588,11 +602,13 @@ type (
 
 	// PushResult is the result returned upon successful push.
 	PushResult struct {
-		Manifest *descriptorPushSummary         `json:"manifest"`
-		Config   *descriptorPushSummary         `json:"config"`
-		Chart    *descriptorPushSummaryWithMeta `json:"chart"`
-		Prov     *descriptorPushSummary         `json:"prov"`
-		Ref      string                         `json:"ref"`
+		Manifest    *descriptorPushSummary         `json:"manifest"`
+		Config      *descriptorPushSummary         `json:"config"`
+		Chart       *descriptorPushSummaryWithMeta `json:"chart"`
+		Prov        *descriptorPushSummary         `json:"prov"`
+		SBOM        *descriptorPushSummary         `json:"sbom,omitempty"`
+		Attachments []*Attachment                  `json:"attachments,omitempty"`
+		Ref         string                         `json:"ref"`
 	}
 
 	descriptorPushSummary struct {
@@ -606,9 +622,12 @@ type (
 	}
 
 	pushOperation struct {
-		provData     []byte
-		strictMode   bool
-		creationTime string
+		provData      []byte
+		sbomData      []byte
+		sbomMediaType string
+		attachments   []*Attachment
+		strictMode    bool
+		creationTime  string
 	}
 )
 
@@ -664,6 +683,15 @@ func (c *Client) Push(data []byte, ref string, options ...PushOption) (*PushResu
 
 		layers = append(layers, provDescriptor)
 	}
+	var sbomDescriptor ocispec.Descriptor
+	if operation.sbomData != nil {
+		sbomDescriptor, err = oras.PushBytes(ctx, memoryStore, operation.sbomMediaType, operation.sbomData)
+		if err != nil {
+			return nil, err
+		}
+
+		layers = append(layers, sbomDescriptor)
+	}
 
 	// sort layers for determinism, similar to how ORAS v1 does it
 	sort.Slice(layers, func(i, j int) bool {
@@ -678,6 +706,11 @@ func (c *Client) Push(data []byte, ref string, options ...PushOption) (*PushResu
 		return nil, err
 	}
 
+	// The attachments refer to the manifest, and are copied with it.
+	if err := packAttachments(ctx, memoryStore, manifestDescriptor, operation.attachments, operation.creationTime); err != nil {
+		return nil, err
+	}
+
 	repository, err := remote.NewRepository(parsedRef.String())
 	if err != nil {
 		return nil, err
@@ -714,8 +747,18 @@ func (c *Client) Push(data []byte, ref string, options ...PushOption) (*PushResu
 			Size:   provDescriptor.Size,
 		}
 	}
+	if operation.sbomData != nil {
+		result.SBOM = &descriptorPushSummary{
+			Digest: sbomDescriptor.Digest.String(),
+			Size:   sbomDescriptor.Size,
+		}
+	}
+	result.Attachments = operation.attachments
 	fmt.Fprintf(c.out, "Pushed: %s\n", result.Ref)
 	fmt.Fprintf(c.out, "Digest: %s\n", result.Manifest.Digest)
+	for _, a := range operation.attachments {
+		fmt.Fprintf(c.out, "Attached: %s\n", a.Name)
+	}
 	if strings.Contains(parsedRef.orasReference.Reference, "_") {
 		fmt.Fprintf(c.out, "%s contains an underscore.\n", result.Ref)
 		fmt.Fprint(c.out, registryUnderscoreMessage+"\n")
@@ -731,6 +774,15 @@ func PushOptProvData(provData []byte) PushOption {
 	}
 }
 
+// PushOptSBOM returns a function that sets the software bill of materials that
+// is pushed as an additional layer of the chart, with the given media type
+func PushOptSBOM(sbomData []byte, mediaType string) PushOption {
+	return func(operation *pushOperation) {
+		operation.sbomData = sbomData
+		operation.sbomMediaType = mediaType
+	}
+}
+
 // PushOptStrictMode returns a function that sets the strictMode setting on push
 func PushOptStrictMode(strictMode bool) PushOption {
 	return func(operation *pushOperation) {
@@ -747,31 +799,27 @@ func PushOptCreationTime(creationTime string) PushOption {
 
 // Tags provides a sorted list all semver compliant tags for a given repository
 func (c *Client) Tags(ref string) ([]string, error) {
-	parsedReference, err := registry.ParseReference(ref)
+	parsedReference, err := newReference(ref)
 	if err != nil {
 		return nil, err
 	}
 
 	ctx := context.Background()
-	repository, err := remote.NewRepository(parsedReference.String())
-	if err != nil {
-		return nil, err
-	}
-	repository.PlainHTTP = c.plainHTTP
-	repository.Client = c.authorizer
-
 	var tagVersions []*semver.Version
-	err = repository.Tags(ctx, "", func(tags []string) error {
-		for _, tag := range tags {
-			// Change underscore (_) back to plus (+) for Helm
-			// See https://github.com/helm/helm/issues/10166
-			tagVersion, err := semver.StrictNewVersion(strings.ReplaceAll(tag, "_", "+"))
-			if err == nil {
-				tagVersions = append(tagVersions, tagVersion)
+	err = c.withMirrors(parsedReference, func(repository *remote.Repository, _ reference) error {
+		tagVersions = nil
+		return repository.Tags(ctx, "", func(tags []string) error {
+			for _, tag := range tags {
+				// Change underscore (_) back to plus (+) for Helm
+				// See https://github.com/helm/helm/issues/10166
+				tagVersion, err := semver.StrictNewVersion(strings.ReplaceAll(tag, "_", "+"))
+				if err == nil {
+					tagVersions = append(tagVersions, tagVersion)
+				}
 			}
-		}
 
-		return nil
+			return nil
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -792,20 +840,17 @@ func (c *Client) Tags(ref string) ([]string, error) {
 
 // Resolve a reference to a descriptor.
 func (c *Client) Resolve(ref string) (desc ocispec.Descriptor, err error) {
-	remoteRepository, err := remote.NewRepository(ref)
-	if err != nil {
-		return desc, err
-	}
-	remoteRepository.PlainHTTP = c.plainHTTP
-
 	parsedReference, err := newReference(ref)
 	if err != nil {
 		return desc, err
 	}
 
 	ctx := context.Background()
-	parsedString := parsedReference.String()
-	return remoteRepository.Resolve(ctx, parsedString)
+	err = c.withMirrors(parsedReference, func(repository *remote.Repository, parsedReference reference) (err error) {
+		desc, err = repository.Resolve(ctx, parsedReference.String())
+		return err
+	})
+	return desc, err
 }
 
 // ValidateReference for path and version
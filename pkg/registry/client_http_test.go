@@ -76,6 +76,10 @@ func (suite *HTTPRegistryClientTestSuite) Test_4_ManInTheMiddle() {
 	suite.True(errors.Is(err, content.ErrMismatchedDigest))
 }
 
+func (suite *HTTPRegistryClientTestSuite) Test_5_Manage() {
+	testManage(&suite.TestSuite)
+}
+
 func TestHTTPRegistryClientTestSuite(t *testing.T) {
 	suite.Run(t, new(HTTPRegistryClientTestSuite))
 }
@@ -23,6 +23,9 @@ const (
 	// CredentialsFileBasename is the filename for auth credentials file
 	CredentialsFileBasename = "registry/config.json"
 
+	// MirrorsFileBasename is the filename for the registry mirrors file
+	MirrorsFileBasename = "registry/mirrors.yaml"
+
 	// ConfigMediaType is the reserved media type for the Helm chart manifest config
 	ConfigMediaType = "application/vnd.cncf.helm.config.v1+json"
 
@@ -32,6 +35,29 @@ const (
 	// ProvLayerMediaType is the reserved media type for Helm chart provenance files
 	ProvLayerMediaType = "application/vnd.cncf.helm.chart.provenance.v1.prov"
 
+	// SignatureArtifactType is the artifact type of a detached chart signature,
+	// which refers to the manifest of the chart it signs
+	SignatureArtifactType = "application/vnd.cncf.helm.chart.signature.v1"
+
+	// AttachmentAnnotation is the annotation of the manifest of a file attached
+	// to a chart, which refers to the manifest of the chart, with the name of
+	// the file
+	AttachmentAnnotation = "sh.helm.chart.attachment"
+
+	// DefaultAttachmentMediaType is the media type of attached files whose
+	// media type is not given
+	DefaultAttachmentMediaType = "application/octet-stream"
+
+	// OCILayoutScheme is the URL scheme for charts stored in an OCI image layout,
+	// such as a bundle
+	OCILayoutScheme = "oci-layout"
+
+	// BundleArtifactType is the artifact type of the metadata of a Helm bundle
+	BundleArtifactType = "application/vnd.cncf.helm.bundle.v1"
+
+	// BundleMetadataMediaType is the media type of the metadata of a Helm bundle
+	BundleMetadataMediaType = "application/vnd.cncf.helm.bundle.metadata.v1+json"
+
 	// LegacyChartLayerMediaType is the legacy reserved media type for Helm chart package content.
 	LegacyChartLayerMediaType = "application/tar+gzip"
 )
@@ -0,0 +1,64 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry // import "helm.sh/helm/v4/pkg/registry"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+)
+
+// Copy copies a chart from one registry reference to another.
+//
+// The manifest, the config and the layers of the chart, including its
+// provenance, are copied unchanged, so the chart keeps its digest. Detached
+// signatures that refer to the chart are copied with it. The destination must
+// be a tag.
+func (c *Client) Copy(src, dst string) (ocispec.Descriptor, error) {
+	srcRef, err := newReference(src)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	dstRef, err := newReference(dst)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if dstRef.Tag == "" {
+		return ocispec.Descriptor{}, errors.New("the destination of a copy must have a tag")
+	}
+	srcRepository, err := c.repository(srcRef)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	dstRepository, err := c.repository(dstRef)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	tagOrDigest := srcRef.Tag
+	if tagOrDigest == "" {
+		tagOrDigest = srcRef.Digest
+	}
+	desc, err := oras.ExtendedCopy(context.Background(), srcRepository, tagOrDigest, dstRepository, dstRef.Tag, oras.DefaultExtendedCopyOptions)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("could not copy %s to %s: %w", srcRef.String(), dstRef.String(), err)
+	}
+	return desc, nil
+}
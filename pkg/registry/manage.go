@@ -0,0 +1,234 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry // import "helm.sh/helm/v4/pkg/registry"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+)
+
+// ErrTagDeleteUnsupported is returned when a registry does not allow deleting
+// tags.
+var ErrTagDeleteUnsupported = errors.New("the registry does not support deleting tags")
+
+// InspectResult describes a manifest in a registry.
+type InspectResult struct {
+	// Ref is the reference of the manifest, with its digest.
+	Ref string `json:"ref"`
+	// Manifest is the descriptor of the manifest.
+	Manifest ocispec.Descriptor `json:"manifest"`
+	// ArtifactType is the artifact type of the manifest, or the media type
+	// of its config when it has none, e.g. ConfigMediaType for a chart.
+	ArtifactType string `json:"artifactType,omitempty"`
+	// Annotations are the annotations of the manifest, e.g. the annotations
+	// generated from the metadata of a chart when it was pushed.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Config is the descriptor of the config of the manifest.
+	Config ocispec.Descriptor `json:"config"`
+	// Layers are the descriptors of the layers of the manifest.
+	Layers []ocispec.Descriptor `json:"layers"`
+	// Referrers are the descriptors of the manifests that refer to the
+	// manifest, e.g. its signatures.
+	Referrers []ocispec.Descriptor `json:"referrers"`
+}
+
+// Tag adds tags to a manifest in a registry, without pushing the manifest
+// again. As for pushed charts, plus (+) signs in the tags are replaced with
+// underscores (_). It returns the descriptor of the manifest.
+func (c *Client) Tag(ref string, tags ...string) (ocispec.Descriptor, error) {
+	parsedRef, err := newReference(ref)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	repository, err := c.repository(parsedRef)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	ctx := context.Background()
+	desc, err := repository.Resolve(ctx, parsedRef.String())
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	for _, tag := range tags {
+		// See https://github.com/helm/helm/issues/10166
+		tag = strings.ReplaceAll(tag, "+", "_")
+		if err := repository.Tag(ctx, desc, tag); err != nil {
+			return ocispec.Descriptor{}, fmt.Errorf("could not tag %s as %s: %w", parsedRef.String(), tag, err)
+		}
+		fmt.Fprintf(c.out, "Tagged: %s:%s\n", parsedRef.Registry+"/"+parsedRef.Repository, tag)
+	}
+	return desc, nil
+}
+
+// Delete deletes a tag or a manifest from a registry. A reference with a
+// digest deletes the manifest, and with it all of its tags. A reference with
+// only a tag deletes the tag, and leaves the manifest in place. Registries may
+// not allow either; ErrTagDeleteUnsupported is returned when a registry does
+// not allow deleting tags.
+func (c *Client) Delete(ref string) error {
+	parsedRef, err := newReference(ref)
+	if err != nil {
+		return err
+	}
+	if parsedRef.Tag == "" && parsedRef.Digest == "" {
+		return fmt.Errorf("%s: a tag or a digest is required to delete", ref)
+	}
+	repository, err := c.repository(parsedRef)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if parsedRef.Digest == "" {
+		if err := deleteTag(ctx, repository, parsedRef.Tag); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "Deleted: %s\n", parsedRef.String())
+		return nil
+	}
+
+	desc, err := repository.Resolve(ctx, parsedRef.Digest)
+	if err != nil {
+		return err
+	}
+	if err := repository.Manifests().Delete(ctx, desc); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "Deleted: %s@%s\n", parsedRef.Registry+"/"+parsedRef.Repository, desc.Digest)
+	return nil
+}
+
+// deleteTag deletes a tag from a repository. Unlike manifests, tags cannot be
+// deleted through oras, so the request is made directly.
+func deleteTag(ctx context.Context, repository *remote.Repository, tag string) error {
+	ref := repository.Reference
+	ref.Reference = tag
+	ctx = auth.AppendRepositoryScope(ctx, ref, auth.ActionDelete)
+	scheme := "https"
+	if repository.PlainHTTP {
+		scheme = "http"
+	}
+	url := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", scheme, ref.Host(), ref.Repository, tag)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+	client := repository.Client
+	if client == nil {
+		client = auth.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusAccepted, http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("%s: %w", ref, errdef.ErrNotFound)
+	case http.StatusMethodNotAllowed:
+		return fmt.Errorf("%s: %w", ref, ErrTagDeleteUnsupported)
+	}
+	errResp := &errcode.ErrorResponse{
+		Method:     req.Method,
+		URL:        req.URL,
+		StatusCode: resp.StatusCode,
+	}
+	var body struct {
+		Errors errcode.Errors `json:"errors"`
+	}
+	if data, err := io.ReadAll(io.LimitReader(resp.Body, 8*1024)); err == nil && json.Unmarshal(data, &body) == nil {
+		errResp.Errors = body.Errors
+	}
+	for _, e := range errResp.Errors {
+		if e.Code == "UNSUPPORTED" {
+			return fmt.Errorf("%s: %w", ref, ErrTagDeleteUnsupported)
+		}
+	}
+	return errResp
+}
+
+// Inspect describes a manifest in a registry: its descriptor, annotations,
+// config, layers and the manifests that refer to it.
+func (c *Client) Inspect(ref string) (*InspectResult, error) {
+	parsedRef, err := newReference(ref)
+	if err != nil {
+		return nil, err
+	}
+	repository, err := c.repository(parsedRef)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	desc, err := repository.Resolve(ctx, parsedRef.String())
+	if err != nil {
+		return nil, err
+	}
+	if desc.MediaType != ocispec.MediaTypeImageManifest {
+		return nil, fmt.Errorf("cannot inspect %s: unsupported media type %s", parsedRef.String(), desc.MediaType)
+	}
+	data, err := content.FetchAll(ctx, repository, desc)
+	if err != nil {
+		return nil, err
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", desc.Digest, err)
+	}
+
+	result := &InspectResult{
+		Ref:          parsedRef.Registry + "/" + parsedRef.Repository + "@" + desc.Digest.String(),
+		Manifest:     desc,
+		ArtifactType: manifest.ArtifactType,
+		Annotations:  manifest.Annotations,
+		Config:       manifest.Config,
+		Layers:       manifest.Layers,
+		Referrers:    []ocispec.Descriptor{},
+	}
+	if result.ArtifactType == "" {
+		result.ArtifactType = manifest.Config.MediaType
+	}
+	if result.Layers == nil {
+		result.Layers = []ocispec.Descriptor{}
+	}
+	// The repository falls back to the referrers tag schema when the
+	// registry does not support the referrers API.
+	err = repository.Referrers(ctx, desc, "", func(descs []ocispec.Descriptor) error {
+		result.Referrers = append(result.Referrers, descs...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list the referrers of %s: %w", result.Ref, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,245 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry // import "helm.sh/helm/v4/pkg/registry"
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+	"sigs.k8s.io/yaml"
+
+	"helm.sh/helm/v4/internal/tlsutil"
+	"helm.sh/helm/v4/internal/version"
+	"helm.sh/helm/v4/pkg/helmpath"
+)
+
+// MirrorsFile is the registry mirrors file.
+//
+// It maps upstream registries to the mirrors that charts are pulled from
+// instead, e.g.
+//
+//	mirrors:
+//	  - upstream: ghcr.io
+//	    endpoints:
+//	      - host: registry-proxy.example.com/ghcr
+//	        caFile: /etc/ssl/proxy-ca.pem
+//	  - upstream: docker.io/bitnamicharts
+//	    endpoints:
+//	      - host: registry-proxy.example.com/bitnami
+//	        username: helm
+//	        password: secret
+type MirrorsFile struct {
+	Mirrors []*Mirror `json:"mirrors"`
+}
+
+// Mirror is the list of mirrors of an upstream registry.
+type Mirror struct {
+	// Upstream is the host of the registry whose references are rewritten,
+	// optionally followed by a path prefix of the repositories, e.g. ghcr.io
+	// or docker.io/bitnamicharts.
+	Upstream string `json:"upstream"`
+	// Endpoints are the mirrors in order of priority. A reference is pulled
+	// from the next mirror when a mirror fails, and finally from the upstream
+	// registry.
+	Endpoints []*MirrorEndpoint `json:"endpoints"`
+}
+
+// MirrorEndpoint is a mirror of an upstream registry.
+type MirrorEndpoint struct {
+	// Host is the host of the mirror, optionally followed by a path prefix,
+	// that replaces the upstream in references.
+	Host                  string `json:"host"`
+	PlainHTTP             bool   `json:"plainHTTP,omitempty"`
+	InsecureSkipTLSVerify bool   `json:"insecureSkipTLSVerify,omitempty"`
+	CertFile              string `json:"certFile,omitempty"`
+	KeyFile               string `json:"keyFile,omitempty"`
+	CAFile                string `json:"caFile,omitempty"`
+	// Username and Password authenticate to the mirror. Without them, the
+	// credentials stored for the host of the mirror are used.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	authorizer *auth.Client
+}
+
+// LoadMirrorsFile loads a registry mirrors file. A file that does not exist
+// has no mirrors.
+func LoadMirrorsFile(path string) (*MirrorsFile, error) {
+	f := &MirrorsFile{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.UnmarshalStrict(data, f); err != nil {
+		return nil, fmt.Errorf("cannot load the registry mirrors file %s: %w", path, err)
+	}
+	for _, m := range f.Mirrors {
+		m.Upstream = trimMirrorHost(m.Upstream)
+		if m.Upstream == "" {
+			return nil, fmt.Errorf("%s: a mirror has no upstream", path)
+		}
+		for _, e := range m.Endpoints {
+			e.Host = trimMirrorHost(e.Host)
+			if e.Host == "" {
+				return nil, fmt.Errorf("%s: a mirror of %s has no host", path, m.Upstream)
+			}
+		}
+	}
+	return f, nil
+}
+
+func trimMirrorHost(host string) string {
+	return strings.Trim(strings.TrimPrefix(host, OCIScheme+"://"), "/")
+}
+
+// find returns the mirror of a reference, the one with the longest upstream
+// that the repository of the reference is below.
+func (f *MirrorsFile) find(ref reference) *Mirror {
+	name := ref.Registry + "/" + ref.Repository
+	var found *Mirror
+	for _, m := range f.Mirrors {
+		if name != m.Upstream && !strings.HasPrefix(name, m.Upstream+"/") {
+			continue
+		}
+		if found == nil || len(m.Upstream) > len(found.Upstream) {
+			found = m
+		}
+	}
+	return found
+}
+
+// rewrite returns the reference with the upstream replaced by the host of
+// the endpoint.
+func (m *Mirror) rewrite(ref reference, e *MirrorEndpoint) reference {
+	name := e.Host + strings.TrimPrefix(ref.Registry+"/"+ref.Repository, m.Upstream)
+	registry, repository, _ := strings.Cut(name, "/")
+	ref.Registry = registry
+	ref.Repository = repository
+	ref.orasReference.Registry = registry
+	ref.orasReference.Repository = repository
+	return ref
+}
+
+// name returns the reference without the tag and digest if it has neither,
+// unlike String.
+func (r *reference) name() string {
+	if r.Tag == "" && r.Digest == "" {
+		return r.Registry + "/" + r.Repository
+	}
+	return r.String()
+}
+
+// setupMirrors creates the authorizers of the mirror endpoints of the client.
+func (c *Client) setupMirrors() error {
+	if c.mirrorsFile == "" {
+		c.mirrorsFile = helmpath.ConfigPath(MirrorsFileBasename)
+	}
+	mirrors, err := LoadMirrorsFile(c.mirrorsFile)
+	if err != nil {
+		return err
+	}
+	for _, m := range mirrors.Mirrors {
+		for _, e := range m.Endpoints {
+			e.authorizer, err = c.mirrorAuthorizer(e)
+			if err != nil {
+				return fmt.Errorf("mirror %s of %s: %w", e.Host, m.Upstream, err)
+			}
+		}
+	}
+	c.mirrors = mirrors
+	return nil
+}
+
+func (c *Client) mirrorAuthorizer(e *MirrorEndpoint) (*auth.Client, error) {
+	authorizer := &auth.Client{
+		Client: c.httpClient,
+	}
+	if e.InsecureSkipTLSVerify || e.CAFile != "" || (e.CertFile != "" && e.KeyFile != "") {
+		tlsConf, err := tlsutil.NewTLSConfig(
+			tlsutil.WithInsecureSkipVerify(e.InsecureSkipTLSVerify),
+			tlsutil.WithCertKeyPairFiles(e.CertFile, e.KeyFile),
+			tlsutil.WithCAFile(e.CAFile),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("can't create TLS config for client: %w", err)
+		}
+		authorizer.Client = &http.Client{Transport: NewTransport(c.debug)}
+		transportTLSConf, err := ensureTLSConfig(authorizer)
+		if err != nil {
+			return nil, err
+		}
+		transportTLSConf.InsecureSkipVerify = tlsConf.InsecureSkipVerify
+		transportTLSConf.Certificates = tlsConf.Certificates
+		transportTLSConf.RootCAs = tlsConf.RootCAs
+	}
+	authorizer.SetUserAgent(version.GetUserAgent())
+
+	if e.Username != "" || e.Password != "" {
+		host, _, _ := strings.Cut(e.Host, "/")
+		authorizer.Credential = auth.StaticCredential(host, auth.Credential{Username: e.Username, Password: e.Password})
+	} else {
+		authorizer.Credential = credentials.Credential(c.credentialsStore)
+	}
+	if c.enableCache {
+		authorizer.Cache = auth.NewCache()
+	}
+	return authorizer, nil
+}
+
+// withMirrors calls fn with the repository of a reference in each mirror of
+// the reference in order of priority, and finally in its own registry, until
+// fn succeeds. It returns the error of the last call.
+func (c *Client) withMirrors(ref reference, fn func(repository *remote.Repository, ref reference) error) error {
+	var m *Mirror
+	if c.mirrors != nil {
+		m = c.mirrors.find(ref)
+	}
+	if m != nil {
+		for _, e := range m.Endpoints {
+			mirrored := m.rewrite(ref, e)
+			repository, err := remote.NewRepository(mirrored.Registry + "/" + mirrored.Repository)
+			if err != nil {
+				return err
+			}
+			repository.PlainHTTP = e.PlainHTTP
+			repository.Client = e.authorizer
+
+			slog.Info("using registry mirror", "reference", ref.name(), "mirror", mirrored.name())
+			err = fn(repository, mirrored)
+			if err == nil {
+				return nil
+			}
+			slog.Warn("registry mirror failed", "mirror", mirrored.name(), slog.Any("error", err))
+		}
+	}
+
+	repository, err := c.repository(ref)
+	if err != nil {
+		return err
+	}
+	return fn(repository, ref)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+const testMirrorsFile = `mirrors:
+  - upstream: oci://ghcr.io/
+    endpoints:
+      - host: proxy.example.com/ghcr
+      - host: backup.example.com
+        plainHTTP: true
+  - upstream: ghcr.io/helm
+    endpoints:
+      - host: proxy.example.com/helm
+        username: helm
+        password: secret
+`
+
+func writeMirrorsFile(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mirrors.yaml")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMirrorsFile(t *testing.T) {
+	f, err := LoadMirrorsFile(writeMirrorsFile(t, testMirrorsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Mirrors) != 2 {
+		t.Fatalf("expected 2 mirrors, got %d", len(f.Mirrors))
+	}
+	if f.Mirrors[0].Upstream != "ghcr.io" {
+		t.Errorf("expected upstream ghcr.io, got %s", f.Mirrors[0].Upstream)
+	}
+	if !f.Mirrors[0].Endpoints[1].PlainHTTP {
+		t.Error("expected the second endpoint to use plain HTTP")
+	}
+
+	f, err = LoadMirrorsFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Mirrors) != 0 {
+		t.Errorf("expected no mirrors, got %d", len(f.Mirrors))
+	}
+
+	for _, data := range []string{
+		"mirrors:\n  - endpoints:\n      - host: proxy.example.com\n",
+		"mirrors:\n  - upstream: ghcr.io\n    endpoints:\n      - caFile: ca.pem\n",
+		"mirrors:\n  - upstream: ghcr.io\n    unknown: true\n",
+	} {
+		if _, err := LoadMirrorsFile(writeMirrorsFile(t, data)); err == nil {
+			t.Errorf("expected an error loading %q", data)
+		}
+	}
+}
+
+func TestMirrorsFileRewrite(t *testing.T) {
+	f, err := LoadMirrorsFile(writeMirrorsFile(t, testMirrorsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ref      string
+		expected []string
+	}{
+		{"ghcr.io/acme/mychart:1.0.0", []string{"proxy.example.com/ghcr/acme/mychart:1.0.0", "backup.example.com/acme/mychart:1.0.0"}},
+		{"ghcr.io/helm/charts/nginx:2.0.0", []string{"proxy.example.com/helm/charts/nginx:2.0.0"}},
+		{"ghcr.io/helmfile/chart:1.0.0", []string{"proxy.example.com/ghcr/helmfile/chart:1.0.0", "backup.example.com/helmfile/chart:1.0.0"}},
+		{"docker.io/bitnamicharts/nginx:1.0.0", nil},
+	}
+	for _, tt := range tests {
+		ref, err := newReference(tt.ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := f.find(ref)
+		if tt.expected == nil {
+			if m != nil {
+				t.Errorf("%s: expected no mirror, got %s", tt.ref, m.Upstream)
+			}
+			continue
+		}
+		if m == nil {
+			t.Fatalf("%s: expected a mirror", tt.ref)
+		}
+		if len(m.Endpoints) != len(tt.expected) {
+			t.Fatalf("%s: expected %d endpoints, got %d", tt.ref, len(tt.expected), len(m.Endpoints))
+		}
+		for i, e := range m.Endpoints {
+			mirrored := m.rewrite(ref, e)
+			if mirrored.String() != tt.expected[i] {
+				t.Errorf("%s: expected %s, got %s", tt.ref, tt.expected[i], mirrored.String())
+			}
+		}
+	}
+}
+
+func TestClientWithMirrors(t *testing.T) {
+	client, err := NewClient(ClientOptMirrorsFile(writeMirrorsFile(t, testMirrorsFile)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := newReference("ghcr.io/acme/mychart:1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tried []string
+	errMirror := errors.New("mirror unavailable")
+	err = client.withMirrors(ref, func(repository *remote.Repository, ref reference) error {
+		tried = append(tried, ref.String())
+		if repository.Reference.Registry != "ghcr.io" {
+			return errMirror
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"proxy.example.com/ghcr/acme/mychart:1.0.0",
+		"backup.example.com/acme/mychart:1.0.0",
+		"ghcr.io/acme/mychart:1.0.0",
+	}
+	if len(tried) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, tried)
+	}
+	for i := range expected {
+		if tried[i] != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], tried[i])
+		}
+	}
+}
+
+func TestClientWithMalformedMirrors(t *testing.T) {
+	client, err := NewClient(ClientOptMirrorsFile(writeMirrorsFile(t, "mirrors: [")))
+	if err != nil {
+		t.Fatalf("expected a malformed mirrors file to be ignored, got %s", err)
+	}
+	if client.mirrors != nil {
+		t.Errorf("expected no mirrors, got %v", client.mirrors)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry // import "helm.sh/helm/v4/pkg/registry"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// Signature is a detached signature of a chart in a registry.
+type Signature struct {
+	// Prov is the provenance data of the signature.
+	Prov []byte
+	// Digest is the digest of the manifest of the signature.
+	Digest string
+	// Created is the time the signature was attached, if it is known.
+	Created string
+}
+
+// repository returns the remote repository of a reference.
+func (c *Client) repository(parsedRef reference) (*remote.Repository, error) {
+	repository, err := remote.NewRepository(parsedRef.Registry + "/" + parsedRef.Repository)
+	if err != nil {
+		return nil, err
+	}
+	repository.PlainHTTP = c.plainHTTP
+	repository.Client = c.authorizer
+	return repository, nil
+}
+
+// PushSignature attaches the provenance data of a chart in a registry to the
+// chart, as a detached signature artifact that refers to the manifest of the
+// chart. The chart itself is not pushed again.
+//
+// The signature is found through the referrers API of the registry, or through
+// the referrers tag schema when the registry does not support it.
+func (c *Client) PushSignature(ref string, provData []byte) (ocispec.Descriptor, error) {
+	parsedRef, err := newReference(ref)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	repository, err := c.repository(parsedRef)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	ctx := context.Background()
+	subject, err := repository.Resolve(ctx, parsedRef.String())
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	provDescriptor, err := oras.PushBytes(ctx, repository, ProvLayerMediaType, provData)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc, err := oras.PackManifest(ctx, repository, oras.PackManifestVersion1_1, SignatureArtifactType, oras.PackManifestOptions{
+		Subject: &subject,
+		Layers:  []ocispec.Descriptor{provDescriptor},
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	fmt.Fprintf(c.out, "Signed: %s\n", parsedRef.String())
+	fmt.Fprintf(c.out, "Signature: %s\n", desc.Digest)
+	return desc, nil
+}
+
+// Signatures returns the detached signatures of a chart in a registry, the
+// most recent first.
+func (c *Client) Signatures(ref string) ([]*Signature, error) {
+	parsedRef, err := newReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	var signatures []*Signature
+	err = c.withMirrors(parsedRef, func(repository *remote.Repository, parsedRef reference) error {
+		signatures, err = signaturesOf(ctx, repository, parsedRef)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	// RFC 3339 times in UTC sort as strings.
+	sort.SliceStable(signatures, func(i, j int) bool {
+		return signatures[i].Created > signatures[j].Created
+	})
+	return signatures, nil
+}
+
+// signaturesOf returns the detached signatures of a chart in a repository.
+func signaturesOf(ctx context.Context, repository *remote.Repository, parsedRef reference) ([]*Signature, error) {
+	subject, err := repository.Resolve(ctx, parsedRef.String())
+	if err != nil {
+		return nil, err
+	}
+	// The repository falls back to the referrers tag schema when the
+	// registry does not support the referrers API.
+	var referrers []ocispec.Descriptor
+	err = repository.Referrers(ctx, subject, SignatureArtifactType, func(descs []ocispec.Descriptor) error {
+		referrers = append(referrers, descs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var signatures []*Signature
+	for _, desc := range referrers {
+		data, err := content.FetchAll(ctx, repository, desc)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve signature %s: %w", desc.Digest, err)
+		}
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("invalid signature %s: %w", desc.Digest, err)
+		}
+		for _, layer := range manifest.Layers {
+			if layer.MediaType != ProvLayerMediaType {
+				continue
+			}
+			prov, err := content.FetchAll(ctx, repository.Blobs(), layer)
+			if err != nil {
+				return nil, fmt.Errorf("unable to retrieve blob with digest %s: %w", layer.Digest, err)
+			}
+			signatures = append(signatures, &Signature{
+				Prov:    prov,
+				Digest:  desc.Digest.String(),
+				Created: manifest.Annotations[ocispec.AnnotationCreated],
+			})
+			break
+		}
+	}
+	return signatures, nil
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -37,9 +38,11 @@ import (
 	_ "github.com/distribution/distribution/v3/registry/auth/htpasswd"
 	_ "github.com/distribution/distribution/v3/registry/storage/driver/inmemory"
 	"github.com/foxcpp/go-mockdns"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/phayes/freeport"
 	"github.com/stretchr/testify/suite"
 	"golang.org/x/crypto/bcrypt"
+	"oras.land/oras-go/v2/errdef"
 
 	"helm.sh/helm/v4/internal/tlsutil"
 )
@@ -144,7 +147,11 @@ func setup(suite *TestSuite, tlsEnabled, insecure bool) *registry.Registry {
 
 	config.HTTP.Addr = fmt.Sprintf("127.0.0.1:%d", port)
 	config.HTTP.DrainTimeout = time.Duration(10) * time.Second
-	config.Storage = map[string]configuration.Parameters{"inmemory": map[string]interface{}{}}
+	config.Storage = map[string]configuration.Parameters{
+		"inmemory": map[string]interface{}{},
+		"delete":   map[string]interface{}{"enabled": true},
+	}
+	config.Catalog.MaxEntries = 1000
 
 	config.Auth = configuration.Auth{
 		"htpasswd": configuration.Parameters{
@@ -305,6 +312,91 @@ func testPush(suite *TestSuite) {
 	suite.Equal(
 		"sha256:b0a02b7412f78ae93324d48df8fcc316d8482e5ad7827b5b238657a29a22f256",
 		result.Prov.Digest)
+	suite.Nil(result.SBOM, "no SBOM pushed")
+
+	// attach a detached signature to a chart pushed without provenance
+	ref = fmt.Sprintf("%s/testrepo/signed/%s:%s", suite.DockerRegistryHost, meta.Name, meta.Version)
+	unsigned, err := suite.RegistryClient.Push(chartData, ref, PushOptCreationTime(testingChartCreationTime))
+	suite.Nil(err, "no error pushing a chart without prov")
+	signatures, err := suite.RegistryClient.Signatures(ref)
+	suite.Nil(err, "no error listing the signatures of an unsigned chart")
+	suite.Empty(signatures)
+	signature, err := suite.RegistryClient.PushSignature(ref, provData)
+	suite.Nil(err, "no error pushing a signature")
+	suite.Equal(SignatureArtifactType, signature.ArtifactType)
+	signatures, err = suite.RegistryClient.Signatures(ref)
+	suite.Nil(err, "no error listing the signatures of a signed chart")
+	suite.Require().Len(signatures, 1)
+	suite.Equal(provData, signatures[0].Prov)
+	suite.Equal(signature.Digest.String(), signatures[0].Digest)
+	suite.NotEmpty(signatures[0].Created)
+
+	// the chart is not changed by its signature
+	pullResult, err := suite.RegistryClient.Pull(ref)
+	suite.Nil(err, "no error pulling a chart with a detached signature")
+	suite.Equal(unsigned.Manifest.Digest, pullResult.Manifest.Digest)
+
+	// attach files to a chart as referrers of its manifest
+	ref = fmt.Sprintf("%s/testrepo/attached/%s:%s", suite.DockerRegistryHost, meta.Name, meta.Version)
+	valuesData := []byte("replicaCount: 3\n")
+	result, err = suite.RegistryClient.Push(chartData, ref, PushOptCreationTime(testingChartCreationTime),
+		PushOptAttachments(
+			&Attachment{Name: "values-prod.yaml", MediaType: "application/yaml", Data: valuesData},
+			&Attachment{Name: "README.html", Data: []byte("<h1>chart</h1>")},
+		))
+	suite.Nil(err, "no error pushing a chart with attachments")
+	suite.Len(result.Attachments, 2)
+	attachments, err := suite.RegistryClient.Attachments(ref)
+	suite.Nil(err, "no error listing the attachments of a chart")
+	suite.Require().Len(attachments, 2)
+	suite.Equal("README.html", attachments[0].Name)
+	suite.Equal(DefaultAttachmentMediaType, attachments[0].MediaType)
+	suite.Equal("values-prod.yaml", attachments[1].Name)
+	suite.Equal("application/yaml", attachments[1].MediaType)
+	suite.Equal(int64(len(valuesData)), attachments[1].Size)
+	suite.Nil(attachments[1].Data, "the content of listed attachments is not fetched")
+	err = suite.RegistryClient.FetchAttachment(ref, attachments[1])
+	suite.Nil(err, "no error fetching an attachment")
+	suite.Equal(valuesData, attachments[1].Data)
+
+	// attachments are not signatures, nor signatures attachments
+	_, err = suite.RegistryClient.PushSignature(ref, provData)
+	suite.Nil(err, "no error signing a chart with attachments")
+	attachments, err = suite.RegistryClient.Attachments(ref)
+	suite.Nil(err, "no error listing the attachments of a signed chart")
+	suite.Len(attachments, 2)
+	signatures, err = suite.RegistryClient.Signatures(ref)
+	suite.Nil(err, "no error listing the signatures of a chart with attachments")
+	suite.Len(signatures, 1)
+
+	// push with an SBOM
+	sbomData := []byte(`{"spdxVersion":"SPDX-2.3"}`)
+	ref = fmt.Sprintf("%s/testrepo/sbom/%s:%s", suite.DockerRegistryHost, meta.Name, meta.Version)
+	result, err = suite.RegistryClient.Push(chartData, ref, PushOptSBOM(sbomData, "application/spdx+json"), PushOptCreationTime(testingChartCreationTime))
+	suite.Nil(err, "no error pushing good ref with an SBOM")
+	suite.Equal(int64(len(sbomData)), result.SBOM.Size)
+
+	// the SBOM layer is ignored when pulling
+	pullResult, err = suite.RegistryClient.Pull(ref)
+	suite.Nil(err, "no error pulling a chart with an SBOM")
+	suite.Equal(chartData, pullResult.Chart.Data)
+
+	// push the charts of a bundle
+	var bundle bytes.Buffer
+	_, err = WriteBundle(&bundle, []BundleChart{{Data: chartData, Prov: provData}}, nil, testingChartCreationTime)
+	suite.Nil(err, "no error writing a bundle")
+	bundlePath := filepath.Join(suite.T().TempDir(), "signtest-0.1.0.bundle.tar")
+	suite.Nil(os.WriteFile(bundlePath, bundle.Bytes(), 0644))
+	b, err := LoadBundle(bundlePath)
+	suite.Nil(err, "no error loading a bundle")
+	refs, err := suite.RegistryClient.PushBundle(b, fmt.Sprintf("oci://%s/testrepo/bundle", suite.DockerRegistryHost))
+	suite.Nil(err, "no error pushing a bundle")
+	suite.Equal([]string{fmt.Sprintf("%s/testrepo/bundle/%s:%s", suite.DockerRegistryHost, meta.Name, meta.Version)}, refs)
+
+	pullResult, err = suite.RegistryClient.Pull(refs[0], PullOptWithProv(true))
+	suite.Nil(err, "no error pulling a chart pushed from a bundle")
+	suite.Equal(chartData, pullResult.Chart.Data)
+	suite.Equal(provData, pullResult.Prov.Data)
 }
 
 func testPull(suite *TestSuite) {
@@ -398,4 +490,69 @@ func testTags(suite *TestSuite) {
 	tags, err := suite.RegistryClient.Tags(ref)
 	suite.Nil(err, "no error retrieving tags")
 	suite.Equal(1, len(tags))
+
+	// The metadata is read from the config of the chart
+	chartMeta, err := suite.RegistryClient.ChartMetadata(fmt.Sprintf("%s:%s", ref, tags[0]))
+	suite.Nil(err, "no error retrieving chart metadata")
+	suite.Equal(meta.Name, chartMeta.Name)
+	suite.Equal(meta.Version, chartMeta.Version)
+
+	// The repositories below a prefix are listed with the catalog API
+	repositories, err := suite.RegistryClient.Repositories(suite.DockerRegistryHost, "testrepo")
+	suite.Nil(err, "no error listing repositories")
+	suite.Contains(repositories, "testrepo/"+meta.Name)
+	for _, repository := range repositories {
+		suite.True(strings.HasPrefix(repository, "testrepo/"), "unexpected repository %s", repository)
+	}
+}
+
+func testManage(suite *TestSuite) {
+	chartData, err := os.ReadFile("../downloader/testdata/local-subchart-0.1.0.tgz")
+	suite.Nil(err, "no error loading test chart")
+	meta, err := extractChartMeta(chartData)
+	suite.Nil(err, "no error extracting chart meta")
+	testingChartCreationTime := "1977-09-02T22:04:05Z"
+	repo := fmt.Sprintf("%s/testrepo/manage/%s", suite.DockerRegistryHost, meta.Name)
+	ref := fmt.Sprintf("%s:%s", repo, meta.Version)
+	result, err := suite.RegistryClient.Push(chartData, ref, PushOptCreationTime(testingChartCreationTime))
+	suite.Nil(err, "no error pushing a chart")
+
+	// a tag is added without pushing the chart again
+	desc, err := suite.RegistryClient.Tag(ref, "1.0.0+stable")
+	suite.Nil(err, "no error tagging a chart")
+	suite.Equal(result.Manifest.Digest, desc.Digest.String())
+	tags, err := suite.RegistryClient.Tags(repo)
+	suite.Nil(err, "no error retrieving tags")
+	suite.Equal([]string{"1.0.0+stable", meta.Version}, tags)
+
+	// the manifest is inspected with its annotations and layers
+	inspected, err := suite.RegistryClient.Inspect(repo + ":1.0.0+stable")
+	suite.Nil(err, "no error inspecting a chart")
+	suite.Equal(repo+"@"+result.Manifest.Digest, inspected.Ref)
+	suite.Equal(ConfigMediaType, inspected.ArtifactType)
+	suite.Equal(meta.Name, inspected.Annotations[ocispec.AnnotationTitle])
+	suite.Equal(meta.Version, inspected.Annotations[ocispec.AnnotationVersion])
+	suite.Equal(testingChartCreationTime, inspected.Annotations[ocispec.AnnotationCreated])
+	suite.Require().Len(inspected.Layers, 1)
+	suite.Equal(ChartLayerMediaType, inspected.Layers[0].MediaType)
+	suite.Empty(inspected.Referrers)
+
+	// deleting a tag leaves the manifest and its other tags in place
+	err = suite.RegistryClient.Delete(repo + ":1.0.0+stable")
+	suite.Nil(err, "no error deleting a tag")
+	tags, err = suite.RegistryClient.Tags(repo)
+	suite.Nil(err, "no error retrieving tags")
+	suite.Equal([]string{meta.Version}, tags)
+	err = suite.RegistryClient.Delete(repo + ":1.0.0+stable")
+	suite.True(errors.Is(err, errdef.ErrNotFound), "deleting a missing tag is not found")
+
+	// a reference without a tag or a digest is not deleted
+	err = suite.RegistryClient.Delete(repo)
+	suite.ErrorContains(err, "a tag or a digest is required")
+
+	// deleting a manifest by digest deletes its tags
+	err = suite.RegistryClient.Delete(repo + "@" + result.Manifest.Digest)
+	suite.Nil(err, "no error deleting a manifest")
+	_, err = suite.RegistryClient.Resolve(ref)
+	suite.NotNil(err, "the deleted manifest cannot be resolved")
 }
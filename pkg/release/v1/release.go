@@ -31,6 +31,15 @@ type Release struct {
 	// Config is the set of extra Values added to the chart.
 	// These values override the default values inside of the chart.
 	Config map[string]interface{} `json:"config,omitempty"`
+	// ValuesOrigins records where the values came from, by the dotted path of
+	// each value: the file or flag of the Config values, and the chart,
+	// parent chart or import of the values of the chart. Values that are
+	// defaults of the chart they belong to are left out.
+	//
+	// They are stored because they can't be derived from the release later:
+	// Chart holds the values after import-values were processed, and Config
+	// the merged user-supplied values.
+	ValuesOrigins map[string]ValueOrigin `json:"values_origins,omitempty"`
 	// Manifest is the string representation of the rendered template.
 	Manifest string `json:"manifest,omitempty"`
 	// Hooks are all of the hooks declared for this release.
@@ -44,6 +53,14 @@ type Release struct {
 	Labels map[string]string `json:"-"`
 }
 
+// ValueOrigin records where a value of a release came from.
+type ValueOrigin struct {
+	// Type is the kind of source, e.g. "file" or "chart".
+	Type string `json:"type"`
+	// Source identifies the source, e.g. a chart name, a file path or a flag.
+	Source string `json:"source,omitempty"`
+}
+
 // SetStatus is a helper for setting the status on a release.
 func (r *Release) SetStatus(status Status, msg string) {
 	r.Info.Status = status
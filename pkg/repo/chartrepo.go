@@ -17,17 +17,22 @@ limitations under the License.
 package repo // import "helm.sh/helm/v4/pkg/repo"
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log/slog"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
+	"helm.sh/helm/v4/internal/fileutil"
 	"helm.sh/helm/v4/pkg/getter"
 	"helm.sh/helm/v4/pkg/helmpath"
 )
@@ -51,6 +56,9 @@ type ChartRepository struct {
 	IndexFile *IndexFile
 	Client    getter.Getter
 	CachePath string
+
+	// index is the index loaded by LoadIndex.
+	index *Index
 }
 
 // NewChartRepository constructs ChartRepository
@@ -73,20 +81,33 @@ func NewChartRepository(cfg *Entry, getters getter.Providers) (*ChartRepository,
 	}, nil
 }
 
-// DownloadIndexFile fetches the index from a repository.
+// DownloadIndex fetches the index of a repository: its sharded index if the
+// repository serves one, and its index file otherwise.
+func (r *ChartRepository) DownloadIndex() error {
+	_, err := r.DownloadShardedIndex()
+	if err == nil {
+		return nil
+	}
+	slog.Debug("falling back to the index file of the repository", "repo", r.Config.URL, slog.Any("error", err))
+	_, err = r.DownloadIndexFile()
+	return err
+}
+
+// DownloadIndexFile fetches the index file from a repository, and returns its
+// path in the cache directory.
 func (r *ChartRepository) DownloadIndexFile() (string, error) {
+	r.index = nil
+	shardedDir := filepath.Join(r.CachePath, helmpath.CacheShardedIndexDir(r.Config.Name))
+	fname := filepath.Join(r.CachePath, helmpath.CacheIndexFile(r.Config.Name))
+
 	indexURL, err := ResolveReferenceURL(r.Config.URL, "index.yaml")
 	if err != nil {
 		return "", err
 	}
 
-	resp, err := r.Client.Get(indexURL,
-		getter.WithURL(r.Config.URL),
-		getter.WithInsecureSkipVerifyTLS(r.Config.InsecureSkipTLSverify),
-		getter.WithTLSClientConfig(r.Config.CertFile, r.Config.KeyFile, r.Config.CAFile),
-		getter.WithBasicAuth(r.Config.Username, r.Config.Password),
-		getter.WithPassCredentialsAll(r.Config.PassCredentialsAll),
-	)
+	// The cached index is reused when it has not been modified since it was
+	// downloaded.
+	resp, err := r.Client.Get(indexURL, append(r.getterOptions(), getter.WithCacheFile(fname))...)
 	if err != nil {
 		return "", err
 	}
@@ -98,24 +119,149 @@ func (r *ChartRepository) DownloadIndexFile() (string, error) {
 
 	indexFile, err := loadIndex(index, r.Config.URL)
 	if err != nil {
+		// An invalid index must not be reused.
+		os.Remove(getter.CacheHeadersFile(fname))
 		return "", err
 	}
 
+	// The index file replaces a sharded index downloaded before.
+	os.RemoveAll(shardedDir)
+
 	// Create the chart list file in the cache directory
 	var charts strings.Builder
 	for name := range indexFile.Entries {
 		fmt.Fprintln(&charts, name)
 	}
-	chartsFile := filepath.Join(r.CachePath, helmpath.CacheChartsFile(r.Config.Name))
-	os.MkdirAll(filepath.Dir(chartsFile), 0755)
-	os.WriteFile(chartsFile, []byte(charts.String()), 0644)
+	r.writeChartsFile(charts.String())
 
 	// Create the index file in the cache directory
-	fname := filepath.Join(r.CachePath, helmpath.CacheIndexFile(r.Config.Name))
 	os.MkdirAll(filepath.Dir(fname), 0755)
 	return fname, os.WriteFile(fname, index, 0644)
 }
 
+// DownloadShardedIndex fetches the root of the sharded index of a repository,
+// and returns its path in the cache directory. Unlike an index file, the root
+// only lists the charts of the repository: the versions of the charts are
+// downloaded by LoadIndex when they are first needed.
+func (r *ChartRepository) DownloadShardedIndex() (string, error) {
+	r.index = nil
+	rootURL, err := ResolveReferenceURL(r.Config.URL, path.Join(ShardedIndexDir, "index.yaml"))
+	if err != nil {
+		return "", err
+	}
+
+	fname := filepath.Join(r.CachePath, helmpath.CacheShardedIndexDir(r.Config.Name), "index.yaml")
+	resp, err := r.Client.Get(rootURL, append(r.getterOptions(), getter.WithCacheFile(fname))...)
+	if err != nil {
+		return "", err
+	}
+	data := resp.Bytes()
+
+	root, err := loadShardedIndex(data, rootURL)
+	if err != nil {
+		os.Remove(getter.CacheHeadersFile(fname))
+		return "", err
+	}
+
+	// The sharded index replaces an index file downloaded before.
+	indexFile := filepath.Join(r.CachePath, helmpath.CacheIndexFile(r.Config.Name))
+	os.Remove(indexFile)
+	os.Remove(getter.CacheHeadersFile(indexFile))
+
+	var charts strings.Builder
+	for name := range root.Charts {
+		fmt.Fprintln(&charts, name)
+	}
+	r.writeChartsFile(charts.String())
+
+	os.MkdirAll(filepath.Dir(fname), 0755)
+	return fname, os.WriteFile(fname, data, 0644)
+}
+
+// writeChartsFile creates the chart list file in the cache directory.
+func (r *ChartRepository) writeChartsFile(charts string) {
+	chartsFile := filepath.Join(r.CachePath, helmpath.CacheChartsFile(r.Config.Name))
+	os.MkdirAll(filepath.Dir(chartsFile), 0755)
+	os.WriteFile(chartsFile, []byte(charts), 0644)
+}
+
+// getterOptions returns the options of the getter for the repository.
+func (r *ChartRepository) getterOptions() []getter.Option {
+	return []getter.Option{
+		getter.WithURL(r.Config.URL),
+		getter.WithInsecureSkipVerifyTLS(r.Config.InsecureSkipTLSverify),
+		getter.WithTLSClientConfig(r.Config.CertFile, r.Config.KeyFile, r.Config.CAFile),
+		getter.WithBasicAuth(r.Config.Username, r.Config.Password),
+		getter.WithPassCredentialsAll(r.Config.PassCredentialsAll),
+	}
+}
+
+// LoadIndex loads the index of the repository that DownloadIndex cached.
+//
+// The versions of a chart in a sharded index are downloaded when they are first
+// needed, and cached until the root of the index changes their digest.
+func (r *ChartRepository) LoadIndex() (*Index, error) {
+	if r.index != nil {
+		return r.index, nil
+	}
+
+	rootFile := filepath.Join(r.CachePath, helmpath.CacheShardedIndexDir(r.Config.Name), "index.yaml")
+	data, err := os.ReadFile(rootFile)
+	switch {
+	case err == nil:
+		root, err := loadShardedIndex(data, rootFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading %s: %w", rootFile, err)
+		}
+		r.index = NewShardedIndex(root, r.loadChartShard)
+	case errors.Is(err, fs.ErrNotExist):
+		f, err := LoadIndexFile(filepath.Join(r.CachePath, helmpath.CacheIndexFile(r.Config.Name)))
+		if err != nil {
+			return nil, err
+		}
+		r.index = NewIndex(f)
+	default:
+		return nil, err
+	}
+	return r.index, nil
+}
+
+// loadChartShard loads the versions of a chart in the sharded index of the
+// repository, from the cache directory if they have not changed.
+func (r *ChartRepository) loadChartShard(name string, shard *ChartShard) (ChartVersions, error) {
+	fname := filepath.Join(r.CachePath, helmpath.CacheShardedIndexDir(r.Config.Name), filepath.FromSlash(shard.Path))
+	if data, err := os.ReadFile(fname); err == nil {
+		if cvs, err := loadChartShard(data, name, shard, fname); err == nil {
+			return cvs, nil
+		}
+	}
+
+	if r.Client == nil {
+		return nil, fmt.Errorf("no cached versions for chart %q in repository %s. (try 'helm repo update')", name, r.Config.Name)
+	}
+	shardURL, err := ResolveReferenceURL(r.Config.URL, path.Join(ShardedIndexDir, shard.Path))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.Client.Get(shardURL, r.getterOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download the versions of chart %q: %w", name, err)
+	}
+	data := resp.Bytes()
+	cvs, err := loadChartShard(data, name, shard, shardURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// The versions are still returned when they cannot be cached.
+	if err := os.MkdirAll(filepath.Dir(fname), 0755); err == nil {
+		if err := fileutil.AtomicWriteFile(fname, bytes.NewReader(data), 0644); err != nil {
+			slog.Warn("failed to cache the versions of chart", "chart", name, "file", fname, slog.Any("error", err))
+		}
+	}
+	return cvs, nil
+}
+
 type findChartInRepoURLOptions struct {
 	Username              string
 	Password              string
@@ -196,17 +342,18 @@ func FindChartInRepoURL(repoURL string, chartName string, getters getter.Provide
 	if err != nil {
 		return "", err
 	}
-	idx, err := r.DownloadIndexFile()
-	if err != nil {
+	if err := r.DownloadIndex(); err != nil {
 		return "", fmt.Errorf("looks like %q is not a valid chart repository or cannot be reached: %w", repoURL, err)
 	}
 	defer func() {
 		os.RemoveAll(filepath.Join(r.CachePath, helmpath.CacheChartsFile(r.Config.Name)))
 		os.RemoveAll(filepath.Join(r.CachePath, helmpath.CacheIndexFile(r.Config.Name)))
+		os.RemoveAll(getter.CacheHeadersFile(filepath.Join(r.CachePath, helmpath.CacheIndexFile(r.Config.Name))))
+		os.RemoveAll(filepath.Join(r.CachePath, helmpath.CacheShardedIndexDir(r.Config.Name)))
 	}()
 
-	// Read the index file for the repository to get chart information and return chart URL
-	repoIndex, err := LoadIndexFile(idx)
+	// Read the index for the repository to get chart information and return chart URL
+	repoIndex, err := r.LoadIndex()
 	if err != nil {
 		return "", err
 	}
@@ -353,25 +353,8 @@ func loadIndex(data []byte, source string) (*IndexFile, error) {
 	}
 
 	for name, cvs := range i.Entries {
-		for idx := len(cvs) - 1; idx >= 0; idx-- {
-			if cvs[idx] == nil {
-				slog.Warn("skipping loading invalid entry for chart %q from %s: empty entry", name, source)
-				continue
-			}
-			// When metadata section missing, initialize with no data
-			if cvs[idx].Metadata == nil {
-				cvs[idx].Metadata = &chart.Metadata{}
-			}
-			if cvs[idx].APIVersion == "" {
-				cvs[idx].APIVersion = chart.APIVersionV1
-			}
-			if err := cvs[idx].Validate(); ignoreSkippableChartValidationError(err) != nil {
-				slog.Warn("skipping loading invalid entry for chart %q %q from %s: %s", name, cvs[idx].Version, source, err)
-				cvs = append(cvs[:idx], cvs[idx+1:]...)
-			}
-		}
 		// adjust slice to only contain a set of valid versions
-		i.Entries[name] = cvs
+		i.Entries[name] = validChartVersions(name, cvs, source)
 	}
 	i.SortEntries()
 	if i.APIVersion == "" {
@@ -380,6 +363,31 @@ func loadIndex(data []byte, source string) (*IndexFile, error) {
 	return i, nil
 }
 
+// validChartVersions returns the valid versions of a chart in an index,
+// skipping the invalid ones.
+//
+// The source parameter is only used for logging.
+func validChartVersions(name string, cvs ChartVersions, source string) ChartVersions {
+	for idx := len(cvs) - 1; idx >= 0; idx-- {
+		if cvs[idx] == nil {
+			slog.Warn("skipping loading invalid entry for chart %q from %s: empty entry", name, source)
+			continue
+		}
+		// When metadata section missing, initialize with no data
+		if cvs[idx].Metadata == nil {
+			cvs[idx].Metadata = &chart.Metadata{}
+		}
+		if cvs[idx].APIVersion == "" {
+			cvs[idx].APIVersion = chart.APIVersionV1
+		}
+		if err := cvs[idx].Validate(); ignoreSkippableChartValidationError(err) != nil {
+			slog.Warn("skipping loading invalid entry for chart %q %q from %s: %s", name, cvs[idx].Version, source, err)
+			cvs = append(cvs[:idx], cvs[idx+1:]...)
+		}
+	}
+	return cvs
+}
+
 // jsonOrYamlUnmarshal unmarshals the given byte slice containing JSON or YAML
 // into the provided interface.
 //
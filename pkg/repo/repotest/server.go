@@ -18,6 +18,7 @@ package repotest
 import (
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -183,7 +184,12 @@ func NewOCIServer(t *testing.T, dir string) (*OCIServer, error) {
 
 	config.HTTP.Addr = fmt.Sprintf("127.0.0.1:%d", port)
 	config.HTTP.DrainTimeout = time.Duration(10) * time.Second
-	config.Storage = map[string]configuration.Parameters{"inmemory": map[string]interface{}{}}
+	config.Storage = map[string]configuration.Parameters{
+		"inmemory": map[string]interface{}{},
+		"delete":   map[string]interface{}{"enabled": true},
+	}
+	// The catalog API lists no repositories unless its size is configured.
+	config.Catalog.MaxEntries = 1000
 	config.Auth = configuration.Auth{
 		"htpasswd": configuration.Parameters{
 			"realm": "localhost",
@@ -215,6 +221,15 @@ func (srv *OCIServer) Run(t *testing.T, opts ...OCIServerOpt) {
 	}
 
 	go srv.ListenAndServe()
+	// Wait for the registry to listen, since logging in does not retry.
+	for i := 0; i < 50; i++ {
+		conn, err := net.Dial("tcp", srv.RegistryURL)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
 
 	credentialsFile := filepath.Join(srv.Dir, "config.json")
 
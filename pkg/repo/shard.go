@@ -0,0 +1,343 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"helm.sh/helm/v4/internal/fileutil"
+	"helm.sh/helm/v4/pkg/provenance"
+)
+
+// ShardedIndexDir is the directory of the sharded index of a chart
+// repository, next to its index.yaml.
+const ShardedIndexDir = "index.d"
+
+// ErrNoShardedIndex indicates that a file is not the root of a sharded index.
+var ErrNoShardedIndex = errors.New("not a sharded index")
+
+// ShardedIndexFile is the root of a sharded index of a chart repository.
+//
+// It only lists the charts of the repository: the versions of each chart are
+// in a file of their own, so that clients only download the versions of the
+// charts they need.
+type ShardedIndexFile struct {
+	APIVersion string                 `json:"apiVersion"`
+	Generated  time.Time              `json:"generated"`
+	Charts     map[string]*ChartShard `json:"charts"`
+	PublicKeys []string               `json:"publicKeys,omitempty"`
+
+	// Annotations are additional mappings uninterpreted by Helm. They are made available for
+	// other applications to add information to the index file.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ChartShard references the file of the versions of a chart in a sharded
+// index.
+type ChartShard struct {
+	// Path is the path of the file, relative to the root of the index.
+	Path string `json:"path"`
+	// Digest is the digest of the file.
+	Digest string `json:"digest"`
+	// Latest is the most recent version of the chart, so that charts can be
+	// searched without downloading their versions.
+	Latest *ChartVersion `json:"latest,omitempty"`
+}
+
+// ChartShardFile is the file of the versions of a chart in a sharded index.
+type ChartShardFile struct {
+	APIVersion string        `json:"apiVersion"`
+	Name       string        `json:"name"`
+	Versions   ChartVersions `json:"versions"`
+}
+
+// WriteShardedIndex writes the index as a sharded index in the given
+// directory: the root of the index to index.yaml, and the versions of each
+// chart to charts/<name>.yaml. The files of charts that are no longer in the
+// index are removed.
+//
+// The entries of the index are sorted, and the mode on the files is set to
+// 'mode'.
+func (i IndexFile) WriteShardedIndex(dir string, mode os.FileMode) error {
+	i.SortEntries()
+	root := &ShardedIndexFile{
+		APIVersion:  APIVersionV1,
+		Generated:   i.Generated,
+		Charts:      map[string]*ChartShard{},
+		PublicKeys:  i.PublicKeys,
+		Annotations: i.Annotations,
+	}
+
+	chartsDir := filepath.Join(dir, "charts")
+	if err := os.MkdirAll(chartsDir, 0755); err != nil {
+		return err
+	}
+	for name, cvs := range i.Entries {
+		if len(cvs) == 0 {
+			continue
+		}
+		if name == "" || strings.ContainsAny(name, `/\`) || !filepath.IsLocal(name) {
+			return fmt.Errorf("invalid chart name %q", name)
+		}
+		b, err := yaml.Marshal(&ChartShardFile{
+			APIVersion: APIVersionV1,
+			Name:       name,
+			Versions:   cvs,
+		})
+		if err != nil {
+			return err
+		}
+		p := path.Join("charts", name+".yaml")
+		if err := fileutil.AtomicWriteFile(filepath.Join(dir, filepath.FromSlash(p)), bytes.NewReader(b), mode); err != nil {
+			return err
+		}
+		digest, err := shardDigest(b)
+		if err != nil {
+			return err
+		}
+		root.Charts[name] = &ChartShard{
+			Path:   p,
+			Digest: digest,
+			Latest: cvs[0],
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(chartsDir, "*.yaml"))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if _, ok := root.Charts[strings.TrimSuffix(filepath.Base(f), ".yaml")]; !ok {
+			if err := os.Remove(f); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The root is written last, so that it never references a missing file.
+	b, err := yaml.Marshal(root)
+	if err != nil {
+		return err
+	}
+	return fileutil.AtomicWriteFile(filepath.Join(dir, "index.yaml"), bytes.NewReader(b), mode)
+}
+
+// shardDigest returns the digest of the file of the versions of a chart.
+func shardDigest(data []byte) (string, error) {
+	digest, err := provenance.Digest(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + digest, nil
+}
+
+// loadShardedIndex loads the root of a sharded index and does minimal
+// validity checking.
+//
+// The source parameter is only used for logging.
+// This will fail with ErrNoShardedIndex if the data is not the root of a
+// sharded index, e.g. if it is a classic index file.
+func loadShardedIndex(data []byte, source string) (*ShardedIndexFile, error) {
+	i := &ShardedIndexFile{}
+
+	if len(data) == 0 {
+		return nil, ErrEmptyIndexYaml
+	}
+
+	if err := jsonOrYamlUnmarshal(data, i); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoShardedIndex, err)
+	}
+	if i.Charts == nil {
+		return nil, ErrNoShardedIndex
+	}
+	if i.APIVersion == "" {
+		return nil, ErrNoAPIVersion
+	}
+
+	for name, shard := range i.Charts {
+		if shard == nil || shard.Digest == "" || !filepath.IsLocal(filepath.FromSlash(shard.Path)) {
+			slog.Warn("skipping loading invalid entry for chart", "chart", name, "source", source)
+			delete(i.Charts, name)
+			continue
+		}
+		if shard.Latest != nil && len(validChartVersions(name, ChartVersions{shard.Latest}, source)) == 0 {
+			shard.Latest = nil
+		}
+	}
+	return i, nil
+}
+
+// loadChartShard loads the file of the versions of a chart in a sharded
+// index, checking it against the digest of the root of the index.
+//
+// The source parameter is only used for logging.
+func loadChartShard(data []byte, name string, shard *ChartShard, source string) (ChartVersions, error) {
+	digest, err := shardDigest(data)
+	if err != nil {
+		return nil, err
+	}
+	if digest != shard.Digest {
+		return nil, fmt.Errorf("the digest of %s is %s, expected %s", source, digest, shard.Digest)
+	}
+
+	f := &ChartShardFile{}
+	if err := jsonOrYamlUnmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("error loading %s: %w", source, err)
+	}
+	if f.Name != name {
+		return nil, fmt.Errorf("error loading %s: expected the versions of chart %q, got %q", source, name, f.Name)
+	}
+	cvs := validChartVersions(name, f.Versions, source)
+	sort.Sort(sort.Reverse(cvs))
+	return cvs, nil
+}
+
+// Index is the index of a chart repository, loaded from either its index
+// file or its sharded index.
+//
+// The versions of the charts in a sharded index are only loaded when they are
+// first needed.
+type Index struct {
+	file   *IndexFile
+	root   *ShardedIndexFile
+	load   func(name string, shard *ChartShard) (ChartVersions, error)
+	shards map[string]ChartVersions
+}
+
+// NewIndex returns the index of a chart repository loaded from its index
+// file.
+func NewIndex(f *IndexFile) *Index {
+	return &Index{file: f}
+}
+
+// NewShardedIndex returns the index of a chart repository loaded from the
+// root of its sharded index. The versions of each chart are loaded with the
+// given function when they are first needed.
+func NewShardedIndex(root *ShardedIndexFile, load func(name string, shard *ChartShard) (ChartVersions, error)) *Index {
+	return &Index{
+		root:   root,
+		load:   load,
+		shards: map[string]ChartVersions{},
+	}
+}
+
+// Sharded returns true if the index was loaded from a sharded index.
+func (i *Index) Sharded() bool {
+	return i.root != nil
+}
+
+// ChartNames returns the sorted names of the charts in the index.
+func (i *Index) ChartNames() []string {
+	var names []string
+	if i.root != nil {
+		for name := range i.root.Charts {
+			names = append(names, name)
+		}
+	} else {
+		for name := range i.file.Entries {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Versions returns the versions of a chart, the most recent first.
+func (i *Index) Versions(name string) (ChartVersions, error) {
+	if i.root == nil {
+		cvs, ok := i.file.Entries[name]
+		if !ok {
+			return nil, ErrNoChartName
+		}
+		return cvs, nil
+	}
+
+	if cvs, ok := i.shards[name]; ok {
+		return cvs, nil
+	}
+	shard, ok := i.root.Charts[name]
+	if !ok {
+		return nil, ErrNoChartName
+	}
+	cvs, err := i.load(name, shard)
+	if err != nil {
+		return nil, err
+	}
+	i.shards[name] = cvs
+	return cvs, nil
+}
+
+// Get returns the ChartVersion for the given name, as IndexFile.Get does.
+func (i *Index) Get(name, version string) (*ChartVersion, error) {
+	cvs, err := i.Versions(name)
+	if err != nil {
+		return nil, err
+	}
+	return IndexFile{Entries: map[string]ChartVersions{name: cvs}}.Get(name, version)
+}
+
+// Latest returns an index file with only the most recent version of each
+// chart. Unlike IndexFile, it does not load the versions of the charts in a
+// sharded index.
+func (i *Index) Latest() *IndexFile {
+	f := NewIndexFile()
+	if i.root == nil {
+		for name, cvs := range i.file.Entries {
+			if len(cvs) > 0 {
+				f.Entries[name] = ChartVersions{cvs[0]}
+			}
+		}
+		return f
+	}
+	for name, shard := range i.root.Charts {
+		if shard.Latest != nil {
+			f.Entries[name] = ChartVersions{shard.Latest}
+		}
+	}
+	return f
+}
+
+// IndexFile returns an index file with all the versions of the charts in the
+// index, loading them if needed.
+func (i *Index) IndexFile() (*IndexFile, error) {
+	if i.root == nil {
+		return i.file, nil
+	}
+	f := NewIndexFile()
+	f.Generated = i.root.Generated
+	f.PublicKeys = i.root.PublicKeys
+	f.Annotations = i.root.Annotations
+	for _, name := range i.ChartNames() {
+		cvs, err := i.Versions(name)
+		if err != nil {
+			return nil, err
+		}
+		f.Entries[name] = cvs
+	}
+	return f, nil
+}
@@ -0,0 +1,241 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/cli"
+	"helm.sh/helm/v4/pkg/getter"
+)
+
+func TestWriteShardedIndex(t *testing.T) {
+	i, err := LoadIndexFile("testdata/local-index.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := i.WriteShardedIndex(dir, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "index.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := loadShardedIndex(data, "index.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(root.Charts) != len(i.Entries) {
+		t.Fatalf("expected %d charts, got %d", len(i.Entries), len(root.Charts))
+	}
+	shard := root.Charts["nginx"]
+	if shard.Path != "charts/nginx.yaml" {
+		t.Errorf("expected path charts/nginx.yaml, got %s", shard.Path)
+	}
+	if shard.Latest == nil || shard.Latest.Version != "0.2.0" {
+		t.Errorf("expected latest version 0.2.0, got %v", shard.Latest)
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "charts", "nginx.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cvs, err := loadChartShard(data, "nginx", shard, "nginx.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cvs) != 2 || cvs[0].Version != "0.2.0" || cvs[1].Version != "0.1.0" {
+		t.Errorf("unexpected versions %v", cvs)
+	}
+	if _, err := loadChartShard(data, "alpine", shard, "nginx.yaml"); err == nil {
+		t.Error("expected an error loading the versions of another chart")
+	}
+	if _, err := loadChartShard(append(data, '\n'), "nginx", shard, "nginx.yaml"); err == nil {
+		t.Error("expected an error loading modified versions")
+	}
+
+	// The files of removed charts are removed.
+	delete(i.Entries, "nginx")
+	if err := i.WriteShardedIndex(dir, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "charts", "nginx.yaml")); !os.IsNotExist(err) {
+		t.Errorf("expected the versions of nginx to be removed, got %v", err)
+	}
+
+	// A classic index is not a sharded index.
+	data, err = os.ReadFile("testdata/local-index.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadShardedIndex(data, "local-index.yaml"); err == nil {
+		t.Error("expected an error loading an index file as a sharded index")
+	}
+}
+
+func TestShardedIndexDownload(t *testing.T) {
+	i, err := LoadIndexFile("testdata/local-index.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := i.WriteShardedIndex(filepath.Join(dir, ShardedIndexDir), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	requests := map[string]int{}
+	fs := http.FileServer(http.Dir(dir))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		fs.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	cachePath := t.TempDir()
+	newRepo := func() *ChartRepository {
+		r, err := NewChartRepository(&Entry{Name: "sharded", URL: srv.URL}, getter.All(&cli.EnvSettings{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.CachePath = cachePath
+		return r
+	}
+
+	r := newRepo()
+	if err := r.DownloadIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if requests["/index.yaml"] != 0 {
+		t.Error("expected the index file not to be downloaded")
+	}
+	index, err := r.LoadIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !index.Sharded() {
+		t.Fatal("expected a sharded index")
+	}
+	if latest := index.Latest().Entries["nginx"]; len(latest) != 1 || latest[0].Version != "0.2.0" {
+		t.Errorf("unexpected latest versions %v", latest)
+	}
+	if requests["/index.d/charts/nginx.yaml"] != 0 {
+		t.Error("expected the versions of nginx not to be downloaded before they are needed")
+	}
+
+	cv, err := index.Get("nginx", "0.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cv.URLs[0] != "https://charts.helm.sh/stable/nginx-0.1.0.tgz" {
+		t.Errorf("unexpected URL %s", cv.URLs[0])
+	}
+	if _, err := index.Get("alpine", ""); err != nil {
+		t.Fatal(err)
+	}
+	if requests["/index.d/charts/nginx.yaml"] != 1 || requests["/index.d/charts/alpine.yaml"] != 1 {
+		t.Errorf("expected the versions of nginx and alpine to be downloaded once, got %v", requests)
+	}
+
+	// The cached versions are reused.
+	index, err = newRepo().LoadIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := index.Get("nginx", "0.2.0"); err != nil {
+		t.Fatal(err)
+	}
+	if requests["/index.d/charts/nginx.yaml"] != 1 {
+		t.Errorf("expected the cached versions of nginx to be reused, got %d requests", requests["/index.d/charts/nginx.yaml"])
+	}
+}
+
+func TestShardedIndexDownloadFallback(t *testing.T) {
+	data, err := os.ReadFile("testdata/local-index.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, handler := range map[string]http.HandlerFunc{
+		"missing sharded index": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/index.yaml" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(data)
+		},
+		// Every path returns the index file, which is not a sharded index.
+		"invalid sharded index": func(w http.ResponseWriter, _ *http.Request) {
+			w.Write(data)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(handler)
+			defer srv.Close()
+
+			r, err := NewChartRepository(&Entry{Name: "classic", URL: srv.URL}, getter.All(&cli.EnvSettings{}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.CachePath = t.TempDir()
+			if err := r.DownloadIndex(); err != nil {
+				t.Fatal(err)
+			}
+			index, err := r.LoadIndex()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if index.Sharded() {
+				t.Error("expected the index file to be used")
+			}
+			if _, err := index.Get("nginx", "0.1.0"); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestFindChartInShardedRepoURL(t *testing.T) {
+	i, err := LoadIndexFile("testdata/local-index.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := i.WriteShardedIndex(filepath.Join(dir, ShardedIndexDir), 0644); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer srv.Close()
+
+	g := getter.All(&cli.EnvSettings{})
+	chartURL, err := FindChartInRepoURL(srv.URL, "nginx", g, WithChartVersion("0.1.0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chartURL != "https://charts.helm.sh/stable/nginx-0.1.0.tgz" {
+		t.Errorf("unexpected URL %s", chartURL)
+	}
+}
@@ -0,0 +1,405 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package sbom generates software bills of materials for chart archives.
+
+An SBOM describes a chart, the dependencies it was packaged with, and the
+container images its templates reference. It is stored next to the chart
+archive, e.g. mychart-0.1.0.spdx.json for mychart-0.1.0.tgz.
+*/
+package sbom // import "helm.sh/helm/v4/pkg/sbom"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v4/internal/version"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+// Format is the format of an SBOM.
+type Format string
+
+const (
+	// SPDX is the SPDX 2.3 JSON format.
+	SPDX Format = "spdx"
+	// CycloneDX is the CycloneDX 1.5 JSON format.
+	CycloneDX Format = "cyclonedx"
+)
+
+// Formats returns the supported formats.
+func Formats() []Format {
+	return []Format{SPDX, CycloneDX}
+}
+
+// ParseFormat parses the name of a format.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats() {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown SBOM format %q, must be one of %s or %s", s, SPDX, CycloneDX)
+}
+
+func (f Format) String() string { return string(f) }
+
+// Extension returns the file extension of SBOMs in the format.
+func (f Format) Extension() string {
+	if f == CycloneDX {
+		return ".cdx.json"
+	}
+	return ".spdx.json"
+}
+
+// MediaType returns the media type of SBOMs in the format.
+func (f Format) MediaType() string {
+	if f == CycloneDX {
+		return "application/vnd.cyclonedx+json"
+	}
+	return "application/spdx+json"
+}
+
+// Filename returns the name of the SBOM file of a chart archive.
+func Filename(archive string, f Format) string {
+	return strings.TrimSuffix(archive, ".tgz") + f.Extension()
+}
+
+// Find returns the SBOM file stored next to a chart archive, if there is one.
+func Find(archive string) (string, Format, bool) {
+	for _, f := range Formats() {
+		filename := Filename(archive, f)
+		if _, err := os.Stat(filename); err == nil {
+			return filename, f, true
+		}
+	}
+	return "", "", false
+}
+
+// Verify checks that an SBOM describes the chart archive with the given
+// digest, e.g. "sha256:...".
+func Verify(data []byte, f Format, digest string) error {
+	_, want, ok := splitDigest(digest)
+	if !ok {
+		return fmt.Errorf("unsupported digest %q", digest)
+	}
+	var got string
+	switch f {
+	case SPDX:
+		var doc spdxDoc
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("invalid SPDX document: %w", err)
+		}
+		for _, pkg := range doc.Packages {
+			if pkg.SPDXID != "SPDXRef-Chart" {
+				continue
+			}
+			for _, c := range pkg.Checksums {
+				if c.Algorithm == "SHA256" {
+					got = c.ChecksumValue
+				}
+			}
+		}
+	case CycloneDX:
+		var doc cdxDoc
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("invalid CycloneDX document: %w", err)
+		}
+		for _, h := range doc.Metadata.Component.Hashes {
+			if h.Alg == "SHA-256" {
+				got = h.Content
+			}
+		}
+	default:
+		return fmt.Errorf("unknown SBOM format %q", f)
+	}
+	if got == "" {
+		return errors.New("the SBOM does not record the digest of the chart")
+	}
+	if got != want {
+		return fmt.Errorf("the SBOM describes the chart with digest sha256:%s, not %s", got, digest)
+	}
+	return nil
+}
+
+// Input is what an SBOM describes.
+type Input struct {
+	// Chart is the packaged chart.
+	Chart *chart.Chart
+	// Digest is the digest of the chart archive, e.g. "sha256:...".
+	Digest string
+	// Images are the container images referenced by the chart.
+	Images []string
+	// Created is the time the SBOM is created.
+	Created time.Time
+}
+
+// Generate generates an SBOM in the given format.
+func Generate(f Format, in Input) ([]byte, error) {
+	var doc interface{}
+	switch f {
+	case SPDX:
+		doc = spdxDocument(in)
+	case CycloneDX:
+		doc = cycloneDXDocument(in)
+	default:
+		return nil, fmt.Errorf("unknown SBOM format %q", f)
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// dependencies returns the dependencies the chart was packaged with. Locked
+// dependencies carry their digests.
+func dependencies(c *chart.Chart) []*chart.Dependency {
+	if c.Lock != nil {
+		return c.Lock.Dependencies
+	}
+	var deps []*chart.Dependency
+	for _, d := range c.Metadata.Dependencies {
+		dep := *d
+		// Use the version of the packaged chart rather than the constraint.
+		for _, sub := range c.Dependencies() {
+			if sub.Name() == d.Name {
+				dep.Version = sub.Metadata.Version
+			}
+		}
+		deps = append(deps, &dep)
+	}
+	return deps
+}
+
+// purl returns the package URL of a chart.
+func purl(name, version, repository string) string {
+	p := fmt.Sprintf("pkg:helm/%s@%s", url.PathEscape(name), url.PathEscape(version))
+	if strings.HasPrefix(repository, "http://") || strings.HasPrefix(repository, "https://") || strings.HasPrefix(repository, "oci://") {
+		p += "?repository_url=" + url.QueryEscape(repository)
+	}
+	return p
+}
+
+// splitDigest splits a digest like "sha256:..." into its algorithm and value.
+func splitDigest(digest string) (string, string, bool) {
+	alg, value, ok := strings.Cut(digest, ":")
+	if !ok || alg != "sha256" {
+		return "", "", false
+	}
+	return alg, value, true
+}
+
+func toolName() string {
+	return "helm-" + version.GetVersion()
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxPackage struct {
+	Name                  string            `json:"name"`
+	SPDXID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	Checksums             []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+type spdxDoc struct {
+	SPDXVersion       string `json:"spdxVersion"`
+	DataLicense       string `json:"dataLicense"`
+	SPDXID            string `json:"SPDXID"`
+	Name              string `json:"name"`
+	DocumentNamespace string `json:"documentNamespace"`
+	CreationInfo      struct {
+		Created  string   `json:"created"`
+		Creators []string `json:"creators"`
+	} `json:"creationInfo"`
+	Packages      []spdxPackage      `json:"packages"`
+	Relationships []spdxRelationship `json:"relationships"`
+}
+
+// spdxID turns a name into a valid SPDX identifier.
+func spdxID(kind string, i int, name string) string {
+	id := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '-'
+	}, name)
+	return fmt.Sprintf("SPDXRef-%s-%d-%s", kind, i, id)
+}
+
+func spdxDocument(in Input) spdxDoc {
+	c := in.Chart
+	name := fmt.Sprintf("%s-%s", c.Name(), c.Metadata.Version)
+	doc := spdxDoc{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              name,
+		DocumentNamespace: fmt.Sprintf("https://helm.sh/spdx/%s/%s", url.PathEscape(name), strings.TrimPrefix(in.Digest, "sha256:")),
+	}
+	doc.CreationInfo.Created = in.Created.UTC().Format(time.RFC3339)
+	doc.CreationInfo.Creators = []string{"Tool: " + toolName()}
+
+	chartPkg := spdxPackage{
+		Name:                  c.Name(),
+		SPDXID:                "SPDXRef-Chart",
+		VersionInfo:           c.Metadata.Version,
+		DownloadLocation:      "NOASSERTION",
+		ExternalRefs:          []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: purl(c.Name(), c.Metadata.Version, "")}},
+		PrimaryPackagePurpose: "APPLICATION",
+	}
+	if _, value, ok := splitDigest(in.Digest); ok {
+		chartPkg.Checksums = []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: value}}
+	}
+	doc.Packages = append(doc.Packages, chartPkg)
+	doc.Relationships = append(doc.Relationships, spdxRelationship{"SPDXRef-DOCUMENT", "DESCRIBES", chartPkg.SPDXID})
+
+	for i, d := range dependencies(c) {
+		pkg := spdxPackage{
+			Name:             d.Name,
+			SPDXID:           spdxID("Dependency", i, d.Name),
+			VersionInfo:      d.Version,
+			DownloadLocation: "NOASSERTION",
+			ExternalRefs:     []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: purl(d.Name, d.Version, d.Repository)}},
+		}
+		if d.Repository != "" {
+			pkg.DownloadLocation = d.Repository
+		}
+		if _, value, ok := splitDigest(d.Digest); ok {
+			pkg.Checksums = []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: value}}
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{chartPkg.SPDXID, "DEPENDS_ON", pkg.SPDXID})
+	}
+
+	for i, image := range in.Images {
+		pkg := spdxPackage{
+			Name:                  image,
+			SPDXID:                spdxID("Image", i, image),
+			DownloadLocation:      "NOASSERTION",
+			PrimaryPackagePurpose: "CONTAINER",
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{chartPkg.SPDXID, "DEPENDS_ON", pkg.SPDXID})
+	}
+	return doc
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cdxComponent struct {
+	Type       string        `json:"type"`
+	BOMRef     string        `json:"bom-ref"`
+	Name       string        `json:"name"`
+	Version    string        `json:"version,omitempty"`
+	Hashes     []cdxHash     `json:"hashes,omitempty"`
+	Purl       string        `json:"purl,omitempty"`
+	Properties []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+type cdxDoc struct {
+	BOMFormat   string `json:"bomFormat"`
+	SpecVersion string `json:"specVersion"`
+	Version     int    `json:"version"`
+	Metadata    struct {
+		Timestamp string `json:"timestamp"`
+		Tools     struct {
+			Components []cdxComponent `json:"components"`
+		} `json:"tools"`
+		Component cdxComponent `json:"component"`
+	} `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+func cycloneDXDocument(in Input) cdxDoc {
+	c := in.Chart
+	doc := cdxDoc{BOMFormat: "CycloneDX", SpecVersion: "1.5", Version: 1}
+	doc.Metadata.Timestamp = in.Created.UTC().Format(time.RFC3339)
+	doc.Metadata.Tools.Components = []cdxComponent{{Type: "application", BOMRef: toolName(), Name: "helm", Version: version.GetVersion()}}
+
+	chartPurl := purl(c.Name(), c.Metadata.Version, "")
+	doc.Metadata.Component = cdxComponent{
+		Type:    "application",
+		BOMRef:  chartPurl,
+		Name:    c.Name(),
+		Version: c.Metadata.Version,
+		Purl:    chartPurl,
+	}
+	if _, value, ok := splitDigest(in.Digest); ok {
+		doc.Metadata.Component.Hashes = []cdxHash{{Alg: "SHA-256", Content: value}}
+	}
+
+	root := cdxDependency{Ref: chartPurl}
+	doc.Components = []cdxComponent{}
+	for _, d := range dependencies(c) {
+		p := purl(d.Name, d.Version, d.Repository)
+		comp := cdxComponent{Type: "application", BOMRef: p, Name: d.Name, Version: d.Version, Purl: p}
+		if _, value, ok := splitDigest(d.Digest); ok {
+			comp.Hashes = []cdxHash{{Alg: "SHA-256", Content: value}}
+		}
+		if d.Repository != "" {
+			comp.Properties = append(comp.Properties, cdxProperty{Name: "helm:repository", Value: d.Repository})
+		}
+		if d.Commit != "" {
+			comp.Properties = append(comp.Properties, cdxProperty{Name: "helm:commit", Value: d.Commit})
+		}
+		doc.Components = append(doc.Components, comp)
+		root.DependsOn = append(root.DependsOn, p)
+	}
+	for _, image := range in.Images {
+		comp := cdxComponent{Type: "container", BOMRef: "image:" + image, Name: image}
+		doc.Components = append(doc.Components, comp)
+		root.DependsOn = append(root.DependsOn, comp.BOMRef)
+	}
+	doc.Dependencies = []cdxDependency{root}
+	return doc
+}
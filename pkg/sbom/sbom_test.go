@@ -0,0 +1,166 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sbom
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+func testInput() Input {
+	return Input{
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{
+				Name:    "moby",
+				Version: "1.2.3",
+				Dependencies: []*chart.Dependency{
+					{Name: "whale", Version: "^1.0.0", Repository: "https://example.com/charts"},
+				},
+			},
+			Lock: &chart.Lock{
+				Dependencies: []*chart.Dependency{
+					{Name: "whale", Version: "1.0.1", Repository: "https://example.com/charts", Digest: "sha256:abc123"},
+					{Name: "harpoon", Version: "0.1.0", Repository: "git+https://example.com/harpoon.git", Commit: "deadbeef"},
+				},
+			},
+		},
+		Digest:  "sha256:0123456789",
+		Images:  []string{"alpine:3.20", "nginx:1.27"},
+		Created: time.Date(2020, 5, 4, 3, 2, 1, 0, time.UTC),
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("cyclonedx")
+	require.NoError(t, err)
+	assert.Equal(t, CycloneDX, f)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestFilename(t *testing.T) {
+	assert.Equal(t, "dir/moby-1.2.3.spdx.json", Filename("dir/moby-1.2.3.tgz", SPDX))
+	assert.Equal(t, "dir/moby-1.2.3.cdx.json", Filename("dir/moby-1.2.3.tgz", CycloneDX))
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "moby-1.2.3.tgz")
+
+	_, _, ok := Find(archive)
+	assert.False(t, ok)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "moby-1.2.3.cdx.json"), []byte("{}"), 0644))
+	filename, f, ok := Find(archive)
+	assert.True(t, ok)
+	assert.Equal(t, CycloneDX, f)
+	assert.Equal(t, filepath.Join(dir, "moby-1.2.3.cdx.json"), filename)
+}
+
+func TestVerify(t *testing.T) {
+	for _, f := range Formats() {
+		data, err := Generate(f, testInput())
+		require.NoError(t, err)
+		assert.NoError(t, Verify(data, f, "sha256:0123456789"), f)
+		assert.ErrorContains(t, Verify(data, f, "sha256:abcdef"), "not sha256:abcdef", f)
+	}
+
+	in := testInput()
+	in.Digest = ""
+	data, err := Generate(SPDX, in)
+	require.NoError(t, err)
+	assert.ErrorContains(t, Verify(data, SPDX, "sha256:0123456789"), "does not record the digest")
+	assert.Error(t, Verify([]byte("not json"), CycloneDX, "sha256:0123456789"))
+}
+
+func TestGenerateSPDX(t *testing.T) {
+	data, err := Generate(SPDX, testInput())
+	require.NoError(t, err)
+
+	var doc spdxDoc
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	assert.Equal(t, "moby-1.2.3", doc.Name)
+	assert.Equal(t, "2020-05-04T03:02:01Z", doc.CreationInfo.Created)
+	assert.Equal(t, "https://helm.sh/spdx/moby-1.2.3/0123456789", doc.DocumentNamespace)
+
+	require.Len(t, doc.Packages, 5)
+	assert.Equal(t, "moby", doc.Packages[0].Name)
+	assert.Equal(t, []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: "0123456789"}}, doc.Packages[0].Checksums)
+
+	whale := doc.Packages[1]
+	assert.Equal(t, "whale", whale.Name)
+	assert.Equal(t, "1.0.1", whale.VersionInfo)
+	assert.Equal(t, "https://example.com/charts", whale.DownloadLocation)
+	assert.Equal(t, []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: "abc123"}}, whale.Checksums)
+	assert.Equal(t, "pkg:helm/whale@1.0.1?repository_url=https%3A%2F%2Fexample.com%2Fcharts", whale.ExternalRefs[0].ReferenceLocator)
+
+	assert.Equal(t, "alpine:3.20", doc.Packages[3].Name)
+	assert.Equal(t, "SPDXRef-Image-0-alpine-3.20", doc.Packages[3].SPDXID)
+	assert.Equal(t, "CONTAINER", doc.Packages[3].PrimaryPackagePurpose)
+
+	require.Len(t, doc.Relationships, 5)
+	assert.Equal(t, spdxRelationship{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-Chart"}, doc.Relationships[0])
+	assert.Equal(t, spdxRelationship{"SPDXRef-Chart", "DEPENDS_ON", "SPDXRef-Image-1-nginx-1.27"}, doc.Relationships[4])
+}
+
+func TestGenerateCycloneDX(t *testing.T) {
+	data, err := Generate(CycloneDX, testInput())
+	require.NoError(t, err)
+
+	var doc cdxDoc
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "CycloneDX", doc.BOMFormat)
+	assert.Equal(t, "1.5", doc.SpecVersion)
+	assert.Equal(t, "pkg:helm/moby@1.2.3", doc.Metadata.Component.Purl)
+	assert.Equal(t, []cdxHash{{Alg: "SHA-256", Content: "0123456789"}}, doc.Metadata.Component.Hashes)
+
+	require.Len(t, doc.Components, 4)
+	assert.Equal(t, "whale", doc.Components[0].Name)
+	assert.Equal(t, []cdxHash{{Alg: "SHA-256", Content: "abc123"}}, doc.Components[0].Hashes)
+	assert.Equal(t, "pkg:helm/harpoon@0.1.0", doc.Components[1].Purl)
+	assert.Contains(t, doc.Components[1].Properties, cdxProperty{Name: "helm:commit", Value: "deadbeef"})
+	assert.Equal(t, "container", doc.Components[2].Type)
+	assert.Equal(t, "alpine:3.20", doc.Components[2].Name)
+
+	require.Len(t, doc.Dependencies, 1)
+	assert.Equal(t, "pkg:helm/moby@1.2.3", doc.Dependencies[0].Ref)
+	assert.Len(t, doc.Dependencies[0].DependsOn, 4)
+}
+
+func TestGenerateUnlocked(t *testing.T) {
+	in := testInput()
+	in.Chart.Lock = nil
+	in.Chart.AddDependency(&chart.Chart{Metadata: &chart.Metadata{Name: "whale", Version: "1.0.7"}})
+
+	data, err := Generate(SPDX, in)
+	require.NoError(t, err)
+	var doc spdxDoc
+	require.NoError(t, json.Unmarshal(data, &doc))
+	require.Len(t, doc.Packages, 4)
+	assert.Equal(t, "whale", doc.Packages[1].Name)
+	assert.Equal(t, "1.0.7", doc.Packages[1].VersionInfo)
+}